@@ -1,54 +1,255 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/changes"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/health"
+	"github.com/tsujio/x-base/api/idempotency"
+	"github.com/tsujio/x-base/api/jobs"
+	"github.com/tsujio/x-base/api/metrics"
+	"github.com/tsujio/x-base/api/quotas"
+	"github.com/tsujio/x-base/api/ratelimit"
+	"github.com/tsujio/x-base/api/replicas"
 	"github.com/tsujio/x-base/api/routes"
+	"github.com/tsujio/x-base/api/timeout"
+	"github.com/tsujio/x-base/api/webhooks"
 	"github.com/tsujio/x-base/logging"
 )
 
-func CreateRouter(db *gorm.DB) http.Handler {
+// server holds the router and the components stopped on shutdown.
+type server struct {
+	handler    http.Handler
+	recorder   *changes.Recorder
+	runner     *jobs.Runner
+	dispatcher *webhooks.Dispatcher
+
+	stopping chan struct{}
+	wg       sync.WaitGroup
+}
+
+func CreateRouter(db *gorm.DB, conf *Config) http.Handler {
+	return newServer(db, conf).handler
+}
+
+func newServer(db *gorm.DB, conf *Config) *server {
+	if conf == nil {
+		conf = &Config{}
+	}
+
 	router := mux.NewRouter().
 		StrictSlash(true)
 
+	m := metrics.New(db)
+	router.Use(m.Middleware)
+	router.Use(recordRoute)
+	router.Use(timeout.Middleware(&conf.Timeout))
+	router.Use(auth.Middleware(db, &conf.Auth))
+	router.Use(ratelimit.Middleware(&conf.RateLimit))
+	router.Use(idempotency.Middleware(db, &conf.Idempotency))
+	router.Use(replicas.Middleware)
+	checker := quotas.NewChecker(&conf.Quota)
+
+	bus := events.NewBus()
+	dispatcher := webhooks.NewDispatcher(db, &conf.Webhook)
+	dispatcher.Subscribe(bus)
+	recorder := changes.NewRecorder(db)
+	recorder.Subscribe(bus)
+
+	runner := jobs.NewRunner(db)
+	runner.Bus = bus
+	runner.Quotas = checker
+
 	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 	})
 
+	probe := health.NewProbe(db, &conf.Health)
+	router.HandleFunc("/healthz", probe.Healthz).Methods(http.MethodGet)
+	router.HandleFunc("/readyz", probe.Readyz).Methods(http.MethodGet)
+
+	router.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		if err := auth.AuthorizeAdmin(r); err != nil {
+			auth.SendError(w, r, err)
+			return
+		}
+		m.Handler().ServeHTTP(w, r)
+	}).Methods(http.MethodGet)
+
 	// Organization routes
 	organizationRouter := router.PathPrefix("/organizations").Subrouter()
 	routes.SetOrganizationRoutes(organizationRouter, db)
 
 	// Table routes
 	tableRouter := router.PathPrefix("/tables").Subrouter()
-	routes.SetTableRoutes(tableRouter, db)
+	routes.SetTableRoutes(tableRouter, db, runner, bus, recorder, checker, m)
 
 	// Folder routes
 	folderRouter := router.PathPrefix("/folders").Subrouter()
-	routes.SetFolderRoutes(folderRouter, db)
+	routes.SetFolderRoutes(folderRouter, db, runner, bus, recorder, checker)
+
+	// Job routes
+	jobRouter := router.PathPrefix("/jobs").Subrouter()
+	routes.SetJobRoutes(jobRouter, db)
+
+	// Webhook routes
+	webhookRouter := router.PathPrefix("/webhooks").Subrouter()
+	routes.SetWebhookRoutes(webhookRouter, db, &conf.Webhook)
+
+	handler := cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{
+			http.MethodHead,
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		AllowedHeaders: []string{"*"},
+		ExposedHeaders: []string{"ETag", "Retry-After", idempotency.HeaderReplayed, logging.HeaderRequestID},
+	}).Handler(router)
+
+	return &server{
+		handler:    handler,
+		recorder:   recorder,
+		runner:     runner,
+		dispatcher: dispatcher,
+		stopping:   make(chan struct{}),
+	}
+}
+
+// recordRoute adds the path template of the matched route to the access log.
+func recordRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route := mux.CurrentRoute(r); route != nil {
+			if tmpl, err := route.GetPathTemplate(); err == nil {
+				logging.AddFields(r, logging.Fields{"route": tmpl})
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+const changeEventRetention = 7 * 24 * time.Hour
 
-	handler := cors.AllowAll().Handler(router)
+// runPeriodically calls f every interval in background until stop.
+func (s *server) runPeriodically(interval time.Duration, f func()) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			f()
+			select {
+			case <-s.stopping:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop stops the background work and waits for it. The running jobs are waited for until ctx is done.
+func (s *server) stop(ctx context.Context) {
+	close(s.stopping)
+	s.wg.Wait()
+	if err := s.runner.Stop(ctx); err != nil {
+		logging.Warning(fmt.Sprintf("%+v", err), nil)
+	}
 
-	return handler
+	// Record and dispatch the events published by the drained requests and jobs
+	s.recorder.Close()
+	s.dispatcher.Stop()
 }
 
-func Run(host string, port int, db *gorm.DB) error {
-	router := CreateRouter(db)
+func Run(host string, port int, db *gorm.DB, conf *Config) error {
+	err := auth.ValidateConfig(&conf.Auth)
+	if err != nil {
+		return xerrors.Errorf("Invalid auth config: %w", err)
+	}
 
-	addr := fmt.Sprintf("%s:%d", host, port)
+	timeout := conf.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
 
-	logging.Info(fmt.Sprintf("Listen on %s\n", addr), nil)
+	// Stop the background work before returning so that the db is not closed under it
+	s := newServer(db, conf)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		s.stop(ctx)
+		logging.Info("Shut down", nil)
+	}()
 
-	err := http.ListenAndServe(addr, router)
+	err = s.runner.Resume()
 	if err != nil {
+		return xerrors.Errorf("Failed to resume jobs: %w", err)
+	}
+	s.runner.ResumePeriodically()
+
+	err = s.dispatcher.Resume()
+	if err != nil {
+		return xerrors.Errorf("Failed to resume webhook deliveries: %w", err)
+	}
+	s.dispatcher.ResumePeriodically()
+
+	s.runPeriodically(time.Hour, func() {
+		if err := s.recorder.Prune(changeEventRetention); err != nil {
+			logging.Error(fmt.Sprintf("Failed to prune change events: %+v", err), nil)
+		}
+	})
+	s.runPeriodically(time.Hour, func() {
+		if err := idempotency.Prune(db, &conf.Idempotency); err != nil {
+			logging.Error(fmt.Sprintf("Failed to prune idempotency keys: %+v", err), nil)
+		}
+	})
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", host, port),
+		Handler: logging.Middleware(s.handler),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		logging.Info(fmt.Sprintf("Listen on %s\n", srv.Addr), nil)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
 		return xerrors.Errorf("Failed to start api: %w", err)
+	case <-ctx.Done():
+	}
+
+	// Drain in-flight requests. Event streams are ended since they never finish by themselves.
+	logging.Info("Shutting down", nil)
+	s.recorder.Stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err = srv.Shutdown(shutdownCtx)
+	if err != nil {
+		return xerrors.Errorf("Failed to shut down api: %w", err)
 	}
 
 	return nil
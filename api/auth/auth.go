@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+const (
+	ModeNone   = "none"
+	ModeAPIKey = "apikey"
+	// ModeJWT accepts JWTs issued by an identity provider in addition to api keys.
+	ModeJWT = "jwt"
+)
+
+const (
+	apiKeyPrefix        = "xb_"
+	apiKeyDisplayLength = 12
+	touchInterval       = time.Minute
+)
+
+var ErrForbidden = errors.New("Forbidden")
+
+type Config struct {
+	// Mode is ModeAPIKey, ModeJWT or ModeNone. Authentication is disabled if empty.
+	Mode        string
+	AdminAPIKey string
+	// JWT is used in ModeJWT.
+	JWT JWTConfig
+}
+
+// Principal is the authenticated client of a request.
+type Principal struct {
+	Admin          bool
+	OrganizationID *models.UUID
+	APIKeyID       *models.UUID
+	// Roles maps resource ids to the granted roles. The nil uuid is the organization.
+	Roles map[models.UUID]Role
+	// Attributes are the properties of the api key or the claims of the JWT, referred by table policies.
+	Attributes map[string]interface{}
+}
+
+// ClientID identifies the api key or the JWT subject of the principal.
+// It is empty if the principal has neither, e.g. administrators.
+func (p *Principal) ClientID() string {
+	if p.APIKeyID != nil {
+		return "apikey:" + p.APIKeyID.String()
+	}
+	if sub, ok := p.Attributes["sub"].(string); ok && sub != "" && p.OrganizationID != nil {
+		return "sub:" + p.OrganizationID.String() + ":" + sub
+	}
+	return ""
+}
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(contextKey{}).(*Principal)
+	return p
+}
+
+// AuthorizeAdmin returns ErrForbidden unless the principal of the request is an administrator.
+func AuthorizeAdmin(r *http.Request) error {
+	p := FromContext(r.Context())
+	if p == nil || !p.Admin {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// GenerateAPIKey returns a new random key, its prefix shown to users and its hash to be saved.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", xerrors.Errorf("Failed to generate key: %w", err)
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key), nil
+}
+
+func HashAPIKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+// Middleware authenticates requests and puts the principal into the request context.
+func Middleware(db *gorm.DB, conf *Config) func(http.Handler) http.Handler {
+	var verifier *jwtVerifier
+	if conf != nil && conf.Mode == ModeJWT {
+		verifier = newJWTVerifier(&conf.JWT)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if conf == nil || conf.Mode == "" || conf.Mode == ModeNone {
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), &Principal{Admin: true})))
+				return
+			}
+
+			// Health check
+			if r.URL.Path == "/" || r.URL.Path == "/healthz" || r.URL.Path == "/readyz" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := bearerToken(r)
+			if key == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				responses.SendErrorResponse(w, r, http.StatusUnauthorized, "Authentication required", nil)
+				return
+			}
+
+			var principal *Principal
+			var err error
+			message := "Invalid API key"
+			if verifier != nil && !isAPIKey(conf, key) {
+				principal, err = verifier.verify(key)
+				message = "Invalid token"
+			} else {
+				principal, err = authenticateAPIKey(db.WithContext(r.Context()), conf, key)
+			}
+			if err != nil {
+				if xerrors.Is(err, errInvalidKey) {
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+					responses.SendErrorResponse(w, r, http.StatusUnauthorized, message, nil)
+					return
+				}
+				responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to authenticate", err)
+				return
+			}
+			if principal.OrganizationID != nil {
+				logging.AddFields(r, logging.Fields{"organizationId": principal.OrganizationID.String()})
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+var errInvalidKey = errors.New("Invalid key")
+
+func isAPIKey(conf *Config, key string) bool {
+	return strings.HasPrefix(key, apiKeyPrefix) || (conf.AdminAPIKey != "" && key == conf.AdminAPIKey)
+}
+
+func authenticateAPIKey(db *gorm.DB, conf *Config, key string) (*Principal, error) {
+	if conf.AdminAPIKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(conf.AdminAPIKey)) == 1 {
+		return &Principal{Admin: true}, nil
+	}
+
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, errInvalidKey
+	}
+	apiKey, err := models.GetAPIKeyByHash(db, HashAPIKey(key))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidKey
+		}
+		return nil, xerrors.Errorf("Failed to get api key: %w", err)
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, errInvalidKey
+	}
+
+	now := time.Now().UTC()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > touchInterval {
+		if err := apiKey.Touch(db, now); err != nil {
+			return nil, xerrors.Errorf("Failed to update api key: %w", err)
+		}
+	}
+
+	bindings, err := models.GetAPIKeyRoleBindings(db, apiKey.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get role bindings: %w", err)
+	}
+
+	return &Principal{
+		OrganizationID: &apiKey.OrganizationID,
+		APIKeyID:       &apiKey.ID,
+		Roles:          rolesOf(bindings),
+		Attributes:     apiKey.Properties,
+	}, nil
+}
+
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if len(h) > 7 && strings.EqualFold(h[:7], "Bearer ") {
+		return strings.TrimSpace(h[7:])
+	}
+
+	// EventSource cannot set headers. The parameter is accepted only by event streams
+	// so as not to leave credentials in the URLs of the other requests
+	if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/events") {
+		return r.URL.Query().Get("access_token")
+	}
+
+	return ""
+}
+
+// ValidateConfig returns an error if the config is not usable.
+func ValidateConfig(conf *Config) error {
+	switch conf.Mode {
+	case "", ModeNone, ModeAPIKey:
+		return nil
+	case ModeJWT:
+		if err := validateJWTConfig(&conf.JWT); err != nil {
+			return xerrors.Errorf("Invalid jwt config: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("Invalid auth mode: %s", conf.Mode)
+	}
+}
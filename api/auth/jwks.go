@@ -0,0 +1,270 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/logging"
+)
+
+const (
+	jwksFetchTimeout    = 10 * time.Second
+	jwksRefreshInterval = time.Hour
+	// Minimum interval of refetching on unknown key ids
+	jwksRetryInterval = time.Minute
+)
+
+var errUnknownKey = errors.New("Unknown key")
+
+// keySet holds the public keys loaded from a JWKS file or URL.
+type keySet struct {
+	source    string
+	client    *http.Client
+	mutex     sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+	// attemptedAt is when the last load started, which may have failed
+	attemptedAt time.Time
+	// loading is closed when the load in progress finishes. Nil if not loading.
+	loading chan struct{}
+	loadErr error
+}
+
+func newKeySet(source string) *keySet {
+	return &keySet{
+		source: source,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+	}
+}
+
+func (s *keySet) isRemote() bool {
+	return strings.HasPrefix(s.source, "http://") || strings.HasPrefix(s.source, "https://")
+}
+
+// get returns the key of kid. If kid is empty, the key is returned only if the set has exactly one key.
+func (s *keySet) get(kid string) (interface{}, error) {
+	keys, err := s.current(false)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := find(keys, kid)
+	if xerrors.Is(err, errUnknownKey) && s.isRemote() {
+		// Keys may have been rotated
+		keys, err := s.current(true)
+		if err != nil {
+			return nil, err
+		}
+		return find(keys, kid)
+	}
+	return key, err
+}
+
+// current returns the keys, loading them if they have not been loaded or are outdated.
+// If rotated, they are reloaded unless loaded recently. The mutex is not held while loading,
+// and the loaded keys are returned while another request is loading them or if loading fails.
+func (s *keySet) current(rotated bool) (map[string]interface{}, error) {
+	s.mutex.Lock()
+
+	now := time.Now()
+	due := s.keys == nil ||
+		(s.isRemote() && now.Sub(s.fetchedAt) > jwksRefreshInterval) ||
+		(rotated && now.Sub(s.fetchedAt) > jwksRetryInterval)
+	if s.keys != nil && now.Sub(s.attemptedAt) <= jwksRetryInterval {
+		// Failed loads are retried at intervals
+		due = false
+	}
+	if !due {
+		keys := s.keys
+		s.mutex.Unlock()
+		return keys, nil
+	}
+
+	// Wait for the load in progress if there are no keys to use
+	if loading := s.loading; loading != nil {
+		keys := s.keys
+		s.mutex.Unlock()
+		if keys != nil && !rotated {
+			return keys, nil
+		}
+		<-loading
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if s.keys == nil {
+			return nil, s.loadErr
+		}
+		return s.keys, nil
+	}
+
+	loading := make(chan struct{})
+	s.loading = loading
+	s.attemptedAt = now
+	s.mutex.Unlock()
+
+	keys, err := s.load()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.loading = nil
+	close(loading)
+	s.loadErr = err
+	if err != nil {
+		if s.keys == nil {
+			return nil, err
+		}
+		logging.Warning(fmt.Sprintf("Using the keys loaded at %s: %+v", s.fetchedAt.Format(time.RFC3339), err), nil)
+		return s.keys, nil
+	}
+	s.keys = keys
+	s.fetchedAt = now
+	return keys, nil
+}
+
+func find(keys map[string]interface{}, kid string) (interface{}, error) {
+	if kid == "" {
+		if len(keys) == 1 {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+		return nil, errUnknownKey
+	}
+	key, exists := keys[kid]
+	if !exists {
+		return nil, errUnknownKey
+	}
+	return key, nil
+}
+
+func (s *keySet) load() (map[string]interface{}, error) {
+	var data []byte
+	if s.isRemote() {
+		res, err := s.client.Get(s.source)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch jwks: %w", err)
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Failed to fetch jwks: status=%d", res.StatusCode)
+		}
+		data, err = io.ReadAll(res.Body)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read jwks: %w", err)
+		}
+	} else {
+		var err error
+		data, err = os.ReadFile(s.source)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read jwks: %w", err)
+		}
+	}
+
+	return parseJWKS(data)
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS parses RSA and EC signing keys in the JWKS. Other keys are ignored.
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, xerrors.Errorf("Failed to parse jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{})
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var key interface{}
+		var err error
+		switch k.Kty {
+		case "RSA":
+			key, err = k.rsaPublicKey()
+		case "EC":
+			key, err = k.ecdsaPublicKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("Invalid key (keys[%d]): %w", i, err)
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k *jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, xerrors.Errorf("Invalid n: %w", err)
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, xerrors.Errorf("Invalid e: %w", err)
+	}
+	if n.Sign() == 0 || !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("Invalid rsa key")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k *jwk) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("Unsupported curve: %s", k.Crv)
+	}
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, xerrors.Errorf("Invalid x: %w", err)
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, xerrors.Errorf("Invalid y: %w", err)
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("Invalid ec key")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/api/models"
+)
+
+const (
+	defaultOrganizationClaim = "org_id"
+	defaultRoleClaim         = "role"
+)
+
+var jwtSigningMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+type JWTConfig struct {
+	// JWKS is the path or http(s) URL of the JSON Web Key Set to verify signatures.
+	JWKS     string
+	Issuer   string
+	Audience string
+	// OrganizationClaim is the claim holding the organization id. Defaults to "org_id".
+	OrganizationClaim string
+	// RoleClaim is the claim holding the role on the organization. Defaults to "role".
+	RoleClaim string
+}
+
+type jwtVerifier struct {
+	conf   *JWTConfig
+	keys   *keySet
+	parser *jwt.Parser
+}
+
+func newJWTVerifier(conf *JWTConfig) *jwtVerifier {
+	return &jwtVerifier{
+		conf:   conf,
+		keys:   newKeySet(conf.JWKS),
+		parser: &jwt.Parser{ValidMethods: jwtSigningMethods},
+	}
+}
+
+// verify validates the token and returns the principal mapped from its claims.
+// It returns errInvalidKey if the token is not acceptable.
+func (v *jwtVerifier) verify(tokenString string) (*Principal, error) {
+	var keyErr error
+	token, err := v.parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := v.keys.get(kid)
+		if err != nil && !xerrors.Is(err, errUnknownKey) {
+			keyErr = err
+		}
+		return key, err
+	})
+	if keyErr != nil {
+		return nil, xerrors.Errorf("Failed to get key: %w", keyErr)
+	}
+	if err != nil || !token.Valid {
+		return nil, errInvalidKey
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errInvalidKey
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) ||
+		!claims.VerifyIssuer(v.conf.Issuer, true) ||
+		!claims.VerifyAudience(v.conf.Audience, true) {
+		return nil, errInvalidKey
+	}
+
+	return v.principalOf(claims)
+}
+
+func (v *jwtVerifier) principalOf(claims jwt.MapClaims) (*Principal, error) {
+	orgClaim := v.conf.OrganizationClaim
+	if orgClaim == "" {
+		orgClaim = defaultOrganizationClaim
+	}
+	roleClaim := v.conf.RoleClaim
+	if roleClaim == "" {
+		roleClaim = defaultRoleClaim
+	}
+
+	orgStr, _ := claims[orgClaim].(string)
+	orgID, err := uuid.Parse(orgStr)
+	if err != nil || orgID == uuid.Nil {
+		return nil, errInvalidKey
+	}
+	organizationID := models.UUID(orgID)
+
+	roles := make(map[models.UUID]Role)
+	if r, exists := claims[roleClaim]; exists {
+		role, ok := r.(string)
+		if !ok {
+			return nil, errInvalidKey
+		}
+		if _, valid := roleLevels[Role(role)]; !valid {
+			return nil, errInvalidKey
+		}
+		roles[organizationResource] = Role(role)
+	}
+
+	return &Principal{
+		OrganizationID: &organizationID,
+		Roles:          roles,
+		Attributes:     claims,
+	}, nil
+}
+
+func validateJWTConfig(conf *JWTConfig) error {
+	if conf.JWKS == "" {
+		return fmt.Errorf("JWKS is required")
+	}
+	if conf.Issuer == "" {
+		return fmt.Errorf("Issuer is required")
+	}
+	if conf.Audience == "" {
+		return fmt.Errorf("Audience is required")
+	}
+	return nil
+}
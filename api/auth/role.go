@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/utils/responses"
+)
+
+type Role string
+
+const (
+	RoleOwner     Role = "owner"
+	RoleEditor    Role = "editor"
+	RoleCommenter Role = "commenter"
+	RoleViewer    Role = "viewer"
+)
+
+var roleLevels = map[Role]int{
+	RoleViewer:    1,
+	RoleCommenter: 2,
+	RoleEditor:    3,
+	RoleOwner:     4,
+}
+
+type Permission int
+
+const (
+	// PermissionRead allows getting, exporting and selecting.
+	PermissionRead Permission = iota + 1
+	// PermissionComment is reserved for comments. Currently it allows nothing more than PermissionRead.
+	PermissionComment
+	// PermissionWrite allows creating, updating and deleting folders, tables, columns and records.
+	PermissionWrite
+	// PermissionManage allows managing the organization, its api keys, role bindings and webhooks.
+	PermissionManage
+)
+
+// Allows reports whether the role has the permission.
+func (role Role) Allows(perm Permission) bool {
+	return roleLevels[role] >= int(perm)
+}
+
+var organizationResource = models.UUID(uuid.Nil)
+
+// role returns the strongest role granted on any of the resources.
+// The organization itself is represented by the nil uuid.
+func (p *Principal) role(resourceIDs ...models.UUID) Role {
+	var role Role
+	for _, id := range resourceIDs {
+		if r, exists := p.Roles[id]; exists && roleLevels[r] > roleLevels[role] {
+			role = r
+		}
+	}
+	return role
+}
+
+func (p *Principal) hasResourceRoles() bool {
+	for id := range p.Roles {
+		if id != organizationResource {
+			return true
+		}
+	}
+	return false
+}
+
+func rolesOf(bindings []models.RoleBinding) map[models.UUID]Role {
+	roles := make(map[models.UUID]Role)
+	for _, b := range bindings {
+		id := organizationResource
+		if b.ResourceID != nil {
+			id = *b.ResourceID
+		}
+		if roleLevels[Role(b.Role)] > roleLevels[roles[id]] {
+			roles[id] = Role(b.Role)
+		}
+	}
+	return roles
+}
+
+// Authorize returns ErrForbidden if the principal of the request does not have the permission on the organization.
+func Authorize(r *http.Request, organizationID models.UUID, perm Permission) error {
+	p := FromContext(r.Context())
+	if p == nil {
+		return ErrForbidden
+	}
+	if p.Admin {
+		return nil
+	}
+	if p.OrganizationID == nil || *p.OrganizationID != organizationID {
+		return ErrForbidden
+	}
+	if !p.role(organizationResource).Allows(perm) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeAnywhere returns ErrForbidden unless the principal of the request has the permission on the organization
+// or on any of its folders and tables.
+func AuthorizeAnywhere(r *http.Request, organizationID models.UUID, perm Permission) error {
+	p := FromContext(r.Context())
+	if p == nil {
+		return ErrForbidden
+	}
+	if p.Admin {
+		return nil
+	}
+	if p.OrganizationID == nil || *p.OrganizationID != organizationID {
+		return ErrForbidden
+	}
+	for _, role := range p.Roles {
+		if role.Allows(perm) {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// AuthorizedEntries returns the folders and tables on which the principal of the request has the permission,
+// or nil if it has the permission on the whole organization. ErrForbidden is returned if it has the permission nowhere.
+// The descendants of the entries returned are also authorized.
+func AuthorizedEntries(r *http.Request, organizationID models.UUID, perm Permission) ([]models.UUID, error) {
+	err := Authorize(r, organizationID, perm)
+	if err == nil || !xerrors.Is(err, ErrForbidden) {
+		return nil, err
+	}
+
+	p := FromContext(r.Context())
+	if p == nil || p.OrganizationID == nil || *p.OrganizationID != organizationID {
+		return nil, ErrForbidden
+	}
+	var ids []models.UUID
+	for id, role := range p.Roles {
+		if id != organizationResource && role.Allows(perm) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrForbidden
+	}
+	return ids, nil
+}
+
+// AuthorizeEntry returns ErrForbidden if the principal of the request does not have the permission on the folder or table.
+// Roles granted on the organization and on the ancestor folders are inherited.
+// The entry with the nil id is regarded as the root folder.
+func AuthorizeEntry(r *http.Request, db *gorm.DB, entry *models.TableFilesystemEntry, perm Permission) error {
+	err := Authorize(r, entry.OrganizationID, perm)
+	if err == nil || !xerrors.Is(err, ErrForbidden) {
+		return err
+	}
+
+	p := FromContext(r.Context())
+	if p == nil || p.OrganizationID == nil || *p.OrganizationID != entry.OrganizationID {
+		return ErrForbidden
+	}
+	if entry.ID == models.UUID(uuid.Nil) || !p.hasResourceRoles() {
+		return ErrForbidden
+	}
+
+	e := *entry
+	if err := e.ComputePath(db); err != nil {
+		return xerrors.Errorf("Failed to get path: %w", err)
+	}
+	ids := []models.UUID{e.ID}
+	for _, a := range e.Path {
+		ids = append(ids, a.ID)
+	}
+	if !p.role(ids...).Allows(perm) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// BypassesRowPolicies reports whether table policies are not applied to the principal of the request.
+// Administrators and owners of the entry bypass them.
+func BypassesRowPolicies(r *http.Request, db *gorm.DB, entry *models.TableFilesystemEntry) (bool, error) {
+	err := AuthorizeEntry(r, db, entry, PermissionManage)
+	if err == nil {
+		return true, nil
+	}
+	if xerrors.Is(err, ErrForbidden) {
+		return false, nil
+	}
+	return false, err
+}
+
+// HasRowPoliciesApplied reports whether records of any table in the entry (or the entry itself if it is a table)
+// are subject to table policies for the principal of the request.
+func HasRowPoliciesApplied(r *http.Request, db *gorm.DB, entry *models.TableFilesystemEntry) (bool, error) {
+	bypass, err := BypassesRowPolicies(r, db, entry)
+	if err != nil {
+		return false, err
+	}
+	if bypass {
+		return false, nil
+	}
+
+	count, err := models.CountTablePoliciesUnder(db, entry.OrganizationID, &entry.ID)
+	if err != nil {
+		return false, xerrors.Errorf("Failed to count policies: %w", err)
+	}
+	return count > 0, nil
+}
+
+// SendError sends the error response for the error returned by Authorize functions.
+func SendError(w http.ResponseWriter, r *http.Request, err error) {
+	if xerrors.Is(err, ErrForbidden) {
+		responses.SendErrorResponse(w, r, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+	responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to check permission", err)
+}
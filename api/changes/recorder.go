@@ -0,0 +1,157 @@
+package changes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/logging"
+)
+
+// recordQueueSize is the number of published events waiting to be recorded before Publish blocks.
+const recordQueueSize = 1024
+
+// Recorder persists published events so that streams can resume from an event id.
+type Recorder struct {
+	DB        *gorm.DB
+	mu        sync.Mutex
+	wake      chan struct{}
+	stop      chan struct{}
+	stopOnce  sync.Once
+	queue     chan *events.Event
+	closed    chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func NewRecorder(db *gorm.DB) *Recorder {
+	r := &Recorder{
+		DB:     db,
+		wake:   make(chan struct{}),
+		stop:   make(chan struct{}),
+		queue:  make(chan *events.Event, recordQueueSize),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.work()
+	return r
+}
+
+// Stop ends the active streams, e.g. on shutdown.
+func (r *Recorder) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Close records the queued events and stops recording. Events published after Close are dropped.
+func (r *Recorder) Close() {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+	})
+	<-r.done
+}
+
+// Subscribe makes the recorder save events published to the bus.
+// Events are recorded in background in the order of publication.
+func (r *Recorder) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(e *events.Event) {
+		select {
+		case r.queue <- e:
+		case <-r.closed:
+			logging.Error(fmt.Sprintf("Dropped event %s published after the recorder was closed", e.ID), nil)
+		}
+	})
+}
+
+func (r *Recorder) work() {
+	defer close(r.done)
+	for {
+		select {
+		case e := <-r.queue:
+			r.recordOrLog(e)
+		case <-r.closed:
+			for {
+				select {
+				case e := <-r.queue:
+					r.recordOrLog(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *Recorder) recordOrLog(e *events.Event) {
+	if err := r.record(e); err != nil {
+		logging.Error(fmt.Sprintf("Failed to record event %s: %+v", e.ID, err), nil)
+	}
+}
+
+// Changed returns a channel which is closed when the next event is recorded.
+func (r *Recorder) Changed() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.wake
+}
+
+// Prune deletes events older than the retention.
+func (r *Recorder) Prune(retention time.Duration) error {
+	return models.DeleteChangeEventsBefore(r.DB, time.Now().UTC().Add(-retention))
+}
+
+func (r *Recorder) record(e *events.Event) error {
+	// Collect the folders and their ancestors
+	var folderIDs models.StringList
+	seen := map[models.UUID]bool{}
+	add := func(id models.UUID) {
+		if !seen[id] {
+			seen[id] = true
+			folderIDs = append(folderIDs, id.String())
+		}
+	}
+	for _, id := range e.FolderIDs {
+		add(models.UUID(id))
+		entry := &models.TableFilesystemEntry{ID: models.UUID(id)}
+		if err := entry.ComputePath(r.DB); err != nil {
+			return xerrors.Errorf("Failed to get path: %w", err)
+		}
+		for _, p := range entry.Path {
+			add(p.ID)
+		}
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return xerrors.Errorf("Failed to serialize event: %w", err)
+	}
+
+	ce := &models.ChangeEvent{
+		ID:             models.UUID(e.ID),
+		OrganizationID: models.UUID(e.OrganizationID),
+		TableID:        (*models.UUID)(e.TableID),
+		FolderIDs:      folderIDs,
+		Type:           e.Type,
+		Payload:        models.JSON(payload),
+		CreatedAt:      e.CreatedAt,
+	}
+
+	// Events are inserted one by one by the worker, so streams never see a seq before a smaller one is committed
+	if err := ce.Create(r.DB); err != nil {
+		return xerrors.Errorf("Failed to create change event: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	close(r.wake)
+	r.wake = make(chan struct{})
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package changes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+)
+
+const (
+	streamBatchSize   = 100
+	pollInterval      = 2 * time.Second
+	heartbeatInterval = 15 * time.Second
+)
+
+var ErrStreamingNotSupported = errors.New("Streaming not supported")
+
+type StreamOpts struct {
+	OrganizationID *models.UUID
+	TableID        *models.UUID
+	FolderID       *models.UUID
+	AfterSeq       int64
+	// Filter hides events from the client if not nil.
+	Filter Filter
+}
+
+// Filter returns the payload sent to the client for the event, or nil to skip the event.
+type Filter func(e *models.ChangeEvent) (models.JSON, error)
+
+// RecordsFilter returns the filter which narrows the record ids of record events to the ones visible returns.
+// Record events with no visible ids are skipped. The other events are sent as they are.
+// Records may have been changed or deleted since the event, so visible should decide by the state of the records
+// captured with the event rather than the current one.
+func RecordsFilter(visible func(e *models.ChangeEvent, ids []models.UUID) ([]models.UUID, error)) Filter {
+	return func(e *models.ChangeEvent) (models.JSON, error) {
+		if e.TableID == nil || !strings.HasPrefix(e.Type, "record.") {
+			return e.Payload, nil
+		}
+
+		var payload struct {
+			events.Event
+			Data events.RecordsData `json:"data"`
+		}
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return nil, xerrors.Errorf("Failed to deserialize payload: %w", err)
+		}
+		ids := make([]models.UUID, 0, len(payload.Data.RecordIDs))
+		for _, id := range payload.Data.RecordIDs {
+			ids = append(ids, models.UUID(id))
+		}
+
+		visibleIDs, err := visible(e, ids)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to filter records: %w", err)
+		}
+		if len(visibleIDs) == 0 {
+			return nil, nil
+		}
+		isVisible := map[models.UUID]bool{}
+		for _, id := range visibleIDs {
+			isVisible[id] = true
+		}
+		var recordIDs []uuid.UUID
+		for _, id := range payload.Data.RecordIDs {
+			if isVisible[models.UUID(id)] {
+				recordIDs = append(recordIDs, id)
+			}
+		}
+		payload.Event.Data = &events.RecordsData{RecordIDs: recordIDs}
+
+		b, err := json.Marshal(&payload.Event)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to serialize payload: %w", err)
+		}
+		return models.JSON(b), nil
+	}
+}
+
+// Stream sends events as Server-Sent Events until the client disconnects or the recorder is stopped.
+// Event ids are seqs of the recorded events, which clients send back as Last-Event-ID on reconnect.
+func (r *Recorder) Stream(w http.ResponseWriter, req *http.Request, opts *StreamOpts) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return ErrStreamingNotSupported
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	afterSeq := opts.AfterSeq
+	for {
+		// Get the channel before querying not to miss events recorded in between
+		changed := r.Changed()
+
+		events, err := models.GetChangeEventList(r.DB.WithContext(req.Context()), &models.GetChangeEventListOpts{
+			OrganizationID: opts.OrganizationID,
+			TableID:        opts.TableID,
+			FolderID:       opts.FolderID,
+			AfterSeq:       afterSeq,
+			Limit:          streamBatchSize,
+		})
+		if err != nil {
+			if req.Context().Err() != nil {
+				return nil
+			}
+			return xerrors.Errorf("Failed to get change events: %w", err)
+		}
+		for i := range events {
+			e := &events[i]
+			afterSeq = e.Seq
+			payload := e.Payload
+			if opts.Filter != nil {
+				payload, err = opts.Filter(e)
+				if err != nil {
+					return xerrors.Errorf("Failed to filter change event: %w", err)
+				}
+				if payload == nil {
+					continue
+				}
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, payload); err != nil {
+				return nil
+			}
+		}
+		flusher.Flush()
+
+		if len(events) == streamBatchSize {
+			continue
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil
+		case <-r.stop:
+			return nil
+		case <-changed:
+		case <-time.After(pollInterval):
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// ResolveAfterSeq returns the seq to stream after. Streams start from the latest event if lastEventID is not given.
+func (r *Recorder) ResolveAfterSeq(lastEventID *int64) (int64, error) {
+	if lastEventID != nil {
+		return *lastEventID, nil
+	}
+	return models.GetLatestChangeEventSeq(r.DB)
+}
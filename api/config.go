@@ -0,0 +1,27 @@
+package api
+
+import (
+	"time"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/health"
+	"github.com/tsujio/x-base/api/idempotency"
+	"github.com/tsujio/x-base/api/quotas"
+	"github.com/tsujio/x-base/api/ratelimit"
+	"github.com/tsujio/x-base/api/timeout"
+	"github.com/tsujio/x-base/api/webhooks"
+)
+
+type Config struct {
+	Auth        auth.Config
+	RateLimit   ratelimit.Config
+	Quota       quotas.Config
+	Idempotency idempotency.Config
+	Health      health.Config
+	Timeout     timeout.Config
+	Webhook     webhooks.Config
+	// ShutdownTimeout is how long to wait for in-flight requests on shutdown. DefaultShutdownTimeout if zero.
+	ShutdownTimeout time.Duration
+}
+
+const DefaultShutdownTimeout = 30 * time.Second
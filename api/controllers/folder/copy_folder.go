@@ -0,0 +1,200 @@
+package folder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/jobs"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *FolderController) CopyFolder(w http.ResponseWriter, r *http.Request) {
+	// Get folder id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "folderID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid folder id", err)
+		return
+	}
+
+	// Decode request body
+	var input schemas.CopyFolderInput
+	err = schemas.DecodeJSON(r.Body, &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if result := models.ValidateProperties(input.Properties); result != "" {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+		return
+	}
+
+	// Fetch
+	folder, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get folder", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &folder.TableFilesystemEntry, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Check destination folder
+	if input.ParentFolderID != nil && *input.ParentFolderID != uuid.Nil {
+		parent, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.db(r))
+		if err != nil {
+			if xerrors.Is(err, gorm.ErrRecordNotFound) {
+				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Destination folder not found", nil)
+				return
+			}
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get destination folder", err)
+			return
+		}
+
+		if parent.OrganizationID != folder.OrganizationID {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Cannot copy to another organization", nil)
+			return
+		}
+
+		err = parent.ComputePath(controller.db(r))
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get path", err)
+			return
+		}
+		if parent.ID == folder.ID {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Cannot copy into itself", nil)
+			return
+		}
+		for _, p := range parent.Path {
+			if p.ID == folder.ID {
+				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Cannot copy into itself", nil)
+				return
+			}
+		}
+	}
+
+	// Check permission on destination folder
+	destination := models.TableFilesystemEntry{OrganizationID: folder.OrganizationID}
+	if input.ParentFolderID != nil {
+		destination.ID = models.UUID(*input.ParentFolderID)
+	}
+	if err := auth.AuthorizeEntry(r, controller.db(r), &destination, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+	if input.IncludeRecords {
+		applied, err := auth.HasRowPoliciesApplied(r, controller.db(r), &folder.TableFilesystemEntry)
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to check table policies", err)
+			return
+		}
+		if applied {
+			responses.SendErrorResponse(w, r, http.StatusForbidden, "Cannot copy records of tables with policies", nil)
+			return
+		}
+	}
+
+	// Copy in background, checking quotas in the job
+	if input.Async {
+		job, err := controller.Runner.Enqueue(folder.OrganizationID, jobs.JobTypeCopyFolder, &jobs.CopyFolderParams{
+			FolderID:       id,
+			ParentFolderID: input.ParentFolderID,
+			Properties:     input.Properties,
+			IncludeRecords: input.IncludeRecords,
+		})
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to start job", err)
+			return
+		}
+
+		var output schemas.Job
+		err = copier.Copy(&output, &job)
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		err = json.NewEncoder(w).Encode(&output)
+		if err != nil {
+			logging.Error(fmt.Sprintf("%+v", err), r)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Load the storage usage before writing so that it does not include uncommitted records
+	if err := controller.Quotas.LoadStorage(controller.db(r), folder.OrganizationID); err != nil {
+		quotas.SendError(w, r, err)
+		return
+	}
+
+	// Copy
+	var dup *models.Folder
+	var size int64
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		dup, err = folder.Copy(tx, &models.CopyFolderOpts{
+			ParentFolderID: (*models.UUID)(input.ParentFolderID),
+			Properties:     input.Properties,
+			IncludeRecords: input.IncludeRecords,
+		})
+		if err != nil {
+			return err
+		}
+		size, err = controller.Quotas.CheckCopy(tx, &dup.TableFilesystemEntry, input.IncludeRecords)
+		return err
+	})
+	if err != nil {
+		if quotas.IsExceeded(err) {
+			quotas.SendError(w, r, err)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to copy folder", err)
+		return
+	}
+	controller.Quotas.AddStorage(dup.OrganizationID, size)
+
+	// Convert to output schema
+	var output schemas.Folder
+	err = dup.ComputePath(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get path", err)
+		return
+	}
+	err = copier.Copy(&output, &dup)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeFolderCreated, dup.OrganizationID, nil, &output).In(&dup.ID))
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
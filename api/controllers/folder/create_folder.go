@@ -10,6 +10,8 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
 	"github.com/tsujio/x-base/api/utils/responses"
@@ -30,8 +32,10 @@ func (controller *FolderController) CreateFolder(w http.ResponseWriter, r *http.
 	}
 
 	// Check parent folder
+	parent := &models.Folder{}
+	parent.OrganizationID = models.UUID(input.OrganizationID)
 	if input.ParentFolderID != nil && *input.ParentFolderID != uuid.Nil {
-		parent, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.DB)
+		p, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.db(r))
 		if err != nil {
 			if xerrors.Is(err, gorm.ErrRecordNotFound) {
 				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Parent folder not found", nil)
@@ -41,10 +45,17 @@ func (controller *FolderController) CreateFolder(w http.ResponseWriter, r *http.
 			return
 		}
 
-		if parent.OrganizationID != models.UUID(input.OrganizationID) {
+		if p.OrganizationID != models.UUID(input.OrganizationID) {
 			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Cannot create folder as a child of another organization's folder", nil)
 			return
 		}
+		parent = p
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &parent.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
 	}
 
 	// Create folder
@@ -55,7 +66,7 @@ func (controller *FolderController) CreateFolder(w http.ResponseWriter, r *http.
 			Properties:     input.Properties,
 		},
 	}
-	err = f.Create(controller.DB)
+	err = f.Create(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to create folder", err)
 		return
@@ -63,7 +74,7 @@ func (controller *FolderController) CreateFolder(w http.ResponseWriter, r *http.
 
 	// Convert to output schema
 	var output schemas.Folder
-	err = f.ComputePath(controller.DB)
+	err = f.ComputePath(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get path", err)
 		return
@@ -74,6 +85,9 @@ func (controller *FolderController) CreateFolder(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeFolderCreated, f.OrganizationID, nil, &output).In(&f.ID))
+
 	// Send response
 	err = json.NewEncoder(w).Encode(&output)
 	if err != nil {
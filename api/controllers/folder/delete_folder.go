@@ -8,8 +8,11 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 )
 
@@ -24,7 +27,7 @@ func (controller *FolderController) DeleteFolder(w http.ResponseWriter, r *http.
 	}
 
 	// Fetch
-	folder, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.DB)
+	folder, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
@@ -34,10 +37,26 @@ func (controller *FolderController) DeleteFolder(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &folder.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Check precondition
+	if etag.PreconditionFailed(w, r, func() (interface{}, error) {
+		return makeFolderOutput(controller.db(r), folder)
+	}) {
+		return
+	}
+
 	// Delete
-	err = folder.Delete(controller.DB)
+	err = folder.Delete(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to delete folder", err)
 		return
 	}
+
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeFolderDeleted, folder.OrganizationID, nil, &events.DeletedData{ID: id}).In(&folder.ID, folder.ParentFolderID))
 }
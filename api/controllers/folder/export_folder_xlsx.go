@@ -0,0 +1,109 @@
+package folder
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/api/utils/xlsx"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *FolderController) ExportFolderXLSX(w http.ResponseWriter, r *http.Request) {
+	// Get folder id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "folderID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid folder id", err)
+		return
+	}
+
+	// Decode request parameters
+	var input schemas.ExportFolderXLSXInput
+	err = schemas.DecodeQuery(r.URL.Query(), &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request parameter", err)
+		return
+	}
+
+	// Fetch
+	var folder *models.Folder
+	if id == uuid.Nil {
+		if input.OrganizationID == uuid.Nil {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Organization id is required for root folder", nil)
+			return
+		}
+		folder = &models.Folder{}
+		folder.OrganizationID = models.UUID(input.OrganizationID)
+	} else {
+		f, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.db(r))
+		if err != nil {
+			if xerrors.Is(err, gorm.ErrRecordNotFound) {
+				responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+				return
+			}
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get folder", err)
+			return
+		}
+		folder = f
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &folder.TableFilesystemEntry, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+	applied, err := auth.HasRowPoliciesApplied(r, controller.db(r), &folder.TableFilesystemEntry)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to check table policies", err)
+		return
+	}
+	if applied {
+		responses.SendErrorResponse(w, r, http.StatusForbidden, "Cannot export records of tables with policies", nil)
+		return
+	}
+
+	// Get tables
+	tables, err := folder.GetChildTables(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get tables", err)
+		return
+	}
+
+	// Make workbook
+	wb := xlsx.NewWorkbook()
+	for i := range tables {
+		table := &tables[i]
+		if err := table.FetchColumns(controller.db(r)); err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
+			return
+		}
+		records, err := table.FetchRecordValues(controller.db(r), nil)
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch records", err)
+			return
+		}
+		if err := wb.AddTable(table, records); err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make workbook", err)
+			return
+		}
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", xlsx.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.xlsx"`, folder.ID))
+	_, err = wb.File.WriteTo(w)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -12,8 +12,10 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
 )
@@ -37,7 +39,7 @@ func (controller *FolderController) GetFolder(w http.ResponseWriter, r *http.Req
 	}
 
 	// Fetch
-	folder, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.DB)
+	folder, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
@@ -47,9 +49,15 @@ func (controller *FolderController) GetFolder(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &folder.TableFilesystemEntry, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Convert to output schema
 	var output schemas.Folder
-	err = folder.ComputePath(controller.DB)
+	err = folder.ComputePath(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get path", err)
 		return
@@ -59,6 +67,18 @@ func (controller *FolderController) GetFolder(w http.ResponseWriter, r *http.Req
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
 		return
 	}
+
+	// Check cache
+	tag, err := etag.Of(&output)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make etag", err)
+		return
+	}
+	if etag.NotModified(w, r, tag) {
+		return
+	}
+
+	// Select properties
 	if input.Properties != "" {
 		keys := strings.Split(input.Properties, ",")
 		output.Properties = folder.Properties.SelectKeys(keys)
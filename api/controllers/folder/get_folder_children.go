@@ -12,6 +12,7 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
 	"github.com/tsujio/x-base/api/utils/responses"
@@ -66,7 +67,7 @@ func (controller *FolderController) GetFolderChildren(w http.ResponseWriter, r *
 		folder = &models.Folder{}
 		folder.OrganizationID = models.UUID(input.OrganizationID)
 	} else {
-		f, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.DB)
+		f, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.db(r))
 		if err != nil {
 			if xerrors.Is(err, gorm.ErrRecordNotFound) {
 				responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
@@ -78,6 +79,12 @@ func (controller *FolderController) GetFolderChildren(w http.ResponseWriter, r *
 		folder = f
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &folder.TableFilesystemEntry, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Get children
 	var sortKeyOpt []models.GetListSortKey
 	if err := copier.Copy(&sortKeyOpt, &sortKeys); err != nil {
@@ -90,7 +97,7 @@ func (controller *FolderController) GetFolderChildren(w http.ResponseWriter, r *
 		Limit:       *input.PageSize,
 		ComputePath: true,
 	}
-	children, totalCount, err := folder.GetChildren(controller.DB, &opts)
+	children, totalCount, err := folder.GetChildren(controller.db(r), &opts)
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get children", err)
 		return
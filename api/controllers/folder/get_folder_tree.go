@@ -0,0 +1,116 @@
+package folder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+// maxFolderTreeEntries is the max number of entries returned in a tree.
+const maxFolderTreeEntries = 10000
+
+func (controller *FolderController) GetFolderTree(w http.ResponseWriter, r *http.Request) {
+	// Get folder id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "folderID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid folder id", err)
+		return
+	}
+
+	// Decode request parameters
+	var input schemas.GetFolderTreeInput
+	err = schemas.DecodeQuery(r.URL.Query(), &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request parameter", err)
+		return
+	}
+
+	// Decode sort key
+	var sortKeys []schemas.GetListSortKey
+	err = schemas.DecodeGetListSort(input.Sort, &sortKeys)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid sort parameter", err)
+		return
+	}
+
+	// Fetch
+	var folder *models.Folder
+	if id == uuid.Nil {
+		if input.OrganizationID == uuid.Nil {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Organization id is required for root folder", nil)
+			return
+		}
+		folder = &models.Folder{}
+		folder.OrganizationID = models.UUID(input.OrganizationID)
+	} else {
+		f, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.db(r))
+		if err != nil {
+			if xerrors.Is(err, gorm.ErrRecordNotFound) {
+				responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+				return
+			}
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get folder", err)
+			return
+		}
+		folder = f
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &folder.TableFilesystemEntry, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Get tree
+	var sortKeyOpt []models.GetListSortKey
+	if err := copier.Copy(&sortKeyOpt, &sortKeys); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make query option", err)
+		return
+	}
+	opts := models.GetFolderTreeOpts{
+		Depth:      input.Depth,
+		Type:       input.Type,
+		Sort:       sortKeyOpt,
+		MaxEntries: maxFolderTreeEntries,
+	}
+	tree, err := folder.GetTree(controller.db(r), &opts)
+	if err != nil {
+		if xerrors.Is(err, models.ErrTooManyEntries) {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Too many entries in tree; specify depth or type", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get tree", err)
+		return
+	}
+
+	// Convert to output schema
+	var keys []string
+	if input.Properties != "" {
+		keys = strings.Split(input.Properties, ",")
+	}
+	output := schemas.FolderTree{
+		Children: makeFolderTreeOutput(tree, keys),
+	}
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
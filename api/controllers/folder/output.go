@@ -0,0 +1,47 @@
+package folder
+
+import (
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+)
+
+// makeFolderOutput converts the folder with its path to the output schema.
+func makeFolderOutput(db *gorm.DB, folder *models.Folder) (*schemas.Folder, error) {
+	if err := folder.ComputePath(db); err != nil {
+		return nil, xerrors.Errorf("Failed to get path: %w", err)
+	}
+	var output schemas.Folder
+	if err := copier.Copy(&output, folder); err != nil {
+		return nil, xerrors.Errorf("Failed to make output data: %w", err)
+	}
+	return &output, nil
+}
+
+// makeFolderTreeOutput converts the tree to the output schema, selecting the properties of the keys unless nil.
+func makeFolderTreeOutput(tree []models.TableFilesystemTreeEntry, keys []string) []schemas.FolderTreeEntry {
+	output := []schemas.FolderTreeEntry{}
+	for _, e := range tree {
+		o := schemas.FolderTreeEntry{
+			ID:             uuid.UUID(e.ID),
+			OrganizationID: uuid.UUID(e.OrganizationID),
+			Type:           e.Type,
+			Properties:     e.Properties,
+			CreatedAt:      e.CreatedAt,
+			UpdatedAt:      e.UpdatedAt,
+		}
+		if keys != nil {
+			o.Properties = e.Properties.SelectKeys(keys)
+		}
+		if e.Children != nil {
+			children := makeFolderTreeOutput(e.Children, keys)
+			o.Children = &children
+		}
+		output = append(output, o)
+	}
+	return output
+}
@@ -0,0 +1,123 @@
+package folder
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/changes"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *FolderController) StreamFolderEvents(w http.ResponseWriter, r *http.Request) {
+	// Get folder id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "folderID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid folder id", err)
+		return
+	}
+
+	// Decode request parameters
+	var input schemas.StreamEventsInput
+	err = schemas.DecodeQuery(r.URL.Query(), &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request parameter", err)
+		return
+	}
+	err = input.DecodeLastEventIDHeader(r.Header)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request header", err)
+		return
+	}
+
+	// Fetch
+	opts := changes.StreamOpts{}
+	if id == uuid.Nil {
+		if input.OrganizationID == uuid.Nil {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Organization id is required for root folder", nil)
+			return
+		}
+		// Check permission
+		if err := auth.Authorize(r, models.UUID(input.OrganizationID), auth.PermissionRead); err != nil {
+			auth.SendError(w, r, err)
+			return
+		}
+		organization, err := (&models.Organization{ID: models.UUID(input.OrganizationID)}).Get(controller.db(r))
+		if err != nil {
+			if xerrors.Is(err, gorm.ErrRecordNotFound) {
+				responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+				return
+			}
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get organization", err)
+			return
+		}
+		opts.OrganizationID = &organization.ID
+	} else {
+		folder, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.db(r))
+		if err != nil {
+			if xerrors.Is(err, gorm.ErrRecordNotFound) {
+				responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+				return
+			}
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get folder", err)
+			return
+		}
+		// Check permission
+		if err := auth.AuthorizeEntry(r, controller.db(r), &folder.TableFilesystemEntry, auth.PermissionRead); err != nil {
+			auth.SendError(w, r, err)
+			return
+		}
+		opts.FolderID = &folder.ID
+	}
+	opts.AfterSeq, err = controller.Changes.ResolveAfterSeq(input.LastEventID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get latest event", err)
+		return
+	}
+
+	// Hide the record events of the tables with policies applied to the principal
+	db := controller.db(r)
+	applied := map[models.UUID]bool{}
+	opts.Filter = changes.RecordsFilter(func(e *models.ChangeEvent, ids []models.UUID) ([]models.UUID, error) {
+		tableID := *e.TableID
+		a, checked := applied[tableID]
+		if !checked {
+			table, err := (&models.TableFilesystemEntry{ID: tableID}).GetTable(db)
+			if err != nil {
+				if xerrors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, nil
+				}
+				return nil, xerrors.Errorf("Failed to get table: %w", err)
+			}
+			a, err = auth.HasRowPoliciesApplied(r, db, &table.TableFilesystemEntry)
+			if err != nil {
+				return nil, xerrors.Errorf("Failed to check table policies: %w", err)
+			}
+			applied[tableID] = a
+		}
+		if a {
+			return nil, nil
+		}
+		return ids, nil
+	})
+
+	// Stream
+	err = controller.Changes.Stream(w, r, &opts)
+	if err != nil {
+		if xerrors.Is(err, changes.ErrStreamingNotSupported) {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to start stream", err)
+			return
+		}
+		logging.Error(fmt.Sprintf("%+v", err), r)
+	}
+}
@@ -11,8 +11,11 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
 )
@@ -40,7 +43,7 @@ func (controller *FolderController) UpdateFolder(w http.ResponseWriter, r *http.
 	}
 
 	// Fetch
-	folder, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.DB)
+	folder, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetFolder(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
@@ -50,9 +53,15 @@ func (controller *FolderController) UpdateFolder(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &folder.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Check destination folder
 	if input.ParentFolderID != nil && *input.ParentFolderID != uuid.Nil {
-		parent, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.DB)
+		parent, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.db(r))
 		if err != nil {
 			if xerrors.Is(err, gorm.ErrRecordNotFound) {
 				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Destination folder not found", nil)
@@ -68,7 +77,7 @@ func (controller *FolderController) UpdateFolder(w http.ResponseWriter, r *http.
 		}
 
 		// Path loop check
-		err = parent.ComputePath(controller.DB)
+		err = parent.ComputePath(controller.db(r))
 		if err != nil {
 			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get destination folder's path", err)
 		}
@@ -80,22 +89,51 @@ func (controller *FolderController) UpdateFolder(w http.ResponseWriter, r *http.
 		}
 	}
 
-	// Update
+	// Check permission on destination folder
 	if input.ParentFolderID != nil {
-		folder.ParentFolderID = (*models.UUID)(input.ParentFolderID)
-	}
-	for k, v := range input.Properties {
-		folder.Properties[k] = v
+		destination := models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID), OrganizationID: folder.OrganizationID}
+		if err := auth.AuthorizeEntry(r, controller.db(r), &destination, auth.PermissionWrite); err != nil {
+			auth.SendError(w, r, err)
+			return
+		}
 	}
-	err = folder.Save(controller.DB)
+
+	// Update, checking precondition on the locked folder
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		folder, err = (&models.TableFilesystemEntry{ID: folder.ID}).GetFolder(models.ForUpdate(tx))
+		if err != nil {
+			return err
+		}
+		if err := etag.Check(r, func() (interface{}, error) {
+			return makeFolderOutput(tx, folder)
+		}); err != nil {
+			return err
+		}
+
+		if input.ParentFolderID != nil {
+			folder.ParentFolderID = (*models.UUID)(input.ParentFolderID)
+		}
+		for k, v := range input.Properties {
+			folder.Properties[k] = v
+		}
+		return folder.Save(tx)
+	})
 	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		if xerrors.Is(err, etag.ErrPreconditionFailed) {
+			etag.SendError(w, r, err)
+			return
+		}
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Invalid to save folder", err)
 		return
 	}
 
 	// Convert to output schema
 	var output schemas.Folder
-	err = folder.ComputePath(controller.DB)
+	err = folder.ComputePath(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get path", err)
 		return
@@ -106,7 +144,14 @@ func (controller *FolderController) UpdateFolder(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeFolderUpdated, folder.OrganizationID, nil, &output).In(&folder.ID))
+
 	// Send response
+	if err := etag.Set(w, &output); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make etag", err)
+		return
+	}
 	err = json.NewEncoder(w).Encode(&output)
 	if err != nil {
 		logging.Error(fmt.Sprintf("%+v", err), r)
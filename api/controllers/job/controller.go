@@ -0,0 +1,16 @@
+package job
+
+import (
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type JobController struct {
+	DB *gorm.DB
+}
+
+// db returns the db bound to the request context, so that queries are cancelled with the request.
+func (controller *JobController) db(r *http.Request) *gorm.DB {
+	return controller.DB.WithContext(r.Context())
+}
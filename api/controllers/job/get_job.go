@@ -0,0 +1,62 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *JobController) GetJob(w http.ResponseWriter, r *http.Request) {
+	// Get job id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "jobID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid job id", err)
+		return
+	}
+
+	// Fetch
+	job, err := (&models.Job{ID: models.UUID(id)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get job", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeAnywhere(r, job.OrganizationID, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.Job
+	err = copier.Copy(&output, &job)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -1,7 +1,16 @@
 package organization
 
-import "gorm.io/gorm"
+import (
+	"net/http"
+
+	"gorm.io/gorm"
+)
 
 type OrganizationController struct {
 	DB *gorm.DB
 }
+
+// db returns the db bound to the request context, so that queries are cancelled with the request.
+func (controller *OrganizationController) db(r *http.Request) *gorm.DB {
+	return controller.DB.WithContext(r.Context())
+}
@@ -0,0 +1,108 @@
+package organization
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *OrganizationController) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	// Get organization id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "organizationID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid organization id", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.Authorize(r, models.UUID(id), auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Decode request body
+	var input schemas.CreateAPIKeyInput
+	err = schemas.DecodeJSON(r.Body, &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if result := models.ValidateProperties(input.Properties); result != "" {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+		return
+	}
+
+	// Fetch
+	organization, err := (&models.Organization{ID: models.UUID(id)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get organization", err)
+		return
+	}
+
+	// Create api key
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to generate api key", err)
+		return
+	}
+	if input.Role == "" {
+		input.Role = string(auth.RoleViewer)
+	}
+	apiKey := models.APIKey{
+		OrganizationID: organization.ID,
+		Prefix:         prefix,
+		KeyHash:        hash,
+		Properties:     input.Properties,
+	}
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		if err := apiKey.Create(tx); err != nil {
+			return err
+		}
+
+		// Grant the role on the organization
+		binding := models.RoleBinding{
+			OrganizationID: organization.ID,
+			APIKeyID:       apiKey.ID,
+			Role:           input.Role,
+		}
+		return binding.Create(tx)
+	})
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to create api key", err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.CreatedAPIKey
+	err = copier.Copy(&output.APIKey, &apiKey)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+	output.Key = key
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
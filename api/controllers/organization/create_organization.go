@@ -7,6 +7,7 @@ import (
 
 	"github.com/jinzhu/copier"
 
+	"github.com/tsujio/x-base/api/auth"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
 	"github.com/tsujio/x-base/api/utils/responses"
@@ -14,6 +15,12 @@ import (
 )
 
 func (controller *OrganizationController) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	// Check permission
+	if err := auth.AuthorizeAdmin(r); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusForbidden, "Forbidden", nil)
+		return
+	}
+
 	// Decode request body
 	var input schemas.CreateOrganizationInput
 	err := schemas.DecodeJSON(r.Body, &input)
@@ -30,7 +37,7 @@ func (controller *OrganizationController) CreateOrganization(w http.ResponseWrit
 	o := models.Organization{
 		Properties: input.Properties,
 	}
-	err = o.Create(controller.DB)
+	err = o.Create(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to create organization", err)
 		return
@@ -0,0 +1,115 @@
+package organization
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *OrganizationController) CreateRoleBinding(w http.ResponseWriter, r *http.Request) {
+	// Get organization id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "organizationID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid organization id", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.Authorize(r, models.UUID(id), auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Decode request body
+	var input schemas.CreateRoleBindingInput
+	err = schemas.DecodeJSON(r.Body, &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// Check api key
+	apiKey, err := (&models.APIKey{ID: models.UUID(input.APIKeyID)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "API key not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get api key", err)
+		return
+	}
+	if apiKey.OrganizationID != models.UUID(id) {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "API key not found", nil)
+		return
+	}
+
+	// Check resource
+	if input.ResourceID != nil && *input.ResourceID != uuid.Nil {
+		var entry models.TableFilesystemEntry
+		err := controller.db(r).Where("id = ?", models.UUID(*input.ResourceID)).First(&entry).Error
+		if err != nil {
+			if xerrors.Is(err, gorm.ErrRecordNotFound) {
+				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Resource not found", nil)
+				return
+			}
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get resource", err)
+			return
+		}
+		if entry.OrganizationID != models.UUID(id) {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Resource not found", nil)
+			return
+		}
+	}
+
+	// Check duplication
+	_, err = models.FindRoleBinding(controller.db(r), apiKey.ID, (*models.UUID)(input.ResourceID))
+	if err == nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Role is already granted to the api key on the resource", nil)
+		return
+	} else if !xerrors.Is(err, gorm.ErrRecordNotFound) {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get role binding", err)
+		return
+	}
+
+	// Create role binding
+	binding := models.RoleBinding{
+		OrganizationID: models.UUID(id),
+		APIKeyID:       apiKey.ID,
+		ResourceID:     (*models.UUID)(input.ResourceID),
+		Role:           input.Role,
+	}
+	err = binding.Create(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to create role binding", err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.RoleBinding
+	err = copier.Copy(&output, &binding)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 )
 
@@ -23,8 +25,14 @@ func (controller *OrganizationController) DeleteOrganization(w http.ResponseWrit
 		return
 	}
 
+	// Check permission
+	if err := auth.Authorize(r, models.UUID(id), auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Fetch
-	organization, err := (&models.Organization{ID: models.UUID(id)}).Get(controller.DB)
+	organization, err := (&models.Organization{ID: models.UUID(id)}).Get(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
@@ -34,8 +42,15 @@ func (controller *OrganizationController) DeleteOrganization(w http.ResponseWrit
 		return
 	}
 
+	// Check precondition
+	if etag.PreconditionFailed(w, r, func() (interface{}, error) {
+		return makeOrganizationOutput(organization)
+	}) {
+		return
+	}
+
 	// Delete
-	err = organization.Delete(controller.DB)
+	err = organization.Delete(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to delete organization", err)
 		return
@@ -0,0 +1,59 @@
+package organization
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+)
+
+func (controller *OrganizationController) DeleteRoleBinding(w http.ResponseWriter, r *http.Request) {
+	// Get organization id and role binding id
+	vars := mux.Vars(r)
+	var organizationID, roleBindingID uuid.UUID
+	err := schemas.DecodeUUID(vars, "organizationID", &organizationID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid organization id", err)
+		return
+	}
+	err = schemas.DecodeUUID(vars, "roleBindingID", &roleBindingID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid role binding id", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.Authorize(r, models.UUID(organizationID), auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Fetch
+	binding, err := (&models.RoleBinding{ID: models.UUID(roleBindingID)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get role binding", err)
+		return
+	}
+	if binding.OrganizationID != models.UUID(organizationID) {
+		responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+		return
+	}
+
+	// Delete
+	err = binding.Delete(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to delete role binding", err)
+		return
+	}
+}
@@ -12,8 +12,10 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
 )
@@ -28,6 +30,12 @@ func (controller *OrganizationController) GetOrganization(w http.ResponseWriter,
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeAnywhere(r, models.UUID(id), auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Decode request parameters
 	var input schemas.GetOrganizationInput
 	err = schemas.DecodeQuery(r.URL.Query(), &input)
@@ -37,7 +45,7 @@ func (controller *OrganizationController) GetOrganization(w http.ResponseWriter,
 	}
 
 	// Fetch
-	organization, err := (&models.Organization{ID: models.UUID(id)}).Get(controller.DB)
+	organization, err := (&models.Organization{ID: models.UUID(id)}).Get(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
@@ -54,6 +62,18 @@ func (controller *OrganizationController) GetOrganization(w http.ResponseWriter,
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
 		return
 	}
+
+	// Check cache
+	tag, err := etag.Of(&output)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make etag", err)
+		return
+	}
+	if etag.NotModified(w, r, tag) {
+		return
+	}
+
+	// Select properties
 	if input.Properties != "" {
 		keys := strings.Split(input.Properties, ",")
 		output.Properties = organization.Properties.SelectKeys(keys)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/jinzhu/copier"
 
+	"github.com/tsujio/x-base/api/auth"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
 	"github.com/tsujio/x-base/api/utils/responses"
@@ -54,7 +55,15 @@ func (controller *OrganizationController) GetOrganizationList(w http.ResponseWri
 		Offset: (*input.Page - 1) * *input.PageSize,
 		Limit:  *input.PageSize,
 	}
-	organizations, totalCount, err := models.GetOrganizationList(controller.DB, &opts)
+	if p := auth.FromContext(r.Context()); p == nil || !p.Admin {
+		// Non-administrators can see only their own organization
+		if p == nil || p.OrganizationID == nil {
+			responses.SendErrorResponse(w, r, http.StatusForbidden, "Forbidden", nil)
+			return
+		}
+		opts.ID = p.OrganizationID
+	}
+	organizations, totalCount, err := models.GetOrganizationList(controller.db(r), &opts)
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get organizations", err)
 		return
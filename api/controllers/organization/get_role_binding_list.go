@@ -0,0 +1,92 @@
+package organization
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *OrganizationController) GetRoleBindingList(w http.ResponseWriter, r *http.Request) {
+	// Get organization id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "organizationID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid organization id", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.Authorize(r, models.UUID(id), auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Decode request parameters
+	var input schemas.GetRoleBindingListInput
+	err = schemas.DecodeQuery(r.URL.Query(), &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request parameter", err)
+		return
+	}
+
+	// Decode sort key
+	var sortKeys []schemas.GetListSortKey
+	err = schemas.DecodeGetListSort(input.Sort, &sortKeys)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid sort parameter", err)
+		return
+	}
+
+	if input.Page == nil {
+		input.Page = &defaultPage
+	}
+	if input.PageSize == nil {
+		input.PageSize = &defaultPageSize
+	}
+
+	// Fetch
+	var sortKeyOpt []models.GetListSortKey
+	if err := copier.Copy(&sortKeyOpt, &sortKeys); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make query option", err)
+		return
+	}
+	bindings, totalCount, err := models.GetRoleBindingList(controller.db(r), &models.GetRoleBindingListOpts{
+		OrganizationID: models.UUID(id),
+		APIKeyID:       (*models.UUID)(input.APIKeyID),
+		ResourceID:     (*models.UUID)(input.ResourceID),
+		Sort:           sortKeyOpt,
+		Offset:         (*input.Page - 1) * *input.PageSize,
+		Limit:          *input.PageSize,
+	})
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get role bindings", err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.RoleBindingList
+	err = copier.Copy(&output.RoleBindings, &bindings)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+	output.TotalCount = totalCount
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
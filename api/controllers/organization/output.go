@@ -0,0 +1,17 @@
+package organization
+
+import (
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+)
+
+func makeOrganizationOutput(organization *models.Organization) (*schemas.Organization, error) {
+	var output schemas.Organization
+	if err := copier.Copy(&output, organization); err != nil {
+		return nil, xerrors.Errorf("Failed to make output data: %w", err)
+	}
+	return &output, nil
+}
@@ -0,0 +1,83 @@
+package organization
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *OrganizationController) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	// Get organization id and api key id
+	vars := mux.Vars(r)
+	var organizationID, apiKeyID uuid.UUID
+	err := schemas.DecodeUUID(vars, "organizationID", &organizationID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid organization id", err)
+		return
+	}
+	err = schemas.DecodeUUID(vars, "apiKeyID", &apiKeyID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid api key id", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.Authorize(r, models.UUID(organizationID), auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Fetch
+	apiKey, err := (&models.APIKey{ID: models.UUID(apiKeyID)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get api key", err)
+		return
+	}
+	if apiKey.OrganizationID != models.UUID(organizationID) {
+		responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+		return
+	}
+
+	// Revoke
+	if apiKey.RevokedAt == nil {
+		now := time.Now().UTC()
+		apiKey.RevokedAt = &now
+		err = apiKey.Save(controller.db(r))
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to revoke api key", err)
+			return
+		}
+	}
+
+	// Convert to output schema
+	var output schemas.APIKey
+	err = copier.Copy(&output, &apiKey)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,127 @@
+package organization
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *OrganizationController) SearchTableFilesystemEntries(w http.ResponseWriter, r *http.Request) {
+	// Get organization id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "organizationID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid organization id", err)
+		return
+	}
+
+	// Check permission
+	rootIDs, err := auth.AuthorizedEntries(r, models.UUID(id), auth.PermissionRead)
+	if err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Decode request parameters
+	var input schemas.SearchTableFilesystemEntriesInput
+	err = schemas.DecodeQuery(r.URL.Query(), &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request parameter", err)
+		return
+	}
+	if input.Property == "" {
+		input.Property = "name"
+	}
+	if !models.PropertiesKeyPattern.MatchString(input.Property) {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid property key", nil)
+		return
+	}
+
+	// Decode sort key
+	var sortKeys []schemas.GetListSortKey
+	err = schemas.DecodeGetListSort(input.Sort, &sortKeys)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid sort parameter", err)
+		return
+	}
+
+	if input.Page == nil {
+		input.Page = &defaultPage
+	}
+	if input.PageSize == nil {
+		input.PageSize = &defaultPageSize
+	}
+
+	// Check existence
+	_, err = (&models.Organization{ID: models.UUID(id)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get organization", err)
+		return
+	}
+
+	// Search
+	var sortKeyOpt []models.GetListSortKey
+	if err := copier.Copy(&sortKeyOpt, &sortKeys); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make query option", err)
+		return
+	}
+	opts := models.SearchTableFilesystemEntriesOpts{
+		RootIDs:  rootIDs,
+		Type:     input.Type,
+		Property: input.Property,
+		Prefix:   input.Prefix,
+		Contains: input.Contains,
+		Sort:     sortKeyOpt,
+		Offset:   (*input.Page - 1) * *input.PageSize,
+		Limit:    *input.PageSize,
+	}
+	entries, totalCount, err := models.SearchTableFilesystemEntries(controller.db(r), models.UUID(id), &opts)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to search", err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.SearchResult
+	var e []schemas.FolderChild
+	if err := copier.Copy(&e, &entries); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+	if input.Properties != "" {
+		keys := strings.Split(input.Properties, ",")
+		for i := range e {
+			e[i].Properties = entries[i].Properties.SelectKeys(keys)
+			for j := range e[i].Path {
+				e[i].Path[j].Properties = entries[i].Path[j].Properties.SelectKeys(keys)
+			}
+		}
+	}
+	output.Entries = e
+	output.TotalCount = totalCount
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
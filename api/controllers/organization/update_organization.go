@@ -11,8 +11,10 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
 )
@@ -27,6 +29,12 @@ func (controller *OrganizationController) UpdateOrganization(w http.ResponseWrit
 		return
 	}
 
+	// Check permission
+	if err := auth.Authorize(r, models.UUID(id), auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Decode request body
 	var input schemas.UpdateOrganizationInput
 	err = schemas.DecodeJSON(r.Body, &input)
@@ -39,23 +47,33 @@ func (controller *OrganizationController) UpdateOrganization(w http.ResponseWrit
 		return
 	}
 
-	// Fetch
-	organization, err := (&models.Organization{ID: models.UUID(id)}).Get(controller.DB)
+	// Update, checking precondition on the locked organization
+	var organization *models.Organization
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		organization, err = (&models.Organization{ID: models.UUID(id)}).Get(models.ForUpdate(tx))
+		if err != nil {
+			return err
+		}
+		if err := etag.Check(r, func() (interface{}, error) {
+			return makeOrganizationOutput(organization)
+		}); err != nil {
+			return err
+		}
+
+		for k, v := range input.Properties {
+			organization.Properties[k] = v
+		}
+		return organization.Save(tx)
+	})
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
 			return
 		}
-		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get organization", err)
-		return
-	}
-
-	// Update
-	for k, v := range input.Properties {
-		organization.Properties[k] = v
-	}
-	err = organization.Save(controller.DB)
-	if err != nil {
+		if xerrors.Is(err, etag.ErrPreconditionFailed) {
+			etag.SendError(w, r, err)
+			return
+		}
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to save organization", err)
 		return
 	}
@@ -69,6 +87,10 @@ func (controller *OrganizationController) UpdateOrganization(w http.ResponseWrit
 	}
 
 	// Send response
+	if err := etag.Set(w, &output); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make etag", err)
+		return
+	}
 	err = json.NewEncoder(w).Encode(&output)
 	if err != nil {
 		logging.Error(fmt.Sprintf("%+v", err), r)
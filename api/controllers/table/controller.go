@@ -1,7 +1,27 @@
 package table
 
-import "gorm.io/gorm"
+import (
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/changes"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/jobs"
+	"github.com/tsujio/x-base/api/metrics"
+	"github.com/tsujio/x-base/api/quotas"
+)
 
 type TableController struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Runner  *jobs.Runner
+	Bus     *events.Bus
+	Changes *changes.Recorder
+	Quotas  *quotas.Checker
+	Metrics *metrics.Metrics
+}
+
+// db returns the db bound to the request context, so that queries are cancelled with the request.
+func (controller *TableController) db(r *http.Request) *gorm.DB {
+	return controller.DB.WithContext(r.Context())
 }
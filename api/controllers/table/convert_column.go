@@ -0,0 +1,128 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *TableController) ConvertColumn(w http.ResponseWriter, r *http.Request) {
+	// Get table id and column id
+	vars := mux.Vars(r)
+	var tableID, columnID uuid.UUID
+	err := schemas.DecodeUUID(vars, "tableID", &tableID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid table id", err)
+		return
+	}
+	err = schemas.DecodeUUID(vars, "columnID", &columnID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid column id", err)
+		return
+	}
+
+	// Decode request body
+	var input schemas.ConvertColumnInput
+	err = schemas.DecodeJSON(r.Body, &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if input.OnError == "" {
+		input.OnError = models.ConversionOnErrorAbort
+	}
+
+	// Fetch table
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Fetch columns
+	err = table.FetchColumns(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get columns", err)
+		return
+	}
+
+	// Find column
+	var column *models.Column
+	for _, c := range table.Columns {
+		if c.ID == models.UUID(columnID) {
+			column = &c
+			break
+		}
+	}
+	if column == nil {
+		responses.SendErrorResponse(w, r, http.StatusNotFound, "Column not found", nil)
+		return
+	}
+
+	// Report failures only of the records which the principal can see
+	cond, err := rowPolicyCondition(r, controller.db(r), table)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to apply table policies", err)
+		return
+	}
+
+	// Convert
+	result, err := column.ConvertType(controller.db(r), &models.ConvertColumnTypeOpts{
+		Type:    input.Type,
+		OnError: input.OnError,
+		DryRun:  input.DryRun,
+		Visible: cond,
+	})
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to convert column", err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.ColumnConversionResult
+	err = copier.Copy(&output, &result)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+	err = copier.Copy(&output.Column, &column)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Publish event
+	if output.Applied {
+		controller.Bus.Publish(events.New(events.TypeColumnUpdated, table.OrganizationID, &table.ID, &output.Column).In(table.ParentFolderID))
+	}
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
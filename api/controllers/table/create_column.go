@@ -11,7 +11,10 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
 	"github.com/tsujio/x-base/api/schemas"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
@@ -40,7 +43,7 @@ func (controller *TableController) CreateColumn(w http.ResponseWriter, r *http.R
 	}
 
 	// Fetch table
-	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.DB)
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
@@ -50,6 +53,12 @@ func (controller *TableController) CreateColumn(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Create column
 	var idx int
 	if input.Index != nil {
@@ -60,12 +69,20 @@ func (controller *TableController) CreateColumn(w http.ResponseWriter, r *http.R
 	column := &models.Column{
 		TableID:    table.ID,
 		Index:      idx,
+		Type:       input.Type,
 		Properties: input.Properties,
 	}
-	err = controller.DB.Transaction(func(tx *gorm.DB) error {
-		return column.Create(tx, false)
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		if err := column.Create(tx, false); err != nil {
+			return err
+		}
+		return controller.Quotas.CheckColumns(tx, table.ID, 0)
 	})
 	if err != nil {
+		if quotas.IsExceeded(err) {
+			quotas.SendError(w, r, err)
+			return
+		}
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to create column", err)
 		return
 	}
@@ -78,6 +95,9 @@ func (controller *TableController) CreateColumn(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeColumnCreated, table.OrganizationID, &table.ID, &output).In(table.ParentFolderID))
+
 	// Send response
 	err = json.NewEncoder(w).Encode(&output)
 	if err != nil {
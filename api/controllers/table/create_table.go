@@ -10,7 +10,10 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
 	"github.com/tsujio/x-base/api/schemas"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
@@ -36,8 +39,10 @@ func (controller *TableController) CreateTable(w http.ResponseWriter, r *http.Re
 	}
 
 	// Check parent folder
+	parent := &models.Folder{}
+	parent.OrganizationID = models.UUID(input.OrganizationID)
 	if input.ParentFolderID != nil && *input.ParentFolderID != uuid.Nil {
-		parent, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.DB)
+		p, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.db(r))
 		if err != nil {
 			if xerrors.Is(err, gorm.ErrRecordNotFound) {
 				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Parent folder not found", nil)
@@ -47,14 +52,21 @@ func (controller *TableController) CreateTable(w http.ResponseWriter, r *http.Re
 			return
 		}
 
-		if parent.OrganizationID != models.UUID(input.OrganizationID) {
+		if p.OrganizationID != models.UUID(input.OrganizationID) {
 			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Cannot create table as a child of another organization's folder", nil)
 			return
 		}
+		parent = p
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &parent.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
 	}
 
 	var table *models.Table
-	err = controller.DB.Transaction(func(tx *gorm.DB) error {
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
 		// Create table
 		t := &models.Table{
 			TableFilesystemEntry: models.TableFilesystemEntry{
@@ -74,6 +86,7 @@ func (controller *TableController) CreateTable(w http.ResponseWriter, r *http.Re
 				col := &models.Column{
 					TableID:    t.ID,
 					Index:      i,
+					Type:       c.Type,
 					Properties: c.Properties,
 				}
 				err := col.Create(tx, true)
@@ -88,23 +101,35 @@ func (controller *TableController) CreateTable(w http.ResponseWriter, r *http.Re
 			}
 		}
 
+		// Check quotas
+		if err := controller.Quotas.CheckTables(tx, t.OrganizationID, 0); err != nil {
+			return err
+		}
+		if err := controller.Quotas.CheckColumns(tx, t.ID, 0); err != nil {
+			return err
+		}
+
 		table = t
 
 		return nil
 	})
 	if err != nil {
+		if quotas.IsExceeded(err) {
+			quotas.SendError(w, r, err)
+			return
+		}
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to create table", err)
 		return
 	}
 
 	// Convert to output schema
 	var output schemas.Table
-	err = table.ComputePath(controller.DB)
+	err = table.ComputePath(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get path", err)
 		return
 	}
-	err = table.FetchColumns(controller.DB)
+	err = table.FetchColumns(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
 		return
@@ -115,6 +140,9 @@ func (controller *TableController) CreateTable(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeTableCreated, table.OrganizationID, &table.ID, &output).In(table.ParentFolderID))
+
 	// Send response
 	err = json.NewEncoder(w).Encode(&output)
 	if err != nil {
@@ -0,0 +1,99 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *TableController) CreateTablePolicy(w http.ResponseWriter, r *http.Request) {
+	// Get table id
+	vars := mux.Vars(r)
+	var tableID uuid.UUID
+	err := schemas.DecodeUUID(vars, "tableID", &tableID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid table id", err)
+		return
+	}
+
+	// Decode request body
+	var input schemas.CreateTablePolicyInput
+	err = schemas.DecodeJSON(r.Body, &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if result := models.ValidateProperties(input.Properties); result != "" {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+		return
+	}
+
+	// Fetch table
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Validate where
+	if err := table.FetchColumns(controller.db(r)); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
+		return
+	}
+	if err := validatePolicyWhere(input.Where, table); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid where", err)
+		return
+	}
+
+	// Create
+	where, err := json.Marshal(input.Where)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to encode where", err)
+		return
+	}
+	policy := models.TablePolicy{
+		TableID:    table.ID,
+		Where:      models.JSON(where),
+		Properties: input.Properties,
+	}
+	err = policy.Create(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to create policy", err)
+		return
+	}
+
+	// Convert to output schema
+	output, err := makeTablePolicyOutput(&policy)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Send response
+	err = json.NewEncoder(w).Encode(output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
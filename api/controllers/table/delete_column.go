@@ -8,8 +8,12 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/jobs"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 )
 
@@ -29,7 +33,7 @@ func (controller *TableController) DeleteColumn(w http.ResponseWriter, r *http.R
 	}
 
 	// Fetch table
-	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.DB)
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
@@ -39,8 +43,14 @@ func (controller *TableController) DeleteColumn(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Fetch columns
-	err = table.FetchColumns(controller.DB)
+	err = table.FetchColumns(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get columns", err)
 	}
@@ -58,12 +68,33 @@ func (controller *TableController) DeleteColumn(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Delete
-	err = controller.DB.Transaction(func(tx *gorm.DB) error {
-		return column.Delete(tx, false)
+	// Check precondition
+	if etag.PreconditionFailed(w, r, func() (interface{}, error) {
+		return makeColumnOutput(column)
+	}) {
+		return
+	}
+
+	// Delete, and save the job to purge column data so that it is resumed even if not started
+	var job *models.Job
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		if err := column.Delete(tx, false); err != nil {
+			return err
+		}
+		job, err = controller.Runner.Create(tx, table.OrganizationID, jobs.JobTypePurgeColumnData, &jobs.PurgeColumnDataParams{
+			TableID:  tableID,
+			ColumnID: columnID,
+		})
+		return err
 	})
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to delete column", err)
 		return
 	}
+
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeColumnDeleted, table.OrganizationID, &table.ID, &events.DeletedData{ID: columnID}).In(table.ParentFolderID))
+
+	// Purge column data in background
+	controller.Runner.Start(job)
 }
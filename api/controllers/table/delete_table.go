@@ -8,8 +8,11 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 )
 
@@ -24,7 +27,7 @@ func (controller *TableController) DeleteTable(w http.ResponseWriter, r *http.Re
 	}
 
 	// Fetch
-	table, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetTable(controller.DB)
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetTable(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
@@ -34,10 +37,26 @@ func (controller *TableController) DeleteTable(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Check precondition
+	if etag.PreconditionFailed(w, r, func() (interface{}, error) {
+		return makeTableOutput(controller.db(r), table)
+	}) {
+		return
+	}
+
 	// Delete
-	err = table.Delete(controller.DB)
+	err = table.Delete(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to delete table", err)
 		return
 	}
+
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeTableDeleted, table.OrganizationID, &table.ID, &events.DeletedData{ID: id}).In(table.ParentFolderID))
 }
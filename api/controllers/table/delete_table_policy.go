@@ -0,0 +1,70 @@
+package table
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+)
+
+func (controller *TableController) DeleteTablePolicy(w http.ResponseWriter, r *http.Request) {
+	// Get table id and policy id
+	vars := mux.Vars(r)
+	var tableID, policyID uuid.UUID
+	err := schemas.DecodeUUID(vars, "tableID", &tableID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid table id", err)
+		return
+	}
+	err = schemas.DecodeUUID(vars, "policyID", &policyID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid policy id", err)
+		return
+	}
+
+	// Fetch table
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Fetch policy
+	policy, err := (&models.TablePolicy{ID: models.UUID(policyID)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Policy not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get policy", err)
+		return
+	}
+	if policy.TableID != table.ID {
+		responses.SendErrorResponse(w, r, http.StatusNotFound, "Policy not found", nil)
+		return
+	}
+
+	// Delete
+	err = policy.Delete(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to delete policy", err)
+		return
+	}
+}
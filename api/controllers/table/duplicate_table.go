@@ -0,0 +1,165 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *TableController) DuplicateTable(w http.ResponseWriter, r *http.Request) {
+	// Get table id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "tableID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid table id", err)
+		return
+	}
+
+	// Decode request body
+	var input schemas.DuplicateTableInput
+	err = schemas.DecodeJSON(r.Body, &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if result := models.ValidateProperties(input.Properties); result != "" {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+		return
+	}
+
+	// Fetch
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetTable(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Duplicate into the folder of the source table unless the destination is given
+	if input.ParentFolderID == nil && table.ParentFolderID != nil {
+		parentFolderID := uuid.UUID(*table.ParentFolderID)
+		input.ParentFolderID = &parentFolderID
+	}
+
+	// Check destination folder
+	if input.ParentFolderID != nil && *input.ParentFolderID != uuid.Nil {
+		parent, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.db(r))
+		if err != nil {
+			if xerrors.Is(err, gorm.ErrRecordNotFound) {
+				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Destination folder not found", nil)
+				return
+			}
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get destination folder", err)
+			return
+		}
+
+		if parent.OrganizationID != table.OrganizationID {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Cannot duplicate to another organization", nil)
+			return
+		}
+	}
+
+	// Check permission on destination folder
+	destination := models.TableFilesystemEntry{OrganizationID: table.OrganizationID}
+	if input.ParentFolderID != nil {
+		destination.ID = models.UUID(*input.ParentFolderID)
+	}
+	if err := auth.AuthorizeEntry(r, controller.db(r), &destination, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+	if input.IncludeRecords {
+		applied, err := auth.HasRowPoliciesApplied(r, controller.db(r), &table.TableFilesystemEntry)
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to check table policies", err)
+			return
+		}
+		if applied {
+			responses.SendErrorResponse(w, r, http.StatusForbidden, "Cannot copy records of tables with policies", nil)
+			return
+		}
+	}
+
+	// Load the storage usage before writing so that it does not include uncommitted records
+	if err := controller.Quotas.LoadStorage(controller.db(r), table.OrganizationID); err != nil {
+		quotas.SendError(w, r, err)
+		return
+	}
+
+	// Duplicate
+	var dup *models.Table
+	var size int64
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		dup, err = table.Duplicate(tx, &models.DuplicateTableOpts{
+			ParentFolderID: (*models.UUID)(input.ParentFolderID),
+			Properties:     input.Properties,
+			IncludeRecords: input.IncludeRecords,
+		})
+		if err != nil {
+			return err
+		}
+		size, err = controller.Quotas.CheckCopy(tx, &dup.TableFilesystemEntry, input.IncludeRecords)
+		return err
+	})
+	if err != nil {
+		if quotas.IsExceeded(err) {
+			quotas.SendError(w, r, err)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to duplicate table", err)
+		return
+	}
+	controller.Quotas.AddStorage(dup.OrganizationID, size)
+
+	// Convert to output schema
+	var output schemas.Table
+	err = dup.ComputePath(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get path", err)
+		return
+	}
+	err = dup.FetchColumns(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
+		return
+	}
+	err = copier.Copy(&output, &dup)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeTableCreated, dup.OrganizationID, &dup.ID, &output).In(dup.ParentFolderID))
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
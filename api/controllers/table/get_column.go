@@ -0,0 +1,96 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *TableController) GetColumn(w http.ResponseWriter, r *http.Request) {
+	// Get table id and column id
+	vars := mux.Vars(r)
+	var tableID, columnID uuid.UUID
+	err := schemas.DecodeUUID(vars, "tableID", &tableID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid table id", err)
+		return
+	}
+	err = schemas.DecodeUUID(vars, "columnID", &columnID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid column id", err)
+		return
+	}
+
+	// Fetch table
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Fetch columns
+	err = table.FetchColumns(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get columns", err)
+		return
+	}
+
+	// Find column
+	var column *models.Column
+	for i := range table.Columns {
+		if table.Columns[i].ID == models.UUID(columnID) {
+			column = &table.Columns[i]
+			break
+		}
+	}
+	if column == nil {
+		responses.SendErrorResponse(w, r, http.StatusNotFound, "Column not found", nil)
+		return
+	}
+
+	// Convert to output schema
+	output, err := makeColumnOutput(column)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Check cache
+	tag, err := etag.Of(output)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make etag", err)
+		return
+	}
+	if etag.NotModified(w, r, tag) {
+		return
+	}
+
+	// Send response
+	err = json.NewEncoder(w).Encode(output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
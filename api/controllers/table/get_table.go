@@ -12,8 +12,10 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
 )
@@ -37,7 +39,7 @@ func (controller *TableController) GetTable(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Fetch
-	table, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetTable(controller.DB)
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetTable(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
@@ -47,14 +49,20 @@ func (controller *TableController) GetTable(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Convert to output schema
 	var output schemas.Table
-	err = table.ComputePath(controller.DB)
+	err = table.ComputePath(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get path", err)
 		return
 	}
-	err = table.FetchColumns(controller.DB)
+	err = table.FetchColumns(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
 		return
@@ -64,6 +72,18 @@ func (controller *TableController) GetTable(w http.ResponseWriter, r *http.Reque
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
 		return
 	}
+
+	// Check cache
+	tag, err := etag.Of(&output)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make etag", err)
+		return
+	}
+	if etag.NotModified(w, r, tag) {
+		return
+	}
+
+	// Select properties
 	if input.Properties != "" {
 		keys := strings.Split(input.Properties, ",")
 		output.Properties = table.Properties.SelectKeys(keys)
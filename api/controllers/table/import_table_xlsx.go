@@ -0,0 +1,225 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/api/utils/xlsx"
+	"github.com/tsujio/x-base/logging"
+)
+
+const (
+	maxImportFileSize     = 32 << 20
+	importRecordBatchSize = 500
+)
+
+func (controller *TableController) ImportTableXLSX(w http.ResponseWriter, r *http.Request) {
+	// Get table id
+	vars := mux.Vars(r)
+	var tableID uuid.UUID
+	err := schemas.DecodeUUID(vars, "tableID", &tableID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid table id", err)
+		return
+	}
+
+	// Decode request parameters
+	var input schemas.ImportTableXLSXInput
+	err = schemas.DecodeQuery(r.URL.Query(), &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request parameter", err)
+		return
+	}
+
+	// Fetch table
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+	if err := table.FetchColumns(controller.db(r)); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
+		return
+	}
+	cond, err := rowPolicyCondition(r, controller.db(r), table)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to apply table policies", err)
+		return
+	}
+
+	// Read workbook after the permission check so as not to buffer bodies of forbidden requests
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	var source io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		err := r.ParseMultipartForm(maxImportFileSize)
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+		defer file.Close()
+		source = file
+	}
+	f, err := excelize.OpenReader(source)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid xlsx file", err)
+		return
+	}
+	defer f.Close()
+	header, records, err := xlsx.ReadSheet(f, input.Sheet)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid sheet", err)
+		return
+	}
+
+	// Map header to insert columns
+	columns, err := convertXLSXHeaderToInsertColumns(header, table)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid header", err)
+		return
+	}
+
+	// Load the storage usage before writing so that it does not include uncommitted records
+	if err := controller.Quotas.LoadStorage(controller.db(r), table.OrganizationID); err != nil {
+		quotas.SendError(w, r, err)
+		return
+	}
+
+	// Insert
+	var ids []models.UUID
+	eventID := models.UUID(uuid.New())
+	var size int64
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		for i := 0; i < len(records); i += importRecordBatchSize {
+			q := schemas.InsertQuery{
+				Columns: columns,
+			}
+			for j := i; j < i+importRecordBatchSize && j < len(records); j++ {
+				var record []schemas.ValueExpr
+				for _, v := range records[j] {
+					record = append(record, schemas.ValueExpr{Value: v})
+				}
+				q.Values = append(q.Values, record)
+			}
+
+			iq, err := convertToInsertQuery(&q, table)
+			if err != nil {
+				return xerrors.Errorf("Failed to convert query: %w", err)
+			}
+			batchIDs, err := iq.Execute(tx)
+			if err != nil {
+				return xerrors.Errorf("Failed to execute query: %w", err)
+			}
+			ids = append(ids, batchIDs...)
+		}
+		if err := checkRowPolicy(tx, table, ids, cond); err != nil {
+			return err
+		}
+		size, err = controller.checkRecordQuotas(tx, table, ids)
+		if err != nil {
+			return err
+		}
+		return captureRecords(tx, eventID, models.ChangePhaseAfter, table, ids)
+	})
+	if err != nil {
+		if xerrors.Is(err, errPolicyViolation) {
+			responses.SendErrorResponse(w, r, http.StatusForbidden, errPolicyViolation.Error(), nil)
+			return
+		}
+		if quotas.IsExceeded(err) {
+			quotas.SendError(w, r, err)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to import records", err)
+		return
+	}
+	controller.Quotas.AddStorage(table.OrganizationID, size)
+
+	// Convert to output schema
+	var output schemas.InsertQueryResult
+	err = copier.Copy(&output.RecordIDs, &ids)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Publish event
+	controller.publishRecordEvent(eventID, events.TypeRecordInserted, table, ids)
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func convertXLSXHeaderToInsertColumns(header []string, table *models.Table) ([]interface{}, error) {
+	var columns []interface{}
+	for _, h := range header {
+		if strings.HasPrefix(h, "property.") {
+			columns = append(columns, schemas.PropertyExpr{
+				Key: h[len("property."):],
+			})
+			continue
+		}
+
+		var matched []models.Column
+		if id, err := uuid.Parse(h); err == nil {
+			for _, c := range table.Columns {
+				if c.ID == models.UUID(id) {
+					matched = append(matched, c)
+				}
+			}
+		}
+		if len(matched) == 0 {
+			for _, c := range table.Columns {
+				if name, ok := c.Properties["name"].(string); ok && name == h {
+					matched = append(matched, c)
+				}
+			}
+		}
+
+		switch len(matched) {
+		case 0:
+			return nil, fmt.Errorf("Column not found: %s", h)
+		case 1:
+			columns = append(columns, schemas.ColumnExpr{
+				ColumnID: uuid.UUID(matched[0].ID),
+			})
+		default:
+			return nil, fmt.Errorf("Ambiguous column name: %s", h)
+		}
+	}
+	return columns, nil
+}
@@ -0,0 +1,33 @@
+package table
+
+import (
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+)
+
+// makeTableOutput converts the table with its path and columns to the output schema.
+func makeTableOutput(db *gorm.DB, table *models.Table) (*schemas.Table, error) {
+	if err := table.ComputePath(db); err != nil {
+		return nil, xerrors.Errorf("Failed to get path: %w", err)
+	}
+	if err := table.FetchColumns(db); err != nil {
+		return nil, xerrors.Errorf("Failed to fetch columns: %w", err)
+	}
+	var output schemas.Table
+	if err := copier.Copy(&output, table); err != nil {
+		return nil, xerrors.Errorf("Failed to make output data: %w", err)
+	}
+	return &output, nil
+}
+
+func makeColumnOutput(column *models.Column) (*schemas.Column, error) {
+	var output schemas.Column
+	if err := copier.Copy(&output, column); err != nil {
+		return nil, xerrors.Errorf("Failed to make output data: %w", err)
+	}
+	return &output, nil
+}
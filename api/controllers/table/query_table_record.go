@@ -12,7 +12,11 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
+	"github.com/tsujio/x-base/api/replicas"
 	"github.com/tsujio/x-base/api/schemas"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
@@ -36,7 +40,7 @@ func (controller *TableController) QueryTableRecord(w http.ResponseWriter, r *ht
 	}
 
 	// Fetch table
-	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.DB)
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
@@ -45,15 +49,32 @@ func (controller *TableController) QueryTableRecord(w http.ResponseWriter, r *ht
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
 		return
 	}
-	if err := table.FetchColumns(controller.DB); err != nil {
+
+	// Check permission
+	perm := auth.PermissionWrite
+	if _, ok := query.(*schemas.SelectQuery); ok {
+		perm = auth.PermissionRead
+	}
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, perm); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+	if err := table.FetchColumns(controller.db(r)); err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
 		return
 	}
+	cond, err := rowPolicyCondition(r, controller.db(r), table)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to apply table policies", err)
+		return
+	}
 
 	// Query
 	var output interface{}
 	switch q := query.(type) {
 	case *schemas.InsertQuery:
+		controller.Metrics.ObserveQuery("insert")
+
 		// Convert
 		iq, err := convertToInsertQuery(q, table)
 		if err != nil {
@@ -61,9 +82,39 @@ func (controller *TableController) QueryTableRecord(w http.ResponseWriter, r *ht
 			return
 		}
 
+		// Load the storage usage before writing so that it does not include uncommitted records
+		if err := controller.Quotas.LoadStorage(controller.db(r), table.OrganizationID); err != nil {
+			quotas.SendError(w, r, err)
+			return
+		}
+
 		// Execute
-		ids, err := iq.Execute(controller.DB)
+		eventID := models.UUID(uuid.New())
+		var ids []models.UUID
+		var size int64
+		err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+			ids, err = iq.Execute(tx)
+			if err != nil {
+				return err
+			}
+			if err := checkRowPolicy(tx, table, ids, cond); err != nil {
+				return err
+			}
+			size, err = controller.checkRecordQuotas(tx, table, ids)
+			if err != nil {
+				return err
+			}
+			return captureRecords(tx, eventID, models.ChangePhaseAfter, table, ids)
+		})
 		if err != nil {
+			if xerrors.Is(err, errPolicyViolation) {
+				responses.SendErrorResponse(w, r, http.StatusForbidden, errPolicyViolation.Error(), nil)
+				return
+			}
+			if quotas.IsExceeded(err) {
+				quotas.SendError(w, r, err)
+				return
+			}
 			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to execute query", err)
 			return
 		}
@@ -76,17 +127,24 @@ func (controller *TableController) QueryTableRecord(w http.ResponseWriter, r *ht
 			return
 		}
 		output = schema
+		controller.Quotas.AddStorage(table.OrganizationID, size)
+
+		// Publish event
+		controller.publishRecordEvent(eventID, events.TypeRecordInserted, table, ids)
 	case *schemas.SelectQuery:
+		controller.Metrics.ObserveQuery("select")
+
 		// Convert
 		sq, err := convertToSelectQuery(q, table)
 		if err != nil {
 			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to convert query", err)
 			return
 		}
+		sq.Where = andCondition(sq.Where, cond)
 
-		// Execute
+		// Execute on a read replica if any
 		var result []map[string]interface{}
-		err = sq.Execute(controller.DB, &result)
+		err = sq.Execute(controller.db(replicas.ReadFromReplica(r)), &result)
 		if err != nil {
 			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to execute query", err)
 			return
@@ -106,16 +164,65 @@ func (controller *TableController) QueryTableRecord(w http.ResponseWriter, r *ht
 		schema.Limit = q.Limit
 		output = schema
 	case *schemas.UpdateQuery:
+		controller.Metrics.ObserveQuery("update")
+
 		// Convert
 		sq, err := convertToUpdateQuery(q, table)
 		if err != nil {
 			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to convert query", err)
 			return
 		}
+		sq.Where = andCondition(sq.Where, cond)
+
+		// Load the storage usage before writing so that it does not include uncommitted records
+		if err := controller.Quotas.LoadStorage(controller.db(r), table.OrganizationID); err != nil {
+			quotas.SendError(w, r, err)
+			return
+		}
 
 		// Execute
-		err = sq.Execute(controller.DB)
+		eventID := models.UUID(uuid.New())
+		var ids []models.UUID
+		var delta int64
+		err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+			ids, err = sq.SelectIDs(tx)
+			if err != nil {
+				return xerrors.Errorf("Failed to get target record ids: %w", err)
+			}
+			before, err := controller.Quotas.RecordsBytes(tx, table.ID, ids)
+			if err != nil {
+				return err
+			}
+			if err := captureRecords(tx, eventID, models.ChangePhaseBefore, table, ids); err != nil {
+				return err
+			}
+			if err := sq.Execute(tx); err != nil {
+				return err
+			}
+			if err := checkRowPolicy(tx, table, ids, cond); err != nil {
+				return err
+			}
+			after, err := controller.Quotas.RecordsBytes(tx, table.ID, ids)
+			if err != nil {
+				return err
+			}
+			delta = after - before
+			if delta > 0 {
+				if err := controller.Quotas.CheckStorage(tx, table.OrganizationID, delta); err != nil {
+					return err
+				}
+			}
+			return captureRecords(tx, eventID, models.ChangePhaseAfter, table, ids)
+		})
 		if err != nil {
+			if xerrors.Is(err, errPolicyViolation) {
+				responses.SendErrorResponse(w, r, http.StatusForbidden, errPolicyViolation.Error(), nil)
+				return
+			}
+			if quotas.IsExceeded(err) {
+				quotas.SendError(w, r, err)
+				return
+			}
 			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to execute query", err)
 			return
 		}
@@ -123,16 +230,39 @@ func (controller *TableController) QueryTableRecord(w http.ResponseWriter, r *ht
 		// Convert to output schema
 		var schema schemas.UpdateQueryResult
 		output = schema
+		controller.Quotas.AddStorage(table.OrganizationID, delta)
+
+		// Publish event
+		controller.publishRecordEvent(eventID, events.TypeRecordUpdated, table, ids)
 	case *schemas.DeleteQuery:
+		controller.Metrics.ObserveQuery("delete")
+
 		// Convert
 		sq, err := convertToDeleteQuery(q, table)
 		if err != nil {
 			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to convert query", err)
 			return
 		}
+		sq.Where = andCondition(sq.Where, cond)
 
 		// Execute
-		err = sq.Execute(controller.DB)
+		eventID := models.UUID(uuid.New())
+		var ids []models.UUID
+		var size int64
+		err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+			ids, err = sq.SelectIDs(tx)
+			if err != nil {
+				return xerrors.Errorf("Failed to get target record ids: %w", err)
+			}
+			size, err = controller.Quotas.RecordsBytes(tx, table.ID, ids)
+			if err != nil {
+				return err
+			}
+			if err := captureRecords(tx, eventID, models.ChangePhaseBefore, table, ids); err != nil {
+				return err
+			}
+			return sq.Execute(tx)
+		})
 		if err != nil {
 			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to execute query", err)
 			return
@@ -141,6 +271,10 @@ func (controller *TableController) QueryTableRecord(w http.ResponseWriter, r *ht
 		// Convert to output schema
 		var schema schemas.DeleteQueryResult
 		output = schema
+		controller.Quotas.AddStorage(table.OrganizationID, -size)
+
+		// Publish event
+		controller.publishRecordEvent(eventID, events.TypeRecordDeleted, table, ids)
 	default:
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Invalid query type (application error)", nil)
 		return
@@ -154,6 +288,48 @@ func (controller *TableController) QueryTableRecord(w http.ResponseWriter, r *ht
 	}
 }
 
+// checkRecordQuotas checks quotas after inserting records and returns the size of them,
+// which is added to the storage usage after commit.
+func (controller *TableController) checkRecordQuotas(db *gorm.DB, table *models.Table, ids []models.UUID) (int64, error) {
+	if err := controller.Quotas.CheckRecords(db, table.ID, 0); err != nil {
+		return 0, err
+	}
+	size, err := controller.Quotas.RecordsBytes(db, table.ID, ids)
+	if err != nil {
+		return 0, err
+	}
+	return size, controller.Quotas.CheckStorage(db, table.OrganizationID, size)
+}
+
+// captureRecords saves the state of the records for the event if the table has policies,
+// so that streams decide the visibility of the event by the records at the time of the change.
+func captureRecords(db *gorm.DB, eventID models.UUID, phase string, table *models.Table, ids []models.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	policies, err := models.GetTablePolicies(db, table.ID)
+	if err != nil {
+		return xerrors.Errorf("Failed to get policies: %w", err)
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+	return models.CaptureChangeEventRecords(db, eventID, phase, table.ID, ids)
+}
+
+func (controller *TableController) publishRecordEvent(eventID models.UUID, eventType string, table *models.Table, ids []models.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	data := &events.RecordsData{}
+	for _, id := range ids {
+		data.RecordIDs = append(data.RecordIDs, uuid.UUID(id))
+	}
+	e := events.New(eventType, table.OrganizationID, &table.ID, data).In(table.ParentFolderID)
+	e.ID = uuid.UUID(eventID)
+	controller.Bus.Publish(e)
+}
+
 func convertToInsertQuery(query *schemas.InsertQuery, table *models.Table) (*models.InsertQuery, error) {
 	q := models.InsertQuery{}
 
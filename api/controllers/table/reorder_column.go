@@ -11,6 +11,8 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
 	"github.com/tsujio/x-base/api/utils/responses"
@@ -36,7 +38,7 @@ func (controller *TableController) ReorderColumn(w http.ResponseWriter, r *http.
 	}
 
 	// Fetch table
-	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.DB)
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
@@ -46,12 +48,18 @@ func (controller *TableController) ReorderColumn(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Reorder columns
 	var order []models.UUID
 	for _, id := range input.Order {
 		order = append(order, models.UUID(id))
 	}
-	err = models.ReorderColumns(controller.DB, table.ID, order)
+	err = models.ReorderColumns(controller.db(r), table.ID, order)
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to reorder columns", err)
 		return
@@ -59,7 +67,7 @@ func (controller *TableController) ReorderColumn(w http.ResponseWriter, r *http.
 
 	// Convert to output schema
 	var output schemas.ColumnList
-	err = table.FetchColumns(controller.DB)
+	err = table.FetchColumns(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
 		return
@@ -70,6 +78,11 @@ func (controller *TableController) ReorderColumn(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Publish events
+	for i := range output.Columns {
+		controller.Bus.Publish(events.New(events.TypeColumnUpdated, table.OrganizationID, &table.ID, &output.Columns[i]).In(table.ParentFolderID))
+	}
+
 	// Send response
 	err = json.NewEncoder(w).Encode(&output)
 	if err != nil {
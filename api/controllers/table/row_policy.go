@@ -0,0 +1,107 @@
+package table
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+)
+
+var errPolicyViolation = errors.New("Records do not satisfy table policies")
+
+// rowPolicyCondition returns the condition of the table policies bound to the attributes of the principal of the request.
+// It returns nil if no policy is applied.
+func rowPolicyCondition(r *http.Request, db *gorm.DB, table *models.Table) (models.SQLBuilder, error) {
+	bypass, err := auth.BypassesRowPolicies(r, db, &table.TableFilesystemEntry)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to check permission: %w", err)
+	}
+	if bypass {
+		return nil, nil
+	}
+
+	policies, err := models.GetTablePolicies(db, table.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get policies: %w", err)
+	}
+
+	var attributes map[string]interface{}
+	if p := auth.FromContext(r.Context()); p != nil {
+		attributes = p.Attributes
+	}
+
+	var cond models.SQLBuilder
+	for _, policy := range policies {
+		e, err := convertPolicyWhere(policy.Where, attributes, table)
+		if err != nil {
+			return nil, xerrors.Errorf("Invalid policy (id=%s): %w", policy.ID.String(), err)
+		}
+		cond = andCondition(cond, e)
+	}
+	return cond, nil
+}
+
+func convertPolicyWhere(where models.JSON, attributes map[string]interface{}, table *models.Table) (models.SQLBuilder, error) {
+	var input interface{}
+	if err := json.Unmarshal(where, &input); err != nil {
+		return nil, xerrors.Errorf("Failed to decode where: %w", err)
+	}
+	expr, err := schemas.DecodePolicyWhere(input, attributes)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to decode where: %w", err)
+	}
+	return convertToExpr(expr, table)
+}
+
+func andCondition(where, cond models.SQLBuilder) models.SQLBuilder {
+	if where == nil {
+		return cond
+	}
+	if cond == nil {
+		return where
+	}
+	return models.AndExpr{Op1: where, Op2: cond}
+}
+
+// checkRowPolicy returns errPolicyViolation if any of the records does not satisfy cond.
+func checkRowPolicy(db *gorm.DB, table *models.Table, ids []models.UUID, cond models.SQLBuilder) error {
+	if cond == nil {
+		return nil
+	}
+	violations, err := models.FindRecordIDsNotMatching(db, table.ID, ids, cond)
+	if err != nil {
+		return xerrors.Errorf("Failed to check policies: %w", err)
+	}
+	if len(violations) > 0 {
+		return errPolicyViolation
+	}
+	return nil
+}
+
+// validatePolicyWhere checks that where is a valid expression on the columns of the table.
+func validatePolicyWhere(where interface{}, table *models.Table) error {
+	expr, err := schemas.DecodePolicyWhere(where, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := convertToExpr(expr, table); err != nil {
+		return err
+	}
+	return nil
+}
+
+func makeTablePolicyOutput(policy *models.TablePolicy) (*schemas.TablePolicy, error) {
+	var output schemas.TablePolicy
+	if err := copier.Copy(&output, policy); err != nil {
+		return nil, err
+	}
+	output.Where = json.RawMessage(policy.Where)
+	return &output, nil
+}
@@ -0,0 +1,95 @@
+package table
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/changes"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *TableController) StreamTableEvents(w http.ResponseWriter, r *http.Request) {
+	// Get table id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "tableID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid table id", err)
+		return
+	}
+
+	// Decode request parameters
+	var input schemas.StreamEventsInput
+	err = schemas.DecodeQuery(r.URL.Query(), &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request parameter", err)
+		return
+	}
+	err = input.DecodeLastEventIDHeader(r.Header)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request header", err)
+		return
+	}
+
+	// Fetch
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetTable(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionRead); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+	opts := changes.StreamOpts{
+		TableID: &table.ID,
+	}
+	opts.AfterSeq, err = controller.Changes.ResolveAfterSeq(input.LastEventID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get latest event", err)
+		return
+	}
+
+	// Send only the records which satisfy the table policies
+	err = table.FetchColumns(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
+		return
+	}
+	cond, err := rowPolicyCondition(r, controller.db(r), table)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to apply table policies", err)
+		return
+	}
+	if cond != nil {
+		db := controller.db(r)
+		opts.Filter = changes.RecordsFilter(func(e *models.ChangeEvent, ids []models.UUID) ([]models.UUID, error) {
+			return models.FindChangeEventRecordIDsMatching(db, e.ID, ids, cond)
+		})
+	}
+
+	// Stream
+	err = controller.Changes.Stream(w, r, &opts)
+	if err != nil {
+		if xerrors.Is(err, changes.ErrStreamingNotSupported) {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to start stream", err)
+			return
+		}
+		logging.Error(fmt.Sprintf("%+v", err), r)
+	}
+}
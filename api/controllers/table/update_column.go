@@ -11,8 +11,11 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
 )
@@ -45,7 +48,7 @@ func (controller *TableController) UpdateColumn(w http.ResponseWriter, r *http.R
 	}
 
 	// Fetch table
-	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.DB)
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
@@ -55,8 +58,14 @@ func (controller *TableController) UpdateColumn(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Fetch columns
-	err = table.FetchColumns(controller.DB)
+	err = table.FetchColumns(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get columns", err)
 	}
@@ -74,17 +83,35 @@ func (controller *TableController) UpdateColumn(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Update
-	if input.Index != nil {
-		column.Index = *input.Index
-	}
-	for k, v := range input.Properties {
-		column.Properties[k] = v
-	}
-	err = controller.DB.Transaction(func(tx *gorm.DB) error {
+	// Update, checking precondition on the locked column
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		column, err = (&models.Column{ID: column.ID}).Get(models.ForUpdate(tx))
+		if err != nil {
+			return err
+		}
+		if err := etag.Check(r, func() (interface{}, error) {
+			return makeColumnOutput(column)
+		}); err != nil {
+			return err
+		}
+
+		if input.Index != nil {
+			column.Index = *input.Index
+		}
+		for k, v := range input.Properties {
+			column.Properties[k] = v
+		}
 		return column.Save(tx, false)
 	})
 	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Column not found", nil)
+			return
+		}
+		if xerrors.Is(err, etag.ErrPreconditionFailed) {
+			etag.SendError(w, r, err)
+			return
+		}
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to update column", err)
 		return
 	}
@@ -97,7 +124,14 @@ func (controller *TableController) UpdateColumn(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeColumnUpdated, table.OrganizationID, &table.ID, &output).In(table.ParentFolderID))
+
 	// Send response
+	if err := etag.Set(w, &output); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make etag", err)
+		return
+	}
 	err = json.NewEncoder(w).Encode(&output)
 	if err != nil {
 		logging.Error(fmt.Sprintf("%+v", err), r)
@@ -11,8 +11,11 @@ import (
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
 	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/etag"
 	"github.com/tsujio/x-base/api/utils/responses"
 	"github.com/tsujio/x-base/logging"
 )
@@ -40,7 +43,7 @@ func (controller *TableController) UpdateTable(w http.ResponseWriter, r *http.Re
 	}
 
 	// Fetch
-	table, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetTable(controller.DB)
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(id)}).GetTable(controller.db(r))
 	if err != nil {
 		if xerrors.Is(err, gorm.ErrRecordNotFound) {
 			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
@@ -50,9 +53,15 @@ func (controller *TableController) UpdateTable(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionWrite); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
 	// Check destination folder
 	if input.ParentFolderID != nil && *input.ParentFolderID != uuid.Nil {
-		parent, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.DB)
+		parent, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID)}).GetFolder(controller.db(r))
 		if err != nil {
 			if xerrors.Is(err, gorm.ErrRecordNotFound) {
 				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Destination folder not found", nil)
@@ -68,27 +77,56 @@ func (controller *TableController) UpdateTable(w http.ResponseWriter, r *http.Re
 		}
 	}
 
-	// Update
+	// Check permission on destination folder
 	if input.ParentFolderID != nil {
-		table.ParentFolderID = (*models.UUID)(input.ParentFolderID)
-	}
-	for k, v := range input.Properties {
-		table.Properties[k] = v
+		destination := models.TableFilesystemEntry{ID: models.UUID(*input.ParentFolderID), OrganizationID: table.OrganizationID}
+		if err := auth.AuthorizeEntry(r, controller.db(r), &destination, auth.PermissionWrite); err != nil {
+			auth.SendError(w, r, err)
+			return
+		}
 	}
-	err = table.Save(controller.DB)
+
+	// Update, checking precondition on the locked table
+	err = controller.db(r).Transaction(func(tx *gorm.DB) error {
+		table, err = (&models.TableFilesystemEntry{ID: table.ID}).GetTable(models.ForUpdate(tx))
+		if err != nil {
+			return err
+		}
+		if err := etag.Check(r, func() (interface{}, error) {
+			return makeTableOutput(tx, table)
+		}); err != nil {
+			return err
+		}
+
+		if input.ParentFolderID != nil {
+			table.ParentFolderID = (*models.UUID)(input.ParentFolderID)
+		}
+		for k, v := range input.Properties {
+			table.Properties[k] = v
+		}
+		return table.Save(tx)
+	})
 	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		if xerrors.Is(err, etag.ErrPreconditionFailed) {
+			etag.SendError(w, r, err)
+			return
+		}
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Invalid to save table", err)
 		return
 	}
 
 	// Convert to output schema
 	var output schemas.Table
-	err = table.ComputePath(controller.DB)
+	err = table.ComputePath(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get path", err)
 		return
 	}
-	err = table.FetchColumns(controller.DB)
+	err = table.FetchColumns(controller.db(r))
 	if err != nil {
 		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
 		return
@@ -99,7 +137,14 @@ func (controller *TableController) UpdateTable(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Publish event
+	controller.Bus.Publish(events.New(events.TypeTableUpdated, table.OrganizationID, &table.ID, &output).In(table.ParentFolderID))
+
 	// Send response
+	if err := etag.Set(w, &output); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make etag", err)
+		return
+	}
 	err = json.NewEncoder(w).Encode(&output)
 	if err != nil {
 		logging.Error(fmt.Sprintf("%+v", err), r)
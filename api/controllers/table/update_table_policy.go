@@ -0,0 +1,121 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *TableController) UpdateTablePolicy(w http.ResponseWriter, r *http.Request) {
+	// Get table id and policy id
+	vars := mux.Vars(r)
+	var tableID, policyID uuid.UUID
+	err := schemas.DecodeUUID(vars, "tableID", &tableID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid table id", err)
+		return
+	}
+	err = schemas.DecodeUUID(vars, "policyID", &policyID)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid policy id", err)
+		return
+	}
+
+	// Decode request body
+	var input schemas.UpdateTablePolicyInput
+	err = schemas.DecodeJSON(r.Body, &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if result := models.ValidateProperties(input.Properties); result != "" {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+		return
+	}
+
+	// Fetch table
+	table, err := (&models.TableFilesystemEntry{ID: models.UUID(tableID)}).GetTable(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Table not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.AuthorizeEntry(r, controller.db(r), &table.TableFilesystemEntry, auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Fetch policy
+	policy, err := (&models.TablePolicy{ID: models.UUID(policyID)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Policy not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get policy", err)
+		return
+	}
+	if policy.TableID != table.ID {
+		responses.SendErrorResponse(w, r, http.StatusNotFound, "Policy not found", nil)
+		return
+	}
+
+	// Update
+	if input.Where != nil {
+		if err := table.FetchColumns(controller.db(r)); err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to fetch columns", err)
+			return
+		}
+		if err := validatePolicyWhere(input.Where, table); err != nil {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid where", err)
+			return
+		}
+		where, err := json.Marshal(input.Where)
+		if err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to encode where", err)
+			return
+		}
+		policy.Where = models.JSON(where)
+	}
+	if policy.Properties == nil {
+		policy.Properties = make(models.Properties)
+	}
+	for k, v := range input.Properties {
+		policy.Properties[k] = v
+	}
+	err = policy.Save(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to update policy", err)
+		return
+	}
+
+	// Convert to output schema
+	output, err := makeTablePolicyOutput(policy)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Send response
+	err = json.NewEncoder(w).Encode(output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
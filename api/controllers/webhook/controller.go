@@ -0,0 +1,19 @@
+package webhook
+
+import (
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/webhooks"
+)
+
+type WebhookController struct {
+	DB     *gorm.DB
+	Config *webhooks.Config
+}
+
+// db returns the db bound to the request context, so that queries are cancelled with the request.
+func (controller *WebhookController) db(r *http.Request) *gorm.DB {
+	return controller.DB.WithContext(r.Context())
+}
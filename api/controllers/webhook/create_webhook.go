@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/api/webhooks"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *WebhookController) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	// Decode request body
+	var input schemas.CreateWebhookInput
+	err := schemas.DecodeJSON(r.Body, &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if result := models.ValidateProperties(input.Properties); result != "" {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+		return
+	}
+	if result := webhooks.ValidateURL(input.URL, controller.Config); result != "" {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+		return
+	}
+	for _, e := range input.Events {
+		if result := events.ValidatePattern(e); result != "" {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+			return
+		}
+	}
+	// Check permission
+	if err := auth.Authorize(r, models.UUID(input.OrganizationID), auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Check organization
+	_, err = (&models.Organization{ID: models.UUID(input.OrganizationID)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Organization not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get organization", err)
+		return
+	}
+
+	// Check table
+	if input.TableID != nil {
+		table, err := (&models.TableFilesystemEntry{ID: models.UUID(*input.TableID)}).GetTable(controller.db(r))
+		if err != nil {
+			if xerrors.Is(err, gorm.ErrRecordNotFound) {
+				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Table not found", nil)
+				return
+			}
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get table", err)
+			return
+		}
+
+		if table.OrganizationID != models.UUID(input.OrganizationID) {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, "Cannot subscribe to another organization's table", nil)
+			return
+		}
+	}
+
+	// Generate secret
+	if input.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to generate secret", err)
+			return
+		}
+		input.Secret = hex.EncodeToString(b)
+	}
+
+	// Create webhook
+	webhook := models.Webhook{
+		OrganizationID: models.UUID(input.OrganizationID),
+		TableID:        (*models.UUID)(input.TableID),
+		URL:            input.URL,
+		Secret:         input.Secret,
+		Events:         input.Events,
+		Properties:     input.Properties,
+	}
+	err = webhook.Create(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to create webhook", err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.CreatedWebhook
+	err = copier.Copy(&output.Webhook, &webhook)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+	output.Secret = webhook.Secret
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *WebhookController) GetWebhookDeliveryList(w http.ResponseWriter, r *http.Request) {
+	// Get webhook id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "webhookID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid webhook id", err)
+		return
+	}
+
+	// Decode request parameters
+	var input schemas.GetWebhookDeliveryListInput
+	err = schemas.DecodeQuery(r.URL.Query(), &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request parameter", err)
+		return
+	}
+
+	if input.Page == nil {
+		input.Page = &defaultPage
+	}
+	if input.PageSize == nil {
+		input.PageSize = &defaultPageSize
+	}
+
+	// Fetch
+	webhook, err := (&models.Webhook{ID: models.UUID(id)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get webhook", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.Authorize(r, webhook.OrganizationID, auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+	deliveries, totalCount, err := webhook.GetDeliveries(controller.db(r), &models.GetWebhookDeliveryListOpts{
+		Offset: (*input.Page - 1) * *input.PageSize,
+		Limit:  *input.PageSize,
+	})
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get deliveries", err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.WebhookDeliveryList
+	err = copier.Copy(&output.Deliveries, &deliveries)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+	output.TotalCount = totalCount
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jinzhu/copier"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+var (
+	defaultPage     = 1
+	defaultPageSize = 10
+)
+
+func (controller *WebhookController) GetWebhookList(w http.ResponseWriter, r *http.Request) {
+	// Decode request parameters
+	var input schemas.GetWebhookListInput
+	err := schemas.DecodeQuery(r.URL.Query(), &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request parameter", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.Authorize(r, models.UUID(input.OrganizationID), auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Decode sort key
+	var sortKeys []schemas.GetListSortKey
+	err = schemas.DecodeGetListSort(input.Sort, &sortKeys)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid sort parameter", err)
+		return
+	}
+
+	if input.Page == nil {
+		input.Page = &defaultPage
+	}
+	if input.PageSize == nil {
+		input.PageSize = &defaultPageSize
+	}
+
+	// Fetch
+	var sortKeyOpt []models.GetListSortKey
+	if err := copier.Copy(&sortKeyOpt, &sortKeys); err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make query option", err)
+		return
+	}
+	opts := models.GetWebhookListOpts{
+		OrganizationID: models.UUID(input.OrganizationID),
+		TableID:        (*models.UUID)(input.TableID),
+		Sort:           sortKeyOpt,
+		Offset:         (*input.Page - 1) * *input.PageSize,
+		Limit:          *input.PageSize,
+	}
+	webhooks, totalCount, err := models.GetWebhookList(controller.db(r), &opts)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get webhooks", err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.WebhookList
+	err = copier.Copy(&output.Webhooks, &webhooks)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+	output.TotalCount = totalCount
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
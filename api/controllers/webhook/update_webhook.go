@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/api/webhooks"
+	"github.com/tsujio/x-base/logging"
+)
+
+func (controller *WebhookController) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	// Get webhook id
+	vars := mux.Vars(r)
+	var id uuid.UUID
+	err := schemas.DecodeUUID(vars, "webhookID", &id)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid webhook id", err)
+		return
+	}
+
+	// Decode request body
+	var input schemas.UpdateWebhookInput
+	err = schemas.DecodeJSON(r.Body, &input)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if result := models.ValidateProperties(input.Properties); result != "" {
+		responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+		return
+	}
+	if input.URL != nil {
+		if result := webhooks.ValidateURL(*input.URL, controller.Config); result != "" {
+			responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+			return
+		}
+	}
+	if input.Events != nil {
+		for _, e := range *input.Events {
+			if result := events.ValidatePattern(e); result != "" {
+				responses.SendErrorResponse(w, r, http.StatusBadRequest, result, nil)
+				return
+			}
+		}
+	}
+
+	// Fetch
+	webhook, err := (&models.Webhook{ID: models.UUID(id)}).Get(controller.db(r))
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			responses.SendErrorResponse(w, r, http.StatusNotFound, "Not found", nil)
+			return
+		}
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get webhook", err)
+		return
+	}
+
+	// Check permission
+	if err := auth.Authorize(r, webhook.OrganizationID, auth.PermissionManage); err != nil {
+		auth.SendError(w, r, err)
+		return
+	}
+
+	// Update
+	if input.URL != nil {
+		webhook.URL = *input.URL
+	}
+	if input.Secret != nil {
+		webhook.Secret = *input.Secret
+	}
+	if input.Events != nil {
+		webhook.Events = *input.Events
+	}
+	for k, v := range input.Properties {
+		webhook.Properties[k] = v
+	}
+	err = webhook.Save(controller.db(r))
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to save webhook", err)
+		return
+	}
+
+	// Convert to output schema
+	var output schemas.Webhook
+	err = copier.Copy(&output, &webhook)
+	if err != nil {
+		responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to make output data", err)
+		return
+	}
+
+	// Send response
+	err = json.NewEncoder(w).Encode(&output)
+	if err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
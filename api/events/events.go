@@ -0,0 +1,122 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tsujio/x-base/api/models"
+)
+
+const (
+	TypeRecordInserted = "record.inserted"
+	TypeRecordUpdated  = "record.updated"
+	TypeRecordDeleted  = "record.deleted"
+	TypeTableCreated   = "table.created"
+	TypeTableUpdated   = "table.updated"
+	TypeTableDeleted   = "table.deleted"
+	TypeColumnCreated  = "column.created"
+	TypeColumnUpdated  = "column.updated"
+	TypeColumnDeleted  = "column.deleted"
+	TypeFolderCreated  = "folder.created"
+	TypeFolderUpdated  = "folder.updated"
+	TypeFolderDeleted  = "folder.deleted"
+)
+
+var Types = []string{
+	TypeRecordInserted,
+	TypeRecordUpdated,
+	TypeRecordDeleted,
+	TypeTableCreated,
+	TypeTableUpdated,
+	TypeTableDeleted,
+	TypeColumnCreated,
+	TypeColumnUpdated,
+	TypeColumnDeleted,
+	TypeFolderCreated,
+	TypeFolderUpdated,
+	TypeFolderDeleted,
+}
+
+// ValidatePattern returns an error message if the pattern matches no event type.
+// A pattern is an event type, "<prefix>.*" or "*".
+func ValidatePattern(pattern string) string {
+	if pattern == "*" {
+		return ""
+	}
+	for _, t := range Types {
+		if t == pattern || (strings.HasSuffix(pattern, ".*") && strings.HasPrefix(t, strings.TrimSuffix(pattern, "*"))) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("Invalid event type: %s", pattern)
+}
+
+type Event struct {
+	ID             uuid.UUID   `json:"id"`
+	Type           string      `json:"type"`
+	OrganizationID uuid.UUID   `json:"organizationId"`
+	TableID        *uuid.UUID  `json:"tableId"`
+	Data           interface{} `json:"data"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	FolderIDs      []uuid.UUID `json:"-"`
+}
+
+func New(typ string, organizationID models.UUID, tableID *models.UUID, data interface{}) *Event {
+	return &Event{
+		ID:             uuid.New(),
+		Type:           typ,
+		OrganizationID: uuid.UUID(organizationID),
+		TableID:        (*uuid.UUID)(tableID),
+		Data:           data,
+		CreatedAt:      time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// In sets the folders which the subject of the event belongs to. Nil ids are ignored.
+func (e *Event) In(folderIDs ...*models.UUID) *Event {
+	for _, id := range folderIDs {
+		if id != nil && *id != models.UUID(uuid.Nil) {
+			e.FolderIDs = append(e.FolderIDs, uuid.UUID(*id))
+		}
+	}
+	return e
+}
+
+type RecordsData struct {
+	RecordIDs []uuid.UUID `json:"recordIds"`
+}
+
+type DeletedData struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []func(*Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(f func(*Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, f)
+}
+
+// Publish notifies subscribers of the event. It is safe to call on nil.
+func (b *Bus) Publish(e *Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, f := range b.subscribers {
+		f(e)
+	}
+}
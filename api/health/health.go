@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/databases"
+	"github.com/tsujio/x-base/logging"
+)
+
+const pingTimeout = 3 * time.Second
+
+type Config struct {
+	// MigrationVersion is the migration version the server expects. Not checked if zero.
+	MigrationVersion int64
+}
+
+// Probe serves the liveness and readiness probes.
+type Probe struct {
+	DB   *gorm.DB
+	Conf *Config
+}
+
+func NewProbe(db *gorm.DB, conf *Config) *Probe {
+	if conf == nil {
+		conf = &Config{}
+	}
+	return &Probe{
+		DB:   db,
+		Conf: conf,
+	}
+}
+
+type status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func send(w http.ResponseWriter, r *http.Request, code int, s *status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(s); err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+	}
+}
+
+// Healthz reports that the process is alive.
+func (p *Probe) Healthz(w http.ResponseWriter, r *http.Request) {
+	send(w, r, http.StatusOK, &status{Status: "ok"})
+}
+
+// Readyz reports whether the server can serve requests: the database responds and its migration is at the expected version.
+func (p *Probe) Readyz(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{}
+	ready := true
+
+	if err := p.ping(r.Context()); err != nil {
+		logging.Warning(fmt.Sprintf("Database is not ready: %+v", err), r)
+		checks["database"] = "unavailable"
+		ready = false
+	} else {
+		checks["database"] = "ok"
+
+		version, dirty, err := databases.MigrationVersion(p.DB)
+		switch {
+		case err != nil:
+			logging.Warning(fmt.Sprintf("%+v", err), r)
+			checks["migration"] = "unavailable"
+			ready = false
+		case dirty:
+			checks["migration"] = fmt.Sprintf("version %d is dirty", version)
+			ready = false
+		case p.Conf.MigrationVersion != 0 && version != p.Conf.MigrationVersion:
+			checks["migration"] = fmt.Sprintf("version %d, expected %d", version, p.Conf.MigrationVersion)
+			ready = false
+		default:
+			checks["migration"] = "ok"
+		}
+	}
+
+	if !ready {
+		send(w, r, http.StatusServiceUnavailable, &status{Status: "unavailable", Checks: checks})
+		return
+	}
+	send(w, r, http.StatusOK, &status{Status: "ok", Checks: checks})
+}
+
+func (p *Probe) ping(ctx context.Context) error {
+	sqlDB, err := p.DB.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,240 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/utils/recorder"
+	"github.com/tsujio/x-base/api/utils/responses"
+	"github.com/tsujio/x-base/logging"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	DefaultRetention = 24 * time.Hour
+	DefaultLease     = 5 * time.Minute
+
+	maxKeyLength = 255
+
+	// maxBodySize is the max size of request bodies, which are read into memory to be hashed.
+	maxBodySize = 32 << 20
+)
+
+// replayedHeaders are the response headers stored to be replayed.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+type Config struct {
+	// Retention is how long responses are stored. DefaultRetention if zero.
+	Retention time.Duration
+	// Lease is how long a key is reserved for the request in progress, after which the key can be used again
+	// in case the server stopped before storing the response. It should be longer than request timeouts.
+	// DefaultLease if zero.
+	Lease time.Duration
+}
+
+func retention(conf *Config) time.Duration {
+	if conf == nil || conf.Retention <= 0 {
+		return DefaultRetention
+	}
+	return conf.Retention
+}
+
+func lease(conf *Config) time.Duration {
+	if conf == nil || conf.Lease <= 0 {
+		return DefaultLease
+	}
+	return conf.Lease
+}
+
+// Prune deletes responses older than the retention.
+func Prune(db *gorm.DB, conf *Config) error {
+	return models.DeleteIdempotencyKeysBefore(db, time.Now().UTC().Add(-retention(conf)))
+}
+
+func hash(parts ...[]byte) string {
+	h := sha256.New()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%d:", len(p))
+		h.Write(p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// scope separates keys of different clients.
+func scope(p *auth.Principal) string {
+	switch {
+	case p == nil:
+		return ""
+	case p.Admin:
+		return "admin"
+	case p.ClientID() != "":
+		return p.ClientID()
+	case p.OrganizationID != nil:
+		return "organization:" + p.OrganizationID.String()
+	default:
+		return ""
+	}
+}
+
+// Middleware stores responses to POST requests with the Idempotency-Key header,
+// and replays them for the retried requests with the same key.
+// A request with the same key but a different method, path or body is rejected with 422,
+// and one sent while the first request is in progress is rejected with 409 until the lease expires.
+// Responses with 5xx status or to cancelled requests are not stored so that the request can be retried.
+// It must be used after auth.Middleware.
+func Middleware(db *gorm.DB, conf *Config) func(http.Handler) http.Handler {
+	ttl := retention(conf)
+	leaseTTL := lease(conf)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Invalid idempotency key", nil)
+				return
+			}
+
+			// Read request body
+			body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+			if err != nil {
+				if len(body) >= maxBodySize {
+					responses.SendErrorResponse(w, r, http.StatusRequestEntityTooLarge, "Request body is too large", nil)
+					return
+				}
+				responses.SendErrorResponse(w, r, http.StatusBadRequest, "Failed to read request body", err)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			// Reserve key
+			p := auth.FromContext(r.Context())
+			k := &models.IdempotencyKey{
+				KeyHash:     hash([]byte(scope(p)), []byte(key)),
+				RequestHash: hash([]byte(r.Method), []byte(r.URL.RequestURI()), body),
+				CreatedAt:   time.Now().UTC(),
+			}
+			created, err := reserve(db.WithContext(r.Context()), k, ttl, leaseTTL)
+			if err != nil {
+				responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to reserve idempotency key", err)
+				return
+			}
+			if !created {
+				stored, err := models.GetIdempotencyKey(db.WithContext(r.Context()), k.KeyHash)
+				if err != nil {
+					if xerrors.Is(err, gorm.ErrRecordNotFound) {
+						responses.SendErrorResponse(w, r, http.StatusConflict, "Request with the same idempotency key is in progress", nil)
+						return
+					}
+					responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to get idempotency key", err)
+					return
+				}
+				replay(w, r, stored, k.RequestHash)
+				return
+			}
+
+			// Release key if the handler panics
+			defer func() {
+				if v := recover(); v != nil {
+					if err := k.Delete(db); err != nil {
+						logging.Error(fmt.Sprintf("Failed to release idempotency key: %+v", err), r)
+					}
+					panic(v)
+				}
+			}()
+
+			// Serve and store response
+			rec := recorder.WithBody(w)
+			next.ServeHTTP(rec, r)
+
+			// Not bound to the request context, which may be done already
+			if status := rec.Status(); status >= http.StatusInternalServerError || status == responses.StatusClientClosedRequest {
+				if err := k.Delete(db); err != nil {
+					logging.Error(fmt.Sprintf("Failed to release idempotency key: %+v", err), r)
+				}
+				return
+			}
+			if err := store(db, k, rec); err != nil {
+				logging.Error(fmt.Sprintf("Failed to store response: %+v", err), r)
+			}
+		})
+	}
+}
+
+// reserve creates the key, replacing the expired one and the one whose lease has expired without the response.
+func reserve(db *gorm.DB, k *models.IdempotencyKey, ttl, leaseTTL time.Duration) (bool, error) {
+	created, err := k.CreateIfNotExists(db)
+	if err != nil || created {
+		return created, err
+	}
+
+	err = db.Where("key_hash = ? AND (created_at < ? OR (status_code IS NULL AND created_at < ?))",
+		k.KeyHash, k.CreatedAt.Add(-ttl), k.CreatedAt.Add(-leaseTTL)).
+		Delete(&models.IdempotencyKey{}).Error
+	if err != nil {
+		return false, xerrors.Errorf("Failed to delete expired key: %w", err)
+	}
+	return k.CreateIfNotExists(db)
+}
+
+func store(db *gorm.DB, k *models.IdempotencyKey, rec *recorder.Recorder) error {
+	header := map[string]string{}
+	for _, name := range replayedHeaders {
+		if v := rec.Header().Get(name); v != "" {
+			header[name] = v
+		}
+	}
+	b, err := json.Marshal(header)
+	if err != nil {
+		return xerrors.Errorf("Failed to encode header: %w", err)
+	}
+
+	status := rec.Status()
+	k.StatusCode = &status
+	k.Header = models.JSON(b)
+	k.Body = rec.Body()
+	return k.Save(db)
+}
+
+func replay(w http.ResponseWriter, r *http.Request, stored *models.IdempotencyKey, requestHash string) {
+	if stored.RequestHash != requestHash {
+		responses.SendErrorResponse(w, r, http.StatusUnprocessableEntity, "Idempotency key is already used for another request", nil)
+		return
+	}
+	if stored.StatusCode == nil {
+		responses.SendErrorResponse(w, r, http.StatusConflict, "Request with the same idempotency key is in progress", nil)
+		return
+	}
+
+	var header map[string]string
+	if len(stored.Header) > 0 {
+		if err := json.Unmarshal(stored.Header, &header); err != nil {
+			responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to decode stored header", err)
+			return
+		}
+	}
+	for name, v := range header {
+		w.Header().Set(name, v)
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(*stored.StatusCode)
+	if _, err := w.Write(stored.Body); err != nil {
+		logging.Error(fmt.Sprintf("%+v", err), r)
+	}
+}
@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
+	"github.com/tsujio/x-base/api/schemas"
+)
+
+const JobTypeCopyFolder = "copy_folder"
+
+type CopyFolderParams struct {
+	FolderID       uuid.UUID              `json:"folderId"`
+	ParentFolderID *uuid.UUID             `json:"parentFolderId"`
+	Properties     map[string]interface{} `json:"properties"`
+	IncludeRecords bool                   `json:"includeRecords"`
+}
+
+type CopyFolderResult struct {
+	FolderID uuid.UUID `json:"folderId"`
+}
+
+func init() {
+	register(JobTypeCopyFolder, copyFolder)
+}
+
+func copyFolder(db *gorm.DB, bus *events.Bus, checker *quotas.Checker, params json.RawMessage, report ProgressReporter) (interface{}, error) {
+	var p CopyFolderParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, xerrors.Errorf("Failed to deserialize params: %w", err)
+	}
+
+	folder, err := (&models.TableFilesystemEntry{ID: models.UUID(p.FolderID)}).GetFolder(db)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get folder: %w", err)
+	}
+
+	// Load the storage usage before writing so that it does not include uncommitted records
+	if err := checker.LoadStorage(db, folder.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	var dup *models.Folder
+	var size int64
+	err = db.Transaction(func(tx *gorm.DB) error {
+		dup, err = folder.Copy(tx, &models.CopyFolderOpts{
+			ParentFolderID: (*models.UUID)(p.ParentFolderID),
+			Properties:     p.Properties,
+			IncludeRecords: p.IncludeRecords,
+		})
+		if err != nil {
+			return xerrors.Errorf("Failed to copy folder: %w", err)
+		}
+		size, err = checker.CheckCopy(tx, &dup.TableFilesystemEntry, p.IncludeRecords)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	checker.AddStorage(dup.OrganizationID, size)
+
+	if err := dup.ComputePath(db); err != nil {
+		return nil, xerrors.Errorf("Failed to get path: %w", err)
+	}
+	var data schemas.Folder
+	if err := copier.Copy(&data, &dup); err != nil {
+		return nil, xerrors.Errorf("Failed to make event data: %w", err)
+	}
+	bus.Publish(events.New(events.TypeFolderCreated, dup.OrganizationID, nil, &data).In(&dup.ID))
+
+	return &CopyFolderResult{FolderID: uuid.UUID(dup.ID)}, nil
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
+)
+
+const JobTypePurgeColumnData = "purge_column_data"
+
+const purgeColumnDataBatchSize = 500
+
+type PurgeColumnDataParams struct {
+	TableID  uuid.UUID `json:"tableId"`
+	ColumnID uuid.UUID `json:"columnId"`
+}
+
+type PurgeColumnDataResult struct {
+	PurgedRecords int64 `json:"purgedRecords"`
+}
+
+func init() {
+	register(JobTypePurgeColumnData, purgeColumnData)
+}
+
+func purgeColumnData(db *gorm.DB, bus *events.Bus, checker *quotas.Checker, params json.RawMessage, report ProgressReporter) (interface{}, error) {
+	var p PurgeColumnDataParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, xerrors.Errorf("Failed to deserialize params: %w", err)
+	}
+
+	result := &PurgeColumnDataResult{}
+	for {
+		n, err := models.PurgeColumnData(db, models.UUID(p.TableID), models.UUID(p.ColumnID), purgeColumnDataBatchSize)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to purge column data: %w", err)
+		}
+		if n == 0 {
+			break
+		}
+		result.PurgedRecords += n
+		if err := report(result); err != nil {
+			return nil, xerrors.Errorf("Failed to report progress: %w", err)
+		}
+	}
+
+	return result, nil
+}
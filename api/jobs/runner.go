@@ -0,0 +1,290 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
+	"github.com/tsujio/x-base/logging"
+)
+
+type Handler func(db *gorm.DB, bus *events.Bus, checker *quotas.Checker, params json.RawMessage, report ProgressReporter) (interface{}, error)
+
+type ProgressReporter func(progress interface{}) error
+
+var handlers = map[string]Handler{}
+
+func register(jobType string, handler Handler) {
+	handlers[jobType] = handler
+}
+
+// DefaultLeaseDuration is how long a job stays claimed by a runner without renewal.
+const DefaultLeaseDuration = time.Minute
+
+type Runner struct {
+	DB     *gorm.DB
+	Bus    *events.Bus
+	Quotas *quotas.Checker
+	// ID identifies the runner as the owner of the jobs it claims.
+	ID            string
+	LeaseDuration time.Duration
+
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	stopped  bool
+	stopping chan struct{}
+	// ctx is cancelled to interrupt the running jobs when Stop times out.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewRunner(db *gorm.DB) *Runner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{
+		DB:            db,
+		ID:            uuid.NewString(),
+		LeaseDuration: DefaultLeaseDuration,
+		stopping:      make(chan struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Enqueue saves a new job and starts it in background.
+func (r *Runner) Enqueue(organizationID models.UUID, jobType string, params interface{}) (*models.Job, error) {
+	job, err := r.Create(r.DB, organizationID, jobType, params)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Start(job)
+
+	return job, nil
+}
+
+// Create saves a new pending job with db, which may be a transaction. The job is
+// started by Start after the transaction commits, or by Resume otherwise.
+func (r *Runner) Create(db *gorm.DB, organizationID models.UUID, jobType string, params interface{}) (*models.Job, error) {
+	if _, exists := handlers[jobType]; !exists {
+		return nil, fmt.Errorf("Unknown job type: %s", jobType)
+	}
+
+	p, err := json.Marshal(params)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to serialize params: %w", err)
+	}
+
+	job := &models.Job{
+		OrganizationID: organizationID,
+		Type:           jobType,
+		Params:         models.JSON(p),
+	}
+	if err := job.Create(db); err != nil {
+		return nil, xerrors.Errorf("Failed to create job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Start runs the saved job in background.
+func (r *Runner) Start(job *models.Job) {
+	r.start(*job)
+}
+
+// Resume restarts pending jobs and jobs whose runner stopped renewing the lease.
+// Jobs claimed by another runner in the meantime are skipped.
+func (r *Runner) Resume() error {
+	jobs, err := models.GetClaimableJobs(r.DB, time.Now().UTC())
+	if err != nil {
+		return xerrors.Errorf("Failed to get claimable jobs: %w", err)
+	}
+	for _, job := range jobs {
+		r.start(job)
+	}
+	return nil
+}
+
+// ResumePeriodically calls Resume every LeaseDuration in background until Stop,
+// to pick up jobs left by stopped instances once their leases expire.
+func (r *Runner) ResumePeriodically() {
+	if !r.add() {
+		return
+	}
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.LeaseDuration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stopping:
+				return
+			case <-ticker.C:
+				if err := r.Resume(); err != nil {
+					logging.Error(fmt.Sprintf("Failed to resume jobs: %+v", err), nil)
+				}
+			}
+		}
+	}()
+}
+
+// Wait blocks until all started jobs finish.
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+// Stop stops starting jobs and waits for the running ones.
+// If ctx is done first, the running jobs are interrupted and left to be resumed after their leases expire.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.stopped {
+		r.stopped = true
+		close(r.stopping)
+	}
+	r.mu.Unlock()
+	defer r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		<-done
+		return xerrors.Errorf("Jobs were interrupted: %w", ctx.Err())
+	}
+}
+
+// add adds a goroutine to wait for unless stopped.
+func (r *Runner) add() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return false
+	}
+	r.wg.Add(1)
+	return true
+}
+
+func (r *Runner) start(job models.Job) {
+	if !r.add() {
+		return
+	}
+	go func() {
+		defer r.wg.Done()
+		if err := r.run(&job); err != nil {
+			logging.Error(fmt.Sprintf("Failed to run job %s: %+v", job.ID, err), nil)
+		}
+	}()
+}
+
+func (r *Runner) run(job *models.Job) error {
+	db := r.DB.WithContext(r.ctx)
+
+	now := time.Now().UTC()
+	claimed, err := job.Claim(db, r.ID, now, now.Add(r.LeaseDuration))
+	if err != nil {
+		return xerrors.Errorf("Failed to claim job: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+
+	stop := r.renewLease(job)
+	defer stop()
+
+	handler, exists := handlers[job.Type]
+	if !exists {
+		return r.finish(job, nil, fmt.Errorf("Unknown job type: %s", job.Type))
+	}
+
+	result, err := func() (result interface{}, err error) {
+		defer func() {
+			if e := recover(); e != nil {
+				err = fmt.Errorf("Panic: %v", e)
+			}
+		}()
+		return handler(db, r.Bus, r.Quotas, json.RawMessage(job.Params), func(progress interface{}) error {
+			b, err := json.Marshal(progress)
+			if err != nil {
+				return xerrors.Errorf("Failed to serialize progress: %w", err)
+			}
+			return job.SaveProgress(db, models.JSON(b))
+		})
+	}()
+
+	return r.finish(job, result, err)
+}
+
+func (r *Runner) finish(job *models.Job, result interface{}, jobErr error) error {
+	if r.ctx.Err() != nil {
+		logging.Warning(fmt.Sprintf("Job %s was interrupted by shutdown", job.ID), nil)
+		return nil
+	}
+
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	if jobErr != nil {
+		logging.Error(fmt.Sprintf("Job %s failed: %+v", job.ID, jobErr), nil)
+		msg := jobErr.Error()
+		job.Status = models.JobStatusFailed
+		job.Error = &msg
+	} else {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return xerrors.Errorf("Failed to serialize result: %w", err)
+		}
+		job.Status = models.JobStatusSucceeded
+		job.Result = models.JSON(b)
+	}
+
+	completed, err := job.Complete(r.DB, r.ID)
+	if err != nil {
+		return xerrors.Errorf("Failed to complete job: %w", err)
+	}
+	if !completed {
+		logging.Warning(fmt.Sprintf("Lease of job %s was lost before completion", job.ID), nil)
+	}
+	return nil
+}
+
+// renewLease keeps the lease of the job alive until the returned function is called.
+func (r *Runner) renewLease(job *models.Job) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(r.LeaseDuration / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				renewed, err := job.RenewLease(r.DB, r.ID, time.Now().UTC().Add(r.LeaseDuration))
+				if err != nil {
+					logging.Error(fmt.Sprintf("Failed to renew lease of job %s: %+v", job.ID, err), nil)
+				} else if !renewed {
+					logging.Warning(fmt.Sprintf("Lease of job %s was lost", job.ID), nil)
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
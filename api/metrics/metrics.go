@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/utils/recorder"
+	"github.com/tsujio/x-base/databases"
+	"github.com/tsujio/x-base/logging"
+)
+
+const namespace = "xbase"
+
+// Metrics collects metrics of a router. Each router has its own registry.
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	queries         *prometheus.CounterVec
+}
+
+func New(db *gorm.DB) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by route and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		queries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "table_queries_total",
+			Help:      "Number of table queries by kind.",
+		}, []string{"kind"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.requestDuration,
+		m.queries,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "migration_version",
+			Help:      "Version of the last applied migration.",
+		}, func() float64 {
+			version, _, err := databases.MigrationVersion(db)
+			if err != nil {
+				logging.Error(fmt.Sprintf("%+v", err), nil)
+				return 0
+			}
+			return float64(version)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "migration_dirty",
+			Help:      "1 if the last migration failed halfway.",
+		}, func() float64 {
+			_, dirty, err := databases.MigrationVersion(db)
+			if err != nil {
+				logging.Error(fmt.Sprintf("%+v", err), nil)
+				return 0
+			}
+			if dirty {
+				return 1
+			}
+			return 0
+		}),
+	)
+	if sqlDB, err := db.DB(); err == nil {
+		m.registry.MustRegister(collectors.NewDBStatsCollector(sqlDB, "main"))
+	} else {
+		logging.Error(fmt.Sprintf("Failed to get sqlDB object: %+v", err), nil)
+	}
+
+	return m
+}
+
+// ObserveQuery counts a table query of the kind (select, insert, update or delete).
+func (m *Metrics) ObserveQuery(kind string) {
+	if m == nil {
+		return
+	}
+	m.queries.WithLabelValues(kind).Inc()
+}
+
+// Handler serves the metrics in Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware counts requests and observes their latency labeled with the route template.
+// It must be used as a middleware of mux.Router so that the route is matched.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unknown"
+		if cr := mux.CurrentRoute(r); cr != nil {
+			if tmpl, err := cr.GetPathTemplate(); err == nil {
+				route = tmpl
+			}
+		}
+
+		start := time.Now()
+		rec := recorder.New(w)
+		next.ServeHTTP(rec, r)
+
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(rec.Status())).Inc()
+		m.requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
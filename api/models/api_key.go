@@ -0,0 +1,100 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+type APIKey struct {
+	ID             UUID
+	OrganizationID UUID
+	Prefix         string
+	KeyHash        string
+	Properties     Properties
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	LastUsedAt     *time.Time
+	RevokedAt      *time.Time
+}
+
+type GetAPIKeyListOpts struct {
+	OrganizationID UUID
+	Sort           []GetListSortKey
+	Offset, Limit  int
+}
+
+func GetAPIKeyList(db *gorm.DB, opts *GetAPIKeyListOpts) ([]APIKey, int64, error) {
+	order, err := convertGetListSortKeyToOrderString(DialectOf(db), opts.Sort, []string{"id", "created_at", "updated_at", "last_used_at"})
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to convert sort key: %w", err)
+	}
+
+	var keys []APIKey
+	var totalCount int64
+	err = db.Model(&APIKey{}).Where("organization_id = ?", opts.OrganizationID).
+		Count(&totalCount).
+		Order(order).Offset(opts.Offset).Limit(opts.Limit).Find(&keys).
+		Error
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to get models: %w", err)
+	}
+	return keys, totalCount, nil
+}
+
+func GetAPIKeyByHash(db *gorm.DB, keyHash string) (*APIKey, error) {
+	var key APIKey
+	err := db.Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get model: %w", err)
+	}
+	return &key, nil
+}
+
+func (k *APIKey) Create(db *gorm.DB) error {
+	if k.ID == UUID(uuid.Nil) {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return xerrors.Errorf("Failed to generate id: %w", err)
+		}
+		k.ID = UUID(id)
+	}
+
+	err := db.Create(k).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to create model: %w", err)
+	}
+	return nil
+}
+
+func (k *APIKey) Save(db *gorm.DB) error {
+	if k.ID == UUID(uuid.Nil) {
+		return fmt.Errorf("Empty id")
+	}
+	err := db.Save(k).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to save model: %w", err)
+	}
+	return nil
+}
+
+func (k *APIKey) Get(db *gorm.DB) (*APIKey, error) {
+	err := db.Where("id = ?", k.ID).First(k).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get model: %w", err)
+	}
+	return k, nil
+}
+
+// Touch records the last usage time without changing updated_at.
+func (k *APIKey) Touch(db *gorm.DB, t time.Time) error {
+	err := db.Model(k).UpdateColumn("last_used_at", t).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to update model: %w", err)
+	}
+	k.LastUsedAt = &t
+	return nil
+}
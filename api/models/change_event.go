@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+type ChangeEvent struct {
+	Seq            int64 `gorm:"primaryKey;autoIncrement"`
+	ID             UUID
+	OrganizationID UUID
+	TableID        *UUID
+	FolderIDs      StringList
+	Type           string
+	Payload        JSON
+	CreatedAt      time.Time
+}
+
+type GetChangeEventListOpts struct {
+	OrganizationID *UUID
+	TableID        *UUID
+	FolderID       *UUID
+	AfterSeq       int64
+	Limit          int
+}
+
+// GetChangeEventList returns events of the organization, the table or the folder subtree in order of seq.
+func GetChangeEventList(db *gorm.DB, opts *GetChangeEventListOpts) ([]ChangeEvent, error) {
+	q := db.Where("seq > ?", opts.AfterSeq)
+	if opts.OrganizationID != nil {
+		q = q.Where("organization_id = ?", *opts.OrganizationID)
+	}
+	if opts.TableID != nil {
+		q = q.Where("table_id = ?", *opts.TableID)
+	}
+	if opts.FolderID != nil {
+		switch DialectOf(db) {
+		case DialectSQLite:
+			q = q.Where("EXISTS (SELECT 1 FROM json_each(folder_ids) WHERE value = ?)", opts.FolderID.String())
+		case DialectPostgres:
+			q = q.Where("folder_ids @> jsonb_build_array(CAST(? AS text))", opts.FolderID.String())
+		default:
+			q = q.Where("JSON_CONTAINS(folder_ids, JSON_QUOTE(?))", opts.FolderID.String())
+		}
+	}
+
+	var events []ChangeEvent
+	err := q.Order("seq").Limit(opts.Limit).Find(&events).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get models: %w", err)
+	}
+	return events, nil
+}
+
+func GetLatestChangeEventSeq(db *gorm.DB) (int64, error) {
+	var seq int64
+	err := db.Model(&ChangeEvent{}).Select("COALESCE(MAX(seq), 0)").Scan(&seq).Error
+	if err != nil {
+		return 0, xerrors.Errorf("Failed to get latest seq: %w", err)
+	}
+	return seq, nil
+}
+
+func DeleteChangeEventsBefore(db *gorm.DB, t time.Time) error {
+	err := db.Where("created_at < ?", t).Delete(&ChangeEvent{}).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to delete models: %w", err)
+	}
+	err = db.Exec("DELETE FROM change_event_records WHERE captured_at < ?", t).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to delete captured records: %w", err)
+	}
+	return nil
+}
+
+const (
+	ChangePhaseBefore = "before"
+	ChangePhaseAfter  = "after"
+)
+
+// CaptureChangeEventRecords saves the current state of the records as the state of the phase of the change,
+// so that table policies are evaluated against it when the event is streamed.
+func CaptureChangeEventRecords(db *gorm.DB, eventID UUID, phase string, tableID UUID, ids []UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := db.Exec(`
+	INSERT INTO change_event_records(event_id, phase, id, data, properties, created_at, captured_at)
+	SELECT ?, ?, id, data, properties, created_at, ?
+	FROM table_records
+	WHERE table_id = ? AND id IN ?
+	`, eventID, phase, time.Now().UTC(), tableID, ids).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to capture records: %w", err)
+	}
+	return nil
+}
+
+// FindChangeEventRecordIDsMatching returns the ids of the records captured for the event
+// which satisfy cond in any phase of the change.
+func FindChangeEventRecordIDsMatching(db *gorm.DB, eventID UUID, ids []UUID, cond SQLBuilder) ([]UUID, error) {
+	return findRecordIDs(db, "change_event_records", "event_id", eventID, ids, cond, true)
+}
+
+func (e *ChangeEvent) Create(db *gorm.DB) error {
+	err := db.Create(e).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to create model: %w", err)
+	}
+	return nil
+}
@@ -15,17 +15,38 @@ type Column struct {
 	ID         UUID
 	TableID    UUID
 	Index      int
+	Type       *string
 	Properties Properties
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
 
+// shiftColumnIndices adds delta to the indices of the columns at from or later.
+func shiftColumnIndices(db *gorm.DB, tableID UUID, from, delta int) error {
+	index := quote(db, "index")
+	if DialectOf(db) != DialectMySQL {
+		// SQLite and PostgreSQL ignore the order of update and check the unique index on each row,
+		// so negate the indices before shifting them
+		if err := db.Model(&Column{}).
+			Where("table_id = ? AND "+index+" >= ?", tableID, from).
+			UpdateColumn("index", gorm.Expr("-"+index+" - 1")).
+			Error; err != nil {
+			return err
+		}
+		return db.Model(&Column{}).
+			Where("table_id = ? AND "+index+" < 0", tableID).
+			UpdateColumn("index", gorm.Expr("-"+index+" - 1 + ?", delta)).
+			Error
+	}
+	return db.Model(&Column{}).
+		Where("table_id = ? AND "+index+" >= ?", tableID, from).
+		Order(index+" DESC").
+		UpdateColumn("index", gorm.Expr(index+" + ?", delta)).
+		Error
+}
+
 func moveColumnIndicesToTemporaryAddress(db *gorm.DB, tableID UUID) error {
-	if err := db.Model(&Column{}).
-		Where("table_id = ?", tableID).
-		Order("`index` DESC").
-		UpdateColumn("`index`", gorm.Expr("`index` + ?", ColumnTailIndex)).
-		Error; err != nil {
+	if err := shiftColumnIndices(db, tableID, 0, ColumnTailIndex); err != nil {
 		return xerrors.Errorf("Failed to execute query: %w", err)
 	}
 	return nil
@@ -37,17 +58,31 @@ func CanonicalizeColumnIndices(db *gorm.DB, tableID UUID) error {
 			return xerrors.Errorf("Failed to shift column indices: %w", err)
 		}
 
-		err := tx.Exec(`
+		sql := `
 		UPDATE columns AS c
 		INNER JOIN (
-		    SELECT id, ROW_NUMBER() OVER (PARTITION BY table_id ORDER BY `+"`index`"+`) - 1 AS `+"`index`"+`
+		    SELECT id, ROW_NUMBER() OVER (PARTITION BY table_id ORDER BY %[1]s) - 1 AS %[1]s
 		    FROM columns
 		    WHERE table_id = ?
 		) AS t
 		USING (id)
-		SET c.`+"`index`"+` = t.`+"`index`"+`
+		SET c.%[1]s = t.%[1]s
 		WHERE c.table_id = ?
-		`, tableID, tableID).Error
+		`
+		if DialectOf(tx) != DialectMySQL {
+			sql = `
+			UPDATE columns
+			SET %[1]s = t.%[1]s
+			FROM (
+			    SELECT id, ROW_NUMBER() OVER (PARTITION BY table_id ORDER BY %[1]s) - 1 AS %[1]s
+			    FROM columns
+			    WHERE table_id = ?
+			) AS t
+			WHERE columns.id = t.id AND columns.table_id = ?
+			`
+		}
+		sql = fmt.Sprintf(sql, quote(tx, "index"))
+		err := tx.Exec(sql, tableID, tableID).Error
 		if err != nil {
 			return xerrors.Errorf("Failed to reset column indices: %w", err)
 		}
@@ -71,9 +106,9 @@ func ReorderColumns(db *gorm.DB, tableID UUID, order []UUID) error {
 				params = append(params, tableID)
 				err := tx.Exec(fmt.Sprintf(`
 				UPDATE columns
-				SET `+"`index`"+` = CASE id %s ELSE `+"`index`"+` END
+				SET %[1]s = CASE id %[2]s ELSE %[1]s END
 				WHERE table_id = ?
-				`, cases), params...).Error
+				`, quote(tx, "index"), cases), params...).Error
 				if err != nil {
 					return xerrors.Errorf("Failed to update column indices: %w", err)
 				}
@@ -92,11 +127,7 @@ func ReorderColumns(db *gorm.DB, tableID UUID, order []UUID) error {
 
 func (c *Column) BeforeSave(db *gorm.DB) error {
 	// Shift indices
-	if err := db.Model(&Column{}).
-		Where("table_id = ? AND `index` >= ?", c.TableID, c.Index).
-		Order("`index` DESC").
-		UpdateColumn("`index`", gorm.Expr("`index` + ?", 1)).
-		Error; err != nil {
+	if err := shiftColumnIndices(db, c.TableID, c.Index, 1); err != nil {
 		return xerrors.Errorf("Failed to shift column indices: %w", err)
 	}
 
@@ -176,3 +207,41 @@ func (c *Column) Delete(db *gorm.DB, skipCanonicalizeIndices bool) error {
 
 	return nil
 }
+
+func PurgeColumnData(db *gorm.DB, tableID, columnID UUID, limit int) (int64, error) {
+	path := fmt.Sprintf(`$."%s"`, columnID)
+	sql := `
+	UPDATE table_records
+	SET data = JSON_REMOVE(data, ?)
+	WHERE table_id = ? AND JSON_CONTAINS_PATH(data, 'one', ?)
+	LIMIT ?
+	`
+	switch DialectOf(db) {
+	case DialectSQLite:
+		sql = `
+		UPDATE table_records
+		SET data = json_remove(data, ?)
+		WHERE id IN (
+		    SELECT id FROM table_records
+		    WHERE table_id = ? AND json_type(data, ?) IS NOT NULL
+		    LIMIT ?
+		)
+		`
+	case DialectPostgres:
+		path = columnID.String()
+		sql = `
+		UPDATE table_records
+		SET data = data - CAST(? AS text)
+		WHERE id IN (
+		    SELECT id FROM table_records
+		    WHERE table_id = ? AND data -> CAST(? AS text) IS NOT NULL
+		    LIMIT ?
+		)
+		`
+	}
+	result := db.Exec(sql, path, tableID, path, limit)
+	if result.Error != nil {
+		return 0, xerrors.Errorf("Failed to purge column data: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
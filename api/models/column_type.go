@@ -0,0 +1,260 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+const (
+	ColumnTypeText    = "text"
+	ColumnTypeNumber  = "number"
+	ColumnTypeDate    = "date"
+	ColumnTypeBoolean = "boolean"
+)
+
+const (
+	ConversionOnErrorAbort = "abort"
+	ConversionOnErrorNull  = "null"
+)
+
+const (
+	columnDateFormat            = "2006-01-02"
+	columnConversionBatchSize   = 500
+	maxColumnConversionFailures = 100
+)
+
+var columnDateInputFormats = []string{
+	columnDateFormat,
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+	"2006/1/2",
+}
+
+// ConvertColumnValue converts a cell value to the value of the given column type.
+// It returns false if the value cannot be converted.
+func ConvertColumnValue(value interface{}, to string) (interface{}, bool) {
+	if value == nil {
+		return nil, true
+	}
+
+	switch to {
+	case ColumnTypeText:
+		switch v := value.(type) {
+		case string:
+			return v, true
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), true
+		case bool:
+			return strconv.FormatBool(v), true
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, false
+			}
+			return string(b), true
+		}
+	case ColumnTypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, true
+		case string:
+			f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", ""), 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, false
+			}
+			return f, true
+		case bool:
+			if v {
+				return float64(1), true
+			}
+			return float64(0), true
+		}
+	case ColumnTypeDate:
+		if v, ok := value.(string); ok {
+			for _, layout := range columnDateInputFormats {
+				if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+					return t.Format(columnDateFormat), true
+				}
+			}
+		}
+	case ColumnTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, true
+		case string:
+			switch strings.ToLower(strings.TrimSpace(v)) {
+			case "true", "yes", "1":
+				return true, true
+			case "false", "no", "0":
+				return false, true
+			}
+		case float64:
+			switch v {
+			case 1:
+				return true, true
+			case 0:
+				return false, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+type ConvertColumnTypeOpts struct {
+	Type    string
+	OnError string
+	DryRun  bool
+	// Visible limits the records reported as failures. All records are reported if nil.
+	Visible SQLBuilder
+}
+
+type ColumnConversionFailure struct {
+	RecordID UUID
+	Value    interface{}
+}
+
+type ColumnConversionResult struct {
+	Applied          bool
+	ConvertedRecords int64
+	FailureCount     int64
+	Failures         []ColumnConversionFailure
+}
+
+// addFailures appends the failures of the records satisfying visible up to the limit.
+func (result *ColumnConversionResult) addFailures(db *gorm.DB, tableID UUID, failures []ColumnConversionFailure, visible SQLBuilder) error {
+	if len(failures) == 0 || len(result.Failures) >= maxColumnConversionFailures {
+		return nil
+	}
+
+	hidden := map[UUID]bool{}
+	if visible != nil {
+		ids := make([]UUID, 0, len(failures))
+		for _, f := range failures {
+			ids = append(ids, f.RecordID)
+		}
+		hiddenIDs, err := FindRecordIDsNotMatching(db, tableID, ids, visible)
+		if err != nil {
+			return xerrors.Errorf("Failed to filter failures: %w", err)
+		}
+		for _, id := range hiddenIDs {
+			hidden[id] = true
+		}
+	}
+
+	for _, f := range failures {
+		if len(result.Failures) >= maxColumnConversionFailures {
+			break
+		}
+		if !hidden[f.RecordID] {
+			result.Failures = append(result.Failures, f)
+		}
+	}
+	return nil
+}
+
+var errRollbackConversion = errors.New("Rollback conversion")
+
+func (c *Column) ConvertType(db *gorm.DB, opts *ConvertColumnTypeOpts) (*ColumnConversionResult, error) {
+	result := &ColumnConversionResult{}
+	path := fmt.Sprintf(`$."%s"`, c.ID)
+	containsPath, setValue := "JSON_CONTAINS_PATH(data, 'one', ?)", "JSON_SET(data, ?, CAST(? AS JSON))"
+	switch DialectOf(db) {
+	case DialectSQLite:
+		containsPath, setValue = "json_type(data, ?) IS NOT NULL", "json_set(data, ?, json(?))"
+	case DialectPostgres:
+		path = c.ID.String()
+		containsPath, setValue = "data -> CAST(? AS text) IS NOT NULL", "data || jsonb_build_object(CAST(? AS text), CAST(? AS jsonb))"
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var lastID *UUID
+		for {
+			q := tx.Where("table_id = ? AND "+containsPath, c.TableID, path)
+			if lastID != nil {
+				q = q.Where("id > ?", *lastID)
+			}
+			var records []TableRecord
+			err := q.Order("id").Limit(columnConversionBatchSize).Find(&records).Error
+			if err != nil {
+				return xerrors.Errorf("Failed to get records: %w", err)
+			}
+			if len(records) == 0 {
+				break
+			}
+			lastID = &records[len(records)-1].ID
+
+			var failures []ColumnConversionFailure
+			converted := make([]interface{}, len(records))
+			for i, r := range records {
+				var data map[string]interface{}
+				if err := json.Unmarshal(r.Data, &data); err != nil {
+					return xerrors.Errorf("Failed to deserialize data: %w", err)
+				}
+				value := data[c.ID.String()]
+
+				v, ok := ConvertColumnValue(value, opts.Type)
+				if !ok {
+					result.FailureCount++
+					failures = append(failures, ColumnConversionFailure{
+						RecordID: r.ID,
+						Value:    value,
+					})
+				} else {
+					result.ConvertedRecords++
+				}
+				converted[i] = v
+			}
+
+			// Filter failures before the records are updated
+			if err := result.addFailures(tx, c.TableID, failures, opts.Visible); err != nil {
+				return err
+			}
+
+			// Write only if the conversion can be applied
+			if opts.DryRun || (result.FailureCount > 0 && opts.OnError != ConversionOnErrorNull) {
+				continue
+			}
+			for i, r := range records {
+				b, err := json.Marshal(converted[i])
+				if err != nil {
+					return xerrors.Errorf("Failed to serialize value: %w", err)
+				}
+				err = tx.Exec(
+					"UPDATE table_records SET data = "+setValue+" WHERE id = ?",
+					path, string(b), r.ID,
+				).Error
+				if err != nil {
+					return xerrors.Errorf("Failed to update record: %w", err)
+				}
+			}
+		}
+
+		if opts.DryRun || (result.FailureCount > 0 && opts.OnError != ConversionOnErrorNull) {
+			return errRollbackConversion
+		}
+
+		c.Type = &opts.Type
+		// Skip BeforeSave, which shifts the indices for saving the whole column
+		if err := tx.Model(c).Session(&gorm.Session{SkipHooks: true}).Update("type", opts.Type).Error; err != nil {
+			return xerrors.Errorf("Failed to update column type: %w", err)
+		}
+		result.Applied = true
+
+		return nil
+	})
+	if err != nil && !xerrors.Is(err, errRollbackConversion) {
+		return nil, err
+	}
+
+	return result, nil
+}
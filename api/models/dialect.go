@@ -0,0 +1,30 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Dialect is the SQL dialect of the database, given by the name of the gorm dialector.
+type Dialect string
+
+const (
+	DialectMySQL    Dialect = "mysql"
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+)
+
+func DialectOf(db *gorm.DB) Dialect {
+	return Dialect(db.Dialector.Name())
+}
+
+// quote quotes the identifier for the database, e.g. the index column whose name is a reserved word.
+func quote(db *gorm.DB, name string) string {
+	return db.Statement.Quote(name)
+}
+
+// ForUpdate locks the rows selected with the returned db until the end of the transaction.
+// SQLite locks the whole database in the write transaction instead.
+func ForUpdate(db *gorm.DB) *gorm.DB {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"})
+}
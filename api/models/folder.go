@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -45,7 +46,7 @@ func (f *Folder) GetChildren(db *gorm.DB, opts *GetFolderChildrenOpts) ([]TableF
 		},
 	}
 
-	order, err := convertGetListSortKeyToOrderString(opts.Sort, []string{"id", "type", "created_at", "updated_at"})
+	order, err := convertGetListSortKeyToOrderString(DialectOf(db), opts.Sort, []string{"id", "type", "created_at", "updated_at"})
 	if err != nil {
 		return nil, 0, xerrors.Errorf("Failed to convert sort key: %w", err)
 	}
@@ -68,6 +69,135 @@ func (f *Folder) GetChildren(db *gorm.DB, opts *GetFolderChildrenOpts) ([]TableF
 	return children, totalCount, nil
 }
 
+// ErrTooManyEntries is returned by GetTree if the tree has more entries than MaxEntries.
+var ErrTooManyEntries = errors.New("Too many entries")
+
+type GetFolderTreeOpts struct {
+	// Depth limits the levels of descendants, unlimited if 0
+	Depth int
+	// Type limits the entries to the type, which are nested under their nearest ancestors of the type
+	Type string
+	Sort []GetListSortKey
+	// MaxEntries limits the number of entries in the tree, unlimited if 0
+	MaxEntries int
+}
+
+type TableFilesystemTreeEntry struct {
+	TableFilesystemEntry
+	Children []TableFilesystemTreeEntry
+}
+
+// GetTree returns the descendants of the folder as a tree, fetched by a single recursive query.
+func (f *Folder) GetTree(db *gorm.DB, opts *GetFolderTreeOpts) ([]TableFilesystemTreeEntry, error) {
+	d := DialectOf(db)
+	initIDs, appendID, containsID := visitedIDsSQL(d)
+
+	seed := "organization_id = ? AND parent_folder_id = ?"
+	params := []interface{}{f.OrganizationID, f.ID}
+	if f.ID == UUID(uuid.Nil) {
+		seed = "organization_id = ? AND parent_folder_id IS NULL"
+		params = params[:1]
+	}
+	var cond string
+	if opts.Depth > 0 {
+		cond += " AND rec.depth < ?"
+		params = append(params, opts.Depth)
+	}
+	// Walk through all the folders and filter the entries returned
+	var where string
+	if opts.Type != "" {
+		where = "WHERE type = ?"
+		params = append(params, opts.Type)
+	}
+	var limit string
+	if opts.MaxEntries > 0 {
+		limit = "LIMIT ?"
+		params = append(params, opts.MaxEntries+1)
+	}
+
+	order, err := convertGetListSortKeyToOrderString(d, opts.Sort, []string{"id", "type", "created_at", "updated_at"})
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to convert sort key: %w", err)
+	}
+
+	var entries []TableFilesystemEntry
+	err = db.Raw(`
+	WITH recursive rec(id, organization_id, type, parent_folder_id, properties, created_at, updated_at, depth, all_ids) AS (
+	    SELECT id, organization_id, type, parent_folder_id, properties, created_at, updated_at, 1, `+initIDs+`
+	    FROM table_filesystem_entries
+	    WHERE `+seed+`
+	    UNION ALL
+	    SELECT e.id, e.organization_id, e.type, e.parent_folder_id, e.properties, e.created_at, e.updated_at, rec.depth + 1, `+appendID+`
+	    FROM rec
+	    INNER JOIN table_filesystem_entries AS e
+	    ON e.parent_folder_id = rec.id AND
+	       e.organization_id = rec.organization_id
+	    WHERE rec.type = 'folder' AND
+	          NOT `+containsID+cond+`
+	)
+	SELECT id, organization_id, type, parent_folder_id, properties, created_at, updated_at
+	FROM rec
+	`+where+`
+	ORDER BY `+order+`
+	`+limit+`
+	`, params...).Scan(&entries).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get tree: %w", err)
+	}
+	if opts.MaxEntries > 0 && len(entries) > opts.MaxEntries {
+		return nil, ErrTooManyEntries
+	}
+
+	// Build the tree keeping the order of siblings.
+	// Tables have no table ancestors, so they are all at the top level if limited to tables.
+	children := make(map[UUID][]TableFilesystemEntry)
+	for _, e := range entries {
+		parentID := f.ID
+		if e.ParentFolderID != nil && opts.Type != "table" {
+			parentID = *e.ParentFolderID
+		}
+		children[parentID] = append(children[parentID], e)
+	}
+	var build func(parentID UUID, depth int) []TableFilesystemTreeEntry
+	build = func(parentID UUID, depth int) []TableFilesystemTreeEntry {
+		tree := []TableFilesystemTreeEntry{}
+		for _, e := range children[parentID] {
+			// The folder itself is reached again only through a cycle
+			if e.ID == f.ID {
+				continue
+			}
+			node := TableFilesystemTreeEntry{TableFilesystemEntry: e}
+			if e.Type == "folder" && (opts.Depth == 0 || depth < opts.Depth) {
+				node.Children = build(e.ID, depth+1)
+			}
+			tree = append(tree, node)
+		}
+		return tree
+	}
+	return build(f.ID, 1), nil
+}
+
+func (f *Folder) GetChildTables(db *gorm.DB) ([]Table, error) {
+	var entries []TableFilesystemEntry
+	q := db.Model(&TableFilesystemEntry{}).
+		Where("organization_id = ? AND type = ?", f.OrganizationID, "table")
+	if f.ID == UUID(uuid.Nil) {
+		q = q.Where("parent_folder_id IS NULL")
+	} else {
+		q = q.Where("parent_folder_id = ?", f.ID)
+	}
+	err := q.Order("created_at ASC, id ASC").Find(&entries).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get child tables: %w", err)
+	}
+
+	var tables []Table
+	for _, e := range entries {
+		tables = append(tables, Table{TableFilesystemEntry: e})
+	}
+	return tables, nil
+}
+
 func (f *Folder) Create(db *gorm.DB) error {
 	if f.ID == UUID(uuid.Nil) {
 		id, err := uuid.NewRandom()
@@ -120,3 +250,74 @@ func (f *Folder) Delete(db *gorm.DB) error {
 	}
 	return nil
 }
+
+type CopyFolderOpts struct {
+	ParentFolderID *UUID
+	Properties     Properties
+	IncludeRecords bool
+}
+
+func (f *Folder) Copy(db *gorm.DB, opts *CopyFolderOpts) (*Folder, error) {
+	dup := &Folder{
+		TableFilesystemEntry: TableFilesystemEntry{
+			OrganizationID: f.OrganizationID,
+			ParentFolderID: opts.ParentFolderID,
+			Properties:     make(Properties),
+		},
+	}
+	for k, v := range f.Properties {
+		dup.Properties[k] = v
+	}
+	for k, v := range opts.Properties {
+		dup.Properties[k] = v
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Fetch children before creating the copy so as not to contain itself
+		var children []TableFilesystemEntry
+		err := tx.Model(&TableFilesystemEntry{}).
+			Where("organization_id = ? AND parent_folder_id = ?", f.OrganizationID, f.ID).
+			Order("created_at ASC, id ASC").
+			Find(&children).
+			Error
+		if err != nil {
+			return xerrors.Errorf("Failed to get children: %w", err)
+		}
+
+		// Create folder
+		if err := dup.Create(tx); err != nil {
+			return xerrors.Errorf("Failed to create folder: %w", err)
+		}
+
+		// Copy children
+		for _, c := range children {
+			switch c.Type {
+			case "folder":
+				_, err := (&Folder{TableFilesystemEntry: c}).Copy(tx, &CopyFolderOpts{
+					ParentFolderID: &dup.ID,
+					IncludeRecords: opts.IncludeRecords,
+				})
+				if err != nil {
+					return xerrors.Errorf("Failed to copy folder: %w", err)
+				}
+			case "table":
+				_, err := (&Table{TableFilesystemEntry: c}).Duplicate(tx, &DuplicateTableOpts{
+					ParentFolderID: &dup.ID,
+					IncludeRecords: opts.IncludeRecords,
+				})
+				if err != nil {
+					return xerrors.Errorf("Failed to duplicate table: %w", err)
+				}
+			default:
+				return fmt.Errorf("Invalid entry type: %s", c.Type)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dup, nil
+}
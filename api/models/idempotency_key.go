@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKey holds the response to a request with an idempotency key.
+// StatusCode is nil while the request is in progress.
+type IdempotencyKey struct {
+	KeyHash     string `gorm:"primaryKey"`
+	RequestHash string
+	StatusCode  *int
+	Header      JSON
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+func GetIdempotencyKey(db *gorm.DB, keyHash string) (*IdempotencyKey, error) {
+	var k IdempotencyKey
+	err := db.Where("key_hash = ?", keyHash).First(&k).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get model: %w", err)
+	}
+	return &k, nil
+}
+
+func DeleteIdempotencyKeysBefore(db *gorm.DB, t time.Time) error {
+	err := db.Where("created_at < ?", t).Delete(&IdempotencyKey{}).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to delete models: %w", err)
+	}
+	return nil
+}
+
+// CreateIfNotExists creates the key and reports whether it did not exist.
+func (k *IdempotencyKey) CreateIfNotExists(db *gorm.DB) (bool, error) {
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(k)
+	if result.Error != nil {
+		return false, xerrors.Errorf("Failed to create model: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (k *IdempotencyKey) Save(db *gorm.DB) error {
+	err := db.Save(k).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to save model: %w", err)
+	}
+	return nil
+}
+
+func (k *IdempotencyKey) Delete(db *gorm.DB) error {
+	err := db.Where("key_hash = ?", k.KeyHash).Delete(&IdempotencyKey{}).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to delete model: %w", err)
+	}
+	return nil
+}
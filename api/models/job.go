@@ -0,0 +1,154 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+type Job struct {
+	ID             UUID
+	OrganizationID UUID
+	Type           string
+	Status         string
+	Owner          *string
+	LeaseExpiresAt *time.Time
+	Params         JSON
+	Progress       JSON
+	Result         JSON
+	Error          *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+}
+
+// GetClaimableJobs returns pending jobs and running jobs whose lease has expired.
+func GetClaimableJobs(db *gorm.DB, now time.Time) ([]Job, error) {
+	var jobs []Job
+	err := db.Where(claimableJobCondition, JobStatusPending, JobStatusRunning, now).
+		Order("created_at").
+		Find(&jobs).
+		Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get models: %w", err)
+	}
+	return jobs, nil
+}
+
+const claimableJobCondition = "(status = ? OR (status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)))"
+
+// Claim atomically takes the job for the owner until leaseExpiresAt. It returns
+// false if the job has been taken by another owner or has completed.
+func (j *Job) Claim(db *gorm.DB, owner string, now, leaseExpiresAt time.Time) (bool, error) {
+	result := db.Model(&Job{}).
+		Where("id = ?", j.ID).
+		Where(claimableJobCondition, JobStatusPending, JobStatusRunning, now).
+		Updates(map[string]interface{}{
+			"status":           JobStatusRunning,
+			"owner":            owner,
+			"lease_expires_at": leaseExpiresAt,
+			"started_at":       gorm.Expr("COALESCE(started_at, ?)", now),
+		})
+	if result.Error != nil {
+		return false, xerrors.Errorf("Failed to claim job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	if _, err := j.Get(db); err != nil {
+		return false, xerrors.Errorf("Failed to get job: %w", err)
+	}
+	return true, nil
+}
+
+// RenewLease extends the lease held by the owner. It returns false if the lease has been lost.
+func (j *Job) RenewLease(db *gorm.DB, owner string, leaseExpiresAt time.Time) (bool, error) {
+	result := db.Model(&Job{}).
+		Where("id = ? AND status = ? AND owner = ?", j.ID, JobStatusRunning, owner).
+		Update("lease_expires_at", leaseExpiresAt)
+	if result.Error != nil {
+		return false, xerrors.Errorf("Failed to renew lease: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	j.LeaseExpiresAt = &leaseExpiresAt
+	return true, nil
+}
+
+// Complete saves the outcome of the job if the owner still holds its lease.
+func (j *Job) Complete(db *gorm.DB, owner string) (bool, error) {
+	j.LeaseExpiresAt = nil
+	result := db.Model(&Job{}).
+		Where("id = ? AND owner = ?", j.ID, owner).
+		Updates(map[string]interface{}{
+			"status":           j.Status,
+			"result":           j.Result,
+			"error":            j.Error,
+			"finished_at":      j.FinishedAt,
+			"lease_expires_at": nil,
+		})
+	if result.Error != nil {
+		return false, xerrors.Errorf("Failed to complete job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (j *Job) Create(db *gorm.DB) error {
+	if j.ID == UUID(uuid.Nil) {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return xerrors.Errorf("Failed to generate id: %w", err)
+		}
+		j.ID = UUID(id)
+	}
+	if j.Status == "" {
+		j.Status = JobStatusPending
+	}
+
+	err := db.Create(j).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to create model: %w", err)
+	}
+	return nil
+}
+
+func (j *Job) Save(db *gorm.DB) error {
+	if j.ID == UUID(uuid.Nil) {
+		return fmt.Errorf("Empty id")
+	}
+	err := db.Save(j).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to save model: %w", err)
+	}
+	return nil
+}
+
+func (j *Job) Get(db *gorm.DB) (*Job, error) {
+	err := db.Where("id = ?", j.ID).First(j).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get model: %w", err)
+	}
+	return j, nil
+}
+
+func (j *Job) SaveProgress(db *gorm.DB, progress JSON) error {
+	err := db.Model(j).Update("progress", progress).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to save progress: %w", err)
+	}
+	j.Progress = progress
+	return nil
+}
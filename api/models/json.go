@@ -9,8 +9,18 @@ import (
 type JSON json.RawMessage
 
 func (j *JSON) Scan(value interface{}) error {
-	bytes, ok := value.([]byte)
-	if !ok {
+	if value == nil {
+		*j = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
 		return fmt.Errorf("Invalid type: %v (%T)", value, value)
 	}
 
@@ -24,5 +34,6 @@ func (j JSON) Value() (driver.Value, error) {
 	if len(j) == 0 {
 		return nil, nil
 	}
-	return json.RawMessage(j).MarshalJSON()
+	// Pass JSON as text since SQLite stores []byte as a blob, which is not valid JSON there
+	return string(j), nil
 }
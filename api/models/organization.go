@@ -17,20 +17,25 @@ type Organization struct {
 }
 
 type GetOrganizationListOpts struct {
+	ID            *UUID
 	Sort          []GetListSortKey
 	Offset, Limit int
 }
 
 func GetOrganizationList(db *gorm.DB, opts *GetOrganizationListOpts) ([]Organization, int64, error) {
-	order, err := convertGetListSortKeyToOrderString(opts.Sort, []string{"id", "created_at", "updated_at"})
+	order, err := convertGetListSortKeyToOrderString(DialectOf(db), opts.Sort, []string{"id", "created_at", "updated_at"})
 	if err != nil {
 		return nil, 0, xerrors.Errorf("Failed to convert sort key: %w", err)
 	}
 
+	q := db.Model(&Organization{})
+	if opts.ID != nil {
+		q = q.Where("id = ?", *opts.ID)
+	}
+
 	var organizations []Organization
 	var totalCount int64
-	err = db.Model(&Organization{}).
-		Count(&totalCount).
+	err = q.Count(&totalCount).
 		Order(order).Offset(opts.Offset).Limit(opts.Limit).Find(&organizations).
 		Error
 	if err != nil {
@@ -33,8 +33,13 @@ func (p Properties) SelectKeys(keys []string) Properties {
 }
 
 func (p *Properties) Scan(value interface{}) error {
-	bytes, ok := value.([]byte)
-	if !ok {
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
 		return fmt.Errorf("Invalid type: %v (%T)", value, value)
 	}
 
@@ -63,5 +68,9 @@ func (p Properties) Value() (driver.Value, error) {
 		delete(p, k)
 	}
 
-	return json.Marshal(&p)
+	b, err := json.Marshal(&p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
 }
@@ -23,7 +23,7 @@ type TableRecord struct {
 }
 
 type SQLBuilder interface {
-	BuildSQL() (string, []interface{}, error)
+	BuildSQL(d Dialect) (string, []interface{}, error)
 }
 
 type InsertQuery struct {
@@ -77,7 +77,7 @@ func (q *InsertQuery) Execute(db *gorm.DB) ([]UUID, error) {
 			sql += `,`
 		}
 		sql += ` (?, ?, ?, ?, ?, ?)`
-		params = append(params, id, q.TableID, dataJSON, propertiesJSON, now, now)
+		params = append(params, id, q.TableID, string(dataJSON), string(propertiesJSON), now, now)
 	}
 
 	if err := db.Exec(sql, params...).Error; err != nil {
@@ -96,22 +96,47 @@ type SelectQuery struct {
 	Limit   *int
 }
 
+// maxExecutionTimeHint makes MySQL abort the select on the deadline of the context,
+// since cancelling the context only closes the connection on the client side.
+func maxExecutionTimeHint(db *gorm.DB) string {
+	if DialectOf(db) != DialectMySQL {
+		return ""
+	}
+	if db.Statement == nil || db.Statement.Context == nil {
+		return ""
+	}
+	deadline, ok := db.Statement.Context.Deadline()
+	if !ok {
+		return ""
+	}
+	ms := time.Until(deadline).Milliseconds() + 1
+	if ms < 1 {
+		ms = 1
+	}
+	return fmt.Sprintf(" /*+ MAX_EXECUTION_TIME(%d) */", ms)
+}
+
 func (q *SelectQuery) Execute(db *gorm.DB, dest interface{}) error {
+	d := DialectOf(db)
 	var sql string
 	var params []interface{}
+	jsonColumns := make(map[string]bool)
 	switch t := q.From.(type) {
 	case TableExpr:
-		sql = `SELECT`
+		sql = `SELECT` + maxExecutionTimeHint(db)
 		for i, c := range q.Columns {
 			if i > 0 {
 				sql += `,`
 			}
-			s, p, err := c.BuildSQL()
+			s, p, err := c.BuildSQL(d)
 			if err != nil {
 				return xerrors.Errorf("Failed to build select column sql: %w", err)
 			}
 			sql += s
 			params = append(params, p...)
+			if _, ok := c.Column.(jsonSQLBuilder); ok && c.As != "" {
+				jsonColumns[c.As] = true
+			}
 		}
 
 		sql += `
@@ -121,11 +146,11 @@ func (q *SelectQuery) Execute(db *gorm.DB, dest interface{}) error {
 		params = append(params, t.Table.ID)
 
 		if q.Where != nil {
-			s, p, err := q.Where.BuildSQL()
+			s, p, err := q.Where.BuildSQL(d)
 			if err != nil {
 				return xerrors.Errorf("Failed to build where sql: %w", err)
 			}
-			sql += " AND (" + s + ") "
+			sql += " AND " + booleanSQL(d, s) + " "
 			params = append(params, p...)
 		}
 
@@ -135,7 +160,7 @@ func (q *SelectQuery) Execute(db *gorm.DB, dest interface{}) error {
 				if i > 0 {
 					sql += ","
 				}
-				s, p, err := o.BuildSQL()
+				s, p, err := o.BuildSQL(d)
 				if err != nil {
 					return xerrors.Errorf("Failed to build order by sql: %w", err)
 				}
@@ -170,8 +195,14 @@ func (q *SelectQuery) Execute(db *gorm.DB, dest interface{}) error {
 		if err := db.ScanRows(rows, &record); err != nil {
 			return xerrors.Errorf("Failed to scan row: %w", err)
 		}
+		for k, v := range record {
+			// Values of the columns without the declared types in SQLite
+			if p, ok := v.(*interface{}); ok {
+				record[k] = *p
+			}
+		}
 		for _, t := range colTypes {
-			if t.DatabaseTypeName() == "JSON" {
+			if t.DatabaseTypeName() == "JSON" || t.DatabaseTypeName() == "JSONB" || jsonColumns[t.Name()] {
 				if val, exists := record[t.Name()]; exists {
 					if s, ok := val.(string); ok {
 						var v interface{}
@@ -184,6 +215,9 @@ func (q *SelectQuery) Execute(db *gorm.DB, dest interface{}) error {
 		}
 		records = append(records, record)
 	}
+	if err := rows.Err(); err != nil {
+		return xerrors.Errorf("Failed to read rows: %w", err)
+	}
 	reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(records))
 
 	return nil
@@ -196,6 +230,7 @@ type UpdateQuery struct {
 }
 
 func (q *UpdateQuery) Execute(db *gorm.DB) error {
+	d := DialectOf(db)
 	sql := "UPDATE "
 	var params []interface{}
 
@@ -220,17 +255,12 @@ func (q *UpdateQuery) Execute(db *gorm.DB) error {
 		}
 
 		if len(data) > 0 {
-			sql += " SET data = JSON_SET(data"
-			for k, v := range data {
-				sql += `, `
-				s, p, err := v.BuildSQL()
-				if err != nil {
-					return xerrors.Errorf("Failed to build value sql: %w", err)
-				}
-				sql += fmt.Sprintf(`'$."%s"', %s`, k, s)
-				params = append(params, p...)
+			s, p, err := jsonSetSQL(d, "data", data)
+			if err != nil {
+				return err
 			}
-			sql += ")"
+			sql += " SET data = " + s
+			params = append(params, p...)
 		}
 		if len(properties) > 0 {
 			if len(data) > 0 {
@@ -238,17 +268,12 @@ func (q *UpdateQuery) Execute(db *gorm.DB) error {
 			} else {
 				sql += " SET "
 			}
-			sql += "properties = JSON_SET(properties"
-			for k, v := range properties {
-				sql += `, `
-				s, p, err := v.BuildSQL()
-				if err != nil {
-					return xerrors.Errorf("Failed to build value sql: %w", err)
-				}
-				sql += fmt.Sprintf(`'$."%s"', %s`, k, s)
-				params = append(params, p...)
+			s, p, err := jsonSetSQL(d, "properties", properties)
+			if err != nil {
+				return err
 			}
-			sql += ")"
+			sql += "properties = " + s
+			params = append(params, p...)
 		}
 
 		sql += `
@@ -256,11 +281,11 @@ func (q *UpdateQuery) Execute(db *gorm.DB) error {
 		`
 		params = append(params, t.Table.ID)
 
-		s, p, err := q.Where.BuildSQL()
+		s, p, err := q.Where.BuildSQL(d)
 		if err != nil {
 			return xerrors.Errorf("Failed to build where sql: %w", err)
 		}
-		sql += " AND (" + s + ") "
+		sql += " AND " + booleanSQL(d, s) + " "
 		params = append(params, p...)
 	default:
 		return fmt.Errorf("Invalid table type: %T", t)
@@ -274,12 +299,43 @@ func (q *UpdateQuery) Execute(db *gorm.DB) error {
 	return nil
 }
 
+// jsonSetSQL returns the JSON column with the values set to the keys.
+func jsonSetSQL(d Dialect, column string, values map[string]SQLBuilder) (string, []interface{}, error) {
+	var sql string
+	var params []interface{}
+	for k, v := range values {
+		s, p, err := v.BuildSQL(d)
+		if err != nil {
+			return "", nil, xerrors.Errorf("Failed to build value sql: %w", err)
+		}
+		if d == DialectPostgres {
+			if sql != "" {
+				sql += ", "
+			}
+			sql += fmt.Sprintf(`'%s', %s`, k, s)
+		} else {
+			sql += fmt.Sprintf(`, '$."%s"', %s`, k, s)
+		}
+		params = append(params, p...)
+	}
+	if d == DialectPostgres {
+		return fmt.Sprintf("%s || jsonb_build_object(%s)", column, sql), params, nil
+	}
+	return fmt.Sprintf("JSON_SET(%s%s)", column, sql), params, nil
+}
+
+// SelectIDs returns ids of the records to be updated and locks them.
+func (q *UpdateQuery) SelectIDs(db *gorm.DB) ([]UUID, error) {
+	return selectRecordIDsForUpdate(db, q.Table, q.Where)
+}
+
 type DeleteQuery struct {
 	Table interface{}
 	Where SQLBuilder
 }
 
 func (q *DeleteQuery) Execute(db *gorm.DB) error {
+	d := DialectOf(db)
 	sql := "DELETE FROM "
 	var params []interface{}
 
@@ -291,11 +347,11 @@ func (q *DeleteQuery) Execute(db *gorm.DB) error {
 		`
 		params = append(params, t.Table.ID)
 
-		s, p, err := q.Where.BuildSQL()
+		s, p, err := q.Where.BuildSQL(d)
 		if err != nil {
 			return xerrors.Errorf("Failed to build where sql: %w", err)
 		}
-		sql += " AND (" + s + ") "
+		sql += " AND " + booleanSQL(d, s) + " "
 		params = append(params, p...)
 	default:
 		return fmt.Errorf("Invalid table type: %T", t)
@@ -309,6 +365,109 @@ func (q *DeleteQuery) Execute(db *gorm.DB) error {
 	return nil
 }
 
+// SelectIDs returns ids of the records to be deleted and locks them.
+func (q *DeleteQuery) SelectIDs(db *gorm.DB) ([]UUID, error) {
+	return selectRecordIDsForUpdate(db, q.Table, q.Where)
+}
+
+func selectRecordIDsForUpdate(db *gorm.DB, table interface{}, where SQLBuilder) ([]UUID, error) {
+	t, ok := table.(TableExpr)
+	if !ok {
+		return nil, fmt.Errorf("Invalid table type: %T", table)
+	}
+	d := DialectOf(db)
+
+	sql := `
+	SELECT id FROM table_records
+	WHERE table_id = ?
+	`
+	params := []interface{}{t.Table.ID}
+
+	s, p, err := where.BuildSQL(d)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to build where sql: %w", err)
+	}
+	sql += " AND " + booleanSQL(d, s)
+	params = append(params, p...)
+	// SQLite locks the whole database in the write transaction instead
+	if d != DialectSQLite {
+		sql += " FOR UPDATE"
+	}
+
+	rows, err := db.Raw(sql, params...).Rows()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []UUID
+	for rows.Next() {
+		var id UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, xerrors.Errorf("Failed to scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("Failed to read rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// FindRecordIDsNotMatching returns the ids of the records which do not satisfy cond.
+// A record for which cond evaluates to null is regarded as not matching.
+func FindRecordIDsNotMatching(db *gorm.DB, tableID UUID, ids []UUID, cond SQLBuilder) ([]UUID, error) {
+	return findRecordIDs(db, "table_records", "table_id", tableID, ids, cond, false)
+}
+
+// FindRecordIDsMatching returns the ids of the records which satisfy cond. Ids of missing records are excluded.
+func FindRecordIDsMatching(db *gorm.DB, tableID UUID, ids []UUID, cond SQLBuilder) ([]UUID, error) {
+	return findRecordIDs(db, "table_records", "table_id", tableID, ids, cond, true)
+}
+
+// findRecordIDs selects the distinct ids of the rows of the records table whose key column is key.
+func findRecordIDs(db *gorm.DB, from, keyColumn string, key UUID, ids []UUID, cond SQLBuilder, match bool) ([]UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	d := DialectOf(db)
+	s, p, err := cond.BuildSQL(d)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to build condition sql: %w", err)
+	}
+	not := "NOT "
+	if match {
+		not = ""
+	}
+	sql := `
+	SELECT DISTINCT id FROM ` + from + `
+	WHERE ` + keyColumn + ` = ? AND id IN ? AND ` + not + `COALESCE(` + booleanSQL(d, s) + `, FALSE)
+	`
+	params := append([]interface{}{key, ids}, p...)
+
+	rows, err := db.Raw(sql, params...).Rows()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var result []UUID
+	for rows.Next() {
+		var id UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, xerrors.Errorf("Failed to scan id: %w", err)
+		}
+		result = append(result, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("Failed to read rows: %w", err)
+	}
+
+	return result, nil
+}
+
 type MetadataExprKey int
 
 const (
@@ -320,11 +479,17 @@ type MetadataExpr struct {
 	Key MetadataExprKey
 }
 
-func (e MetadataExpr) BuildSQL() (string, []interface{}, error) {
+func (e MetadataExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
 	switch e.Key {
 	case MetadataExprKeyID:
+		if d == DialectPostgres {
+			return " to_jsonb(id_string) ", nil, nil
+		}
 		return " id_string ", nil, nil
 	case MetadataExprKeyCreatedAt:
+		if d == DialectPostgres {
+			return ` to_jsonb(to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"')) `, nil, nil
+		}
 		return " created_at ", nil, nil
 	default:
 		return "", nil, fmt.Errorf("Invalid metadata key: %v", e.Key)
@@ -335,42 +500,125 @@ type PropertyExpr struct {
 	Key string
 }
 
-func (e PropertyExpr) BuildSQL() (string, []interface{}, error) {
+func (e PropertyExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
 	if !PropertiesKeyPattern.MatchString(e.Key) {
 		return "", nil, fmt.Errorf("Invalid property key: %s", e.Key)
 	}
-	sql := fmt.Sprintf(`
-	CAST(CASE WHEN JSON_EXTRACT(properties, '$."%s"') IS NULL OR JSON_TYPE(JSON_EXTRACT(properties, '$."%s"')) = 'NULL' THEN NULL
-	          ELSE JSON_EXTRACT(properties, '$."%s"')
-	     END AS JSON)
-	`, e.Key, e.Key, e.Key)
+	return jsonValueSQL(d, "properties", e.Key), nil, nil
+}
 
-	return sql, nil, nil
+func (e PropertyExpr) BuildJSONSQL(d Dialect) (string, []interface{}, error) {
+	if !PropertiesKeyPattern.MatchString(e.Key) {
+		return "", nil, fmt.Errorf("Invalid property key: %s", e.Key)
+	}
+	return jsonDocumentSQL(d, "properties", e.Key), nil, nil
 }
 
 type ColumnExpr struct {
 	Column Column
 }
 
-func (e ColumnExpr) BuildSQL() (string, []interface{}, error) {
-	id := e.Column.ID.String()
-	sql := fmt.Sprintf(`
-	CAST(CASE WHEN JSON_EXTRACT(data, '$."%s"') IS NULL OR JSON_TYPE(JSON_EXTRACT(data, '$."%s"')) = 'NULL' THEN NULL
-	          ELSE JSON_EXTRACT(data, '$."%s"')
+func (e ColumnExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	return jsonValueSQL(d, "data", e.Column.ID.String()), nil, nil
+}
+
+func (e ColumnExpr) BuildJSONSQL(d Dialect) (string, []interface{}, error) {
+	return jsonDocumentSQL(d, "data", e.Column.ID.String()), nil, nil
+}
+
+// jsonSQLBuilder is an expression of a JSON value, which is selected as a JSON document.
+type jsonSQLBuilder interface {
+	BuildJSONSQL(d Dialect) (string, []interface{}, error)
+}
+
+// jsonValueSQL returns the value of the key in the JSON column, which is null if the key is missing or the value is JSON null.
+// The value is JSON in MySQL, jsonb in PostgreSQL, and a SQL value converted from JSON in SQLite.
+func jsonValueSQL(d Dialect, column, key string) string {
+	switch d {
+	case DialectSQLite:
+		return fmt.Sprintf(` json_extract(%s, '$."%s"') `, column, key)
+	case DialectPostgres:
+		return fmt.Sprintf(` NULLIF(%s -> '%s', CAST('null' AS jsonb)) `, column, key)
+	}
+	return fmt.Sprintf(`
+	CAST(CASE WHEN JSON_EXTRACT(%s, '$."%s"') IS NULL OR JSON_TYPE(JSON_EXTRACT(%s, '$."%s"')) = 'NULL' THEN NULL
+	          ELSE JSON_EXTRACT(%s, '$."%s"')
 	     END AS JSON)
-	`, id, id, id)
+	`, column, key, column, key, column, key)
+}
 
-	return sql, nil, nil
+// jsonDocumentSQL is the same as jsonValueSQL except that SQLite returns the value as a JSON text.
+func jsonDocumentSQL(d Dialect, column, key string) string {
+	if d == DialectSQLite {
+		return fmt.Sprintf(` NULLIF(%s -> '$."%s"', 'null') `, column, key)
+	}
+	return jsonValueSQL(d, column, key)
+}
+
+// jsonTextSQL returns the value of the key in the JSON column as a text, e.g. to be matched with LIKE.
+func jsonTextSQL(d Dialect, column, key string) string {
+	switch d {
+	case DialectSQLite:
+		return fmt.Sprintf(` json_extract(%s, '$."%s"') `, column, key)
+	case DialectPostgres:
+		return fmt.Sprintf(` (%s ->> '%s') `, column, key)
+	}
+	return fmt.Sprintf(` JSON_UNQUOTE(JSON_EXTRACT(%s, '$."%s"')) `, column, key)
 }
 
 type ValueExpr struct {
 	Value interface{}
 }
 
-func (e ValueExpr) BuildSQL() (string, []interface{}, error) {
+func (e ValueExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if d == DialectPostgres {
+		if e.Value == nil {
+			return " CAST(NULL AS jsonb) ", nil, nil
+		}
+		b, err := json.Marshal(e.Value)
+		if err != nil {
+			return "", nil, xerrors.Errorf("Failed to serialize value: %w", err)
+		}
+		return " CAST(? AS jsonb) ", []interface{}{string(b)}, nil
+	}
 	return " ? ", []interface{}{e.Value}, nil
 }
 
+// In PostgreSQL, all expressions are jsonb so that they can be compared with the values of the columns
+// and combined with each other. The operands are converted to SQL values for the operators.
+
+// jsonbSQL converts the result of the operator to jsonb in PostgreSQL.
+func jsonbSQL(d Dialect, sql string) string {
+	if d == DialectPostgres {
+		return fmt.Sprintf(" to_jsonb(%s) ", sql)
+	}
+	return fmt.Sprintf(" %s ", sql)
+}
+
+// booleanSQL converts the operand to boolean in PostgreSQL, e.g. to use it as a condition.
+func booleanSQL(d Dialect, sql string) string {
+	if d == DialectPostgres {
+		return fmt.Sprintf("CAST((%s) AS boolean)", sql)
+	}
+	return fmt.Sprintf("(%s)", sql)
+}
+
+// numericSQL converts the operand to numeric in PostgreSQL.
+func numericSQL(d Dialect, sql string) string {
+	if d == DialectPostgres {
+		return fmt.Sprintf("CAST((%s) AS numeric)", sql)
+	}
+	return fmt.Sprintf("(%s)", sql)
+}
+
+// textSQL converts the operand to text in PostgreSQL.
+func textSQL(d Dialect, sql string) string {
+	if d == DialectPostgres {
+		return fmt.Sprintf("((%s) #>> '{}')", sql)
+	}
+	return fmt.Sprintf("(%s)", sql)
+}
+
 type FuncExprFunc int
 
 const (
@@ -382,11 +630,11 @@ type FuncExpr struct {
 	Args []SQLBuilder
 }
 
-func (e FuncExpr) BuildSQL() (string, []interface{}, error) {
+func (e FuncExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
 	var args []string
 	var params []interface{}
 	for _, arg := range e.Args {
-		s, p, err := arg.BuildSQL()
+		s, p, err := arg.BuildSQL(d)
 		if err != nil {
 			return "", nil, xerrors.Errorf("Failed to build func arg sql: %w", err)
 		}
@@ -402,15 +650,15 @@ func (e FuncExpr) BuildSQL() (string, []interface{}, error) {
 		return "", nil, fmt.Errorf("Invalid func: %v", e.Func)
 	}
 
-	return fmt.Sprintf(" %s(%s) ", fn, strings.Join(args, ", ")), params, nil
+	return jsonbSQL(d, fmt.Sprintf("%s(%s)", fn, strings.Join(args, ", "))), params, nil
 }
 
 type UnaryOpExpr struct {
 	Op SQLBuilder
 }
 
-func (e UnaryOpExpr) BuildSQL() (string, []interface{}, error) {
-	s, p, err := e.Op.BuildSQL()
+func (e UnaryOpExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	s, p, err := e.Op.BuildSQL(d)
 	if err != nil {
 		return "", nil, xerrors.Errorf("Failed to build operand sql: %w", err)
 	}
@@ -422,12 +670,12 @@ type BinOpExpr struct {
 	Op2 SQLBuilder
 }
 
-func (e BinOpExpr) BuildSQL() ([]string, [][]interface{}, error) {
-	s1, p1, err := e.Op1.BuildSQL()
+func (e BinOpExpr) BuildSQL(d Dialect) ([]string, [][]interface{}, error) {
+	s1, p1, err := e.Op1.BuildSQL(d)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("Failed to build first operand sql: %w", err)
 	}
-	s2, p2, err := e.Op2.BuildSQL()
+	s2, p2, err := e.Op2.BuildSQL(d)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("Failed to build second operand sql: %w", err)
 	}
@@ -436,171 +684,182 @@ func (e BinOpExpr) BuildSQL() ([]string, [][]interface{}, error) {
 
 type EqExpr BinOpExpr
 
-func (e EqExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e EqExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) = (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("(%s) = (%s)", s[0], s[1])), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type NeExpr BinOpExpr
 
-func (e NeExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e NeExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) != (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("(%s) != (%s)", s[0], s[1])), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type GtExpr BinOpExpr
 
-func (e GtExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e GtExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) > (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("(%s) > (%s)", s[0], s[1])), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type GeExpr BinOpExpr
 
-func (e GeExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e GeExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) >= (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("(%s) >= (%s)", s[0], s[1])), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type LtExpr BinOpExpr
 
-func (e LtExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e LtExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) < (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("(%s) < (%s)", s[0], s[1])), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type LeExpr BinOpExpr
 
-func (e LeExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e LeExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) <= (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("(%s) <= (%s)", s[0], s[1])), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type LikeExpr BinOpExpr
 
-func (e LikeExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e LikeExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) LIKE (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("%s LIKE %s", textSQL(d, s[0]), textSQL(d, s[1]))), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type IsNullExpr UnaryOpExpr
 
-func (e IsNullExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := UnaryOpExpr(e).BuildSQL(); err != nil {
+func (e IsNullExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := UnaryOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) IS NULL ", s), p, nil
+		return jsonbSQL(d, fmt.Sprintf("(%s) IS NULL", s)), p, nil
 	}
 }
 
 type AndExpr BinOpExpr
 
-func (e AndExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e AndExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) AND (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("%s AND %s", booleanSQL(d, s[0]), booleanSQL(d, s[1]))), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type OrExpr BinOpExpr
 
-func (e OrExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e OrExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) OR (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("%s OR %s", booleanSQL(d, s[0]), booleanSQL(d, s[1]))), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type NotExpr UnaryOpExpr
 
-func (e NotExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := UnaryOpExpr(e).BuildSQL(); err != nil {
+func (e NotExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := UnaryOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" NOT (%s) ", s), p, nil
+		return jsonbSQL(d, "NOT "+booleanSQL(d, s)), p, nil
 	}
 }
 
 type AddExpr BinOpExpr
 
-func (e AddExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e AddExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) + (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("%s + %s", numericSQL(d, s[0]), numericSQL(d, s[1]))), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type SubExpr BinOpExpr
 
-func (e SubExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e SubExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) - (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("%s - %s", numericSQL(d, s[0]), numericSQL(d, s[1]))), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type MulExpr BinOpExpr
 
-func (e MulExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e MulExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" (%s) * (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		return jsonbSQL(d, fmt.Sprintf("%s * %s", numericSQL(d, s[0]), numericSQL(d, s[1]))), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type DivExpr BinOpExpr
 
-func (e DivExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e DivExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
+		switch d {
+		case DialectSQLite:
+			// SQLite truncates the quotient of integers
+			return fmt.Sprintf(" CAST((%s) AS REAL) / (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		case DialectPostgres:
+			// Division by zero is null as in MySQL
+			return jsonbSQL(d, fmt.Sprintf("%s / NULLIF(%s, 0)", numericSQL(d, s[0]), numericSQL(d, s[1]))), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		}
 		return fmt.Sprintf(" (%s) / (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type ModExpr BinOpExpr
 
-func (e ModExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := BinOpExpr(e).BuildSQL(); err != nil {
+func (e ModExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := BinOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
+		if d == DialectPostgres {
+			return jsonbSQL(d, fmt.Sprintf("%s %% NULLIF(%s, 0)", numericSQL(d, s[0]), numericSQL(d, s[1]))), append(append([]interface{}{}, p[0]...), p[1]...), nil
+		}
 		return fmt.Sprintf(" (%s) %% (%s) ", s[0], s[1]), append(append([]interface{}{}, p[0]...), p[1]...), nil
 	}
 }
 
 type NegExpr UnaryOpExpr
 
-func (e NegExpr) BuildSQL() (string, []interface{}, error) {
-	if s, p, err := UnaryOpExpr(e).BuildSQL(); err != nil {
+func (e NegExpr) BuildSQL(d Dialect) (string, []interface{}, error) {
+	if s, p, err := UnaryOpExpr(e).BuildSQL(d); err != nil {
 		return "", nil, err
 	} else {
-		return fmt.Sprintf(" - (%s) ", s), p, nil
+		return jsonbSQL(d, "- "+numericSQL(d, s)), p, nil
 	}
 }
 
@@ -613,8 +872,15 @@ type SelectColumn struct {
 	As     string
 }
 
-func (c SelectColumn) BuildSQL() (string, []interface{}, error) {
-	sql, params, err := c.Column.BuildSQL()
+func (c SelectColumn) BuildSQL(d Dialect) (string, []interface{}, error) {
+	var sql string
+	var params []interface{}
+	var err error
+	if j, ok := c.Column.(jsonSQLBuilder); ok {
+		sql, params, err = j.BuildJSONSQL(d)
+	} else {
+		sql, params, err = c.Column.BuildSQL(d)
+	}
 	if err != nil {
 		return "", nil, xerrors.Errorf("Failed to build column sql: %w", err)
 	}
@@ -641,8 +907,8 @@ type SortKey struct {
 	Order SortKeyOrder
 }
 
-func (k SortKey) BuildSQL() (string, []interface{}, error) {
-	sql, params, err := k.Key.BuildSQL()
+func (k SortKey) BuildSQL(d Dialect) (string, []interface{}, error) {
+	sql, params, err := k.Key.BuildSQL(d)
 	if err != nil {
 		return "", nil, xerrors.Errorf("Failed to build sort key sql: %w", err)
 	}
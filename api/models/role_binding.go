@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// RoleBinding grants a role to an api key on the organization (ResourceID is nil) or on a folder or table.
+type RoleBinding struct {
+	ID             UUID
+	OrganizationID UUID
+	APIKeyID       UUID
+	ResourceID     *UUID
+	Role           string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type GetRoleBindingListOpts struct {
+	OrganizationID UUID
+	APIKeyID       *UUID
+	ResourceID     *UUID
+	Sort           []GetListSortKey
+	Offset, Limit  int
+}
+
+func GetRoleBindingList(db *gorm.DB, opts *GetRoleBindingListOpts) ([]RoleBinding, int64, error) {
+	order, err := convertGetListSortKeyToOrderString(DialectOf(db), opts.Sort, []string{"id", "role", "created_at", "updated_at"})
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to convert sort key: %w", err)
+	}
+
+	q := db.Model(&RoleBinding{}).Where("organization_id = ?", opts.OrganizationID)
+	if opts.APIKeyID != nil {
+		q = q.Where("api_key_id = ?", *opts.APIKeyID)
+	}
+	if opts.ResourceID != nil {
+		if *opts.ResourceID == UUID(uuid.Nil) {
+			q = q.Where("resource_id IS NULL")
+		} else {
+			q = q.Where("resource_id = ?", *opts.ResourceID)
+		}
+	}
+
+	var bindings []RoleBinding
+	var totalCount int64
+	err = q.Count(&totalCount).
+		Order(order).Offset(opts.Offset).Limit(opts.Limit).Find(&bindings).
+		Error
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to get models: %w", err)
+	}
+	return bindings, totalCount, nil
+}
+
+// GetAPIKeyRoleBindings returns all role bindings of the api key.
+func GetAPIKeyRoleBindings(db *gorm.DB, apiKeyID UUID) ([]RoleBinding, error) {
+	var bindings []RoleBinding
+	err := db.Where("api_key_id = ?", apiKeyID).Find(&bindings).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get models: %w", err)
+	}
+	return bindings, nil
+}
+
+// FindRoleBinding returns the binding of the api key on the resource.
+func FindRoleBinding(db *gorm.DB, apiKeyID UUID, resourceID *UUID) (*RoleBinding, error) {
+	q := db.Where("api_key_id = ?", apiKeyID)
+	if resourceID == nil || *resourceID == UUID(uuid.Nil) {
+		q = q.Where("resource_id IS NULL")
+	} else {
+		q = q.Where("resource_id = ?", *resourceID)
+	}
+
+	var binding RoleBinding
+	err := q.First(&binding).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get model: %w", err)
+	}
+	return &binding, nil
+}
+
+func (b *RoleBinding) BeforeSave(*gorm.DB) error {
+	if b.ResourceID != nil && *b.ResourceID == UUID(uuid.Nil) {
+		b.ResourceID = nil
+	}
+	return nil
+}
+
+func (b *RoleBinding) Create(db *gorm.DB) error {
+	if b.ID == UUID(uuid.Nil) {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return xerrors.Errorf("Failed to generate id: %w", err)
+		}
+		b.ID = UUID(id)
+	}
+
+	err := db.Create(b).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to create model: %w", err)
+	}
+	return nil
+}
+
+func (b *RoleBinding) Save(db *gorm.DB) error {
+	if b.ID == UUID(uuid.Nil) {
+		return fmt.Errorf("Empty id")
+	}
+	err := db.Save(b).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to save model: %w", err)
+	}
+	return nil
+}
+
+func (b *RoleBinding) Get(db *gorm.DB) (*RoleBinding, error) {
+	err := db.Where("id = ?", b.ID).First(b).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get model: %w", err)
+	}
+	return b, nil
+}
+
+func (b *RoleBinding) Delete(db *gorm.DB) error {
+	err := db.Where("id = ?", b.ID).Delete(b).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to delete model: %w", err)
+	}
+	return nil
+}
@@ -18,7 +18,7 @@ type GetListSortKey struct {
 
 var categoricalValuePattern = regexp.MustCompile(`^\w+$`)
 
-func convertGetListSortKeyToOrderString(sortKeys []GetListSortKey, sortableKeys []string) (string, error) {
+func convertGetListSortKeyToOrderString(d Dialect, sortKeys []GetListSortKey, sortableKeys []string) (string, error) {
 	if len(sortKeys) == 0 {
 		return "id ASC", nil
 	}
@@ -26,12 +26,19 @@ func convertGetListSortKeyToOrderString(sortKeys []GetListSortKey, sortableKeys
 	var orders []string
 	for _, s := range sortKeys {
 		var key string
+		valueFormat := "'%s'"
 		if strings.HasPrefix(s.Key, "property.") {
 			prop := s.Key[len("property."):]
 			if !PropertiesKeyPattern.MatchString(prop) {
 				return "", fmt.Errorf("Invalid sort key: %s", s.Key)
 			}
-			key = fmt.Sprintf("JSON_EXTRACT(properties, '$.%s')", prop)
+			if d == DialectPostgres {
+				// Compare with the jsonb string
+				key = fmt.Sprintf("(properties -> '%s')", prop)
+				valueFormat = `'"%s"'`
+			} else {
+				key = fmt.Sprintf("JSON_EXTRACT(properties, '$.%s')", prop)
+			}
 		} else {
 			key = utilstrings.ToSnakeCase(s.Key)
 			if !arrays.StringSliceContains(sortableKeys, key) {
@@ -45,7 +52,7 @@ func convertGetListSortKeyToOrderString(sortKeys []GetListSortKey, sortableKeys
 				if !categoricalValuePattern.MatchString(v) {
 					return "", fmt.Errorf("Invalid sort option (value list)")
 				}
-				cases += fmt.Sprintf(" WHEN %s = '%s' THEN %d ", key, v, i)
+				cases += fmt.Sprintf(" WHEN %s = %s THEN %d ", key, fmt.Sprintf(valueFormat, v), i)
 			}
 			orders = append(orders, fmt.Sprintf("CASE %s ELSE %d END ASC", cases, len(s.OrderValues)))
 			continue
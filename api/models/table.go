@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -68,10 +69,161 @@ func (t *Table) Delete(db *gorm.DB) error {
 
 func (t *Table) FetchColumns(db *gorm.DB) error {
 	var columns []Column
-	err := db.Where("table_id = ?", t.ID).Order("`index`").Find(&columns).Error
+	err := db.Where("table_id = ?", t.ID).Order(quote(db, "index")).Find(&columns).Error
 	if err != nil {
 		return xerrors.Errorf("Failed to get columns: %w", err)
 	}
 	t.Columns = columns
 	return nil
 }
+
+// FetchRecordValues returns column values of the records matching where (all records if nil).
+func (t *Table) FetchRecordValues(db *gorm.DB, where SQLBuilder) ([][]interface{}, error) {
+	if len(t.Columns) == 0 {
+		return nil, nil
+	}
+
+	q := SelectQuery{
+		From:  TableExpr{Table: *t},
+		Where: where,
+		OrderBy: []SortKey{
+			{Key: MetadataExpr{Key: MetadataExprKeyCreatedAt}, Order: SortKeyOrderAsc},
+			{Key: MetadataExpr{Key: MetadataExprKeyID}, Order: SortKeyOrderAsc},
+		},
+	}
+	for i, c := range t.Columns {
+		q.Columns = append(q.Columns, SelectColumn{
+			Column: ColumnExpr{Column: c},
+			As:     fmt.Sprintf("_%d", i),
+		})
+	}
+
+	var result []map[string]interface{}
+	if err := q.Execute(db, &result); err != nil {
+		return nil, xerrors.Errorf("Failed to select records: %w", err)
+	}
+
+	var records [][]interface{}
+	for _, row := range result {
+		record := make([]interface{}, len(t.Columns))
+		for i := range t.Columns {
+			record[i] = row[fmt.Sprintf("_%d", i)]
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+const duplicateRecordBatchSize = 500
+
+type DuplicateTableOpts struct {
+	ParentFolderID *UUID
+	Properties     Properties
+	IncludeRecords bool
+}
+
+func (t *Table) Duplicate(db *gorm.DB, opts *DuplicateTableOpts) (*Table, error) {
+	dup := &Table{
+		TableFilesystemEntry: TableFilesystemEntry{
+			OrganizationID: t.OrganizationID,
+			ParentFolderID: opts.ParentFolderID,
+			Properties:     make(Properties),
+		},
+	}
+	for k, v := range t.Properties {
+		dup.Properties[k] = v
+	}
+	for k, v := range opts.Properties {
+		dup.Properties[k] = v
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Create table
+		if err := dup.Create(tx); err != nil {
+			return xerrors.Errorf("Failed to create table: %w", err)
+		}
+
+		// Create columns
+		if err := t.FetchColumns(tx); err != nil {
+			return xerrors.Errorf("Failed to fetch columns: %w", err)
+		}
+		columnIDMap := make(map[string]string)
+		for _, c := range t.Columns {
+			col := &Column{
+				TableID:    dup.ID,
+				Index:      c.Index,
+				Type:       c.Type,
+				Properties: c.Properties,
+			}
+			if err := col.Create(tx, true); err != nil {
+				return xerrors.Errorf("Failed to create column: %w", err)
+			}
+			columnIDMap[c.ID.String()] = col.ID.String()
+		}
+
+		// Copy records
+		if opts.IncludeRecords {
+			if err := copyRecords(tx, t.ID, dup.ID, columnIDMap); err != nil {
+				return xerrors.Errorf("Failed to copy records: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dup, nil
+}
+
+func copyRecords(db *gorm.DB, srcTableID, dstTableID UUID, columnIDMap map[string]string) error {
+	var lastID *UUID
+	for {
+		q := db.Where("table_id = ?", srcTableID)
+		if lastID != nil {
+			q = q.Where("id > ?", *lastID)
+		}
+		var records []TableRecord
+		err := q.Order("id").Limit(duplicateRecordBatchSize).Find(&records).Error
+		if err != nil {
+			return xerrors.Errorf("Failed to get records: %w", err)
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		// Copy the id since the records get new ids below
+		last := records[len(records)-1].ID
+		lastID = &last
+
+		for i, r := range records {
+			var data map[string]interface{}
+			if err := json.Unmarshal(r.Data, &data); err != nil {
+				return xerrors.Errorf("Failed to deserialize data: %w", err)
+			}
+			newData := make(map[string]interface{})
+			for k, v := range data {
+				if newKey, ok := columnIDMap[k]; ok {
+					newData[newKey] = v
+				}
+			}
+			dataJSON, err := json.Marshal(newData)
+			if err != nil {
+				return xerrors.Errorf("Failed to serialize data: %w", err)
+			}
+
+			id, err := uuid.NewRandom()
+			if err != nil {
+				return xerrors.Errorf("Failed to generate uuid: %w", err)
+			}
+
+			records[i].ID = UUID(id)
+			records[i].TableID = dstTableID
+			records[i].Data = dataJSON
+		}
+		if err := db.Create(&records).Error; err != nil {
+			return xerrors.Errorf("Failed to insert records: %w", err)
+		}
+	}
+}
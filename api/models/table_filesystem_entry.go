@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,15 +42,50 @@ type TableFilesystemPathEntry struct {
 	Properties Properties
 }
 
+// visitedIDsSQL returns the expressions to keep the ids visited in the recursive query as `rec.all_ids`, to stop at a cycle:
+// the initial value, the value appending `e.id` and the condition that it contains `e.id`.
+func visitedIDsSQL(d Dialect) (initIDs, appendID, containsID string) {
+	switch d {
+	case DialectSQLite:
+		return "'/' || hex(id) || '/'", "rec.all_ids || hex(e.id) || '/'", "instr(rec.all_ids, '/' || hex(e.id) || '/') > 0"
+	case DialectPostgres:
+		return "ARRAY[id]", "rec.all_ids || e.id", "e.id = ANY(rec.all_ids)"
+	}
+	return "JSON_ARRAY(id)", "JSON_ARRAY_APPEND(rec.all_ids, '$', e.id)", "JSON_CONTAINS(rec.all_ids, CAST(e.id AS JSON), '$')"
+}
+
 func (e *TableFilesystemEntry) ComputePath(db *gorm.DB) error {
-	var entries []TableFilesystemPathEntry
+	entries := []TableFilesystemEntry{*e}
+	if err := ComputePaths(db, entries); err != nil {
+		return err
+	}
+	e.Path = entries[0].Path
+	return nil
+}
+
+// ComputePaths sets the paths of the entries in a single query.
+func ComputePaths(db *gorm.DB, entries []TableFilesystemEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ids := make([]UUID, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+
+	initIDs, appendID, containsID := visitedIDsSQL(DialectOf(db))
+
+	var rows []struct {
+		StartID UUID
+		TableFilesystemPathEntry
+	}
 	err := db.Raw(`
-	WITH recursive rec(id, organization_id, type, parent_folder_id, properties, depth, all_ids) AS (
-	    SELECT id, organization_id, type, parent_folder_id, properties, 0, JSON_ARRAY(id)
+	WITH recursive rec(start_id, id, organization_id, type, parent_folder_id, properties, depth, all_ids) AS (
+	    SELECT id, id, organization_id, type, parent_folder_id, properties, 0, `+initIDs+`
 	    FROM table_filesystem_entries
-	    WHERE id = ?
+	    WHERE id IN ?
 	    UNION ALL
-	    SELECT e.id, e.organization_id, e.type, e.parent_folder_id, e.properties, rec.depth - 1, JSON_ARRAY_APPEND(rec.all_ids, '$', e.id)
+	    SELECT rec.start_id, e.id, e.organization_id, e.type, e.parent_folder_id, e.properties, rec.depth - 1, `+appendID+`
 	    FROM rec
 	    INNER JOIN folders AS f
 	    ON f.id = rec.parent_folder_id
@@ -56,17 +93,125 @@ func (e *TableFilesystemEntry) ComputePath(db *gorm.DB) error {
 	    ON e.id = f.id AND
 	       e.organization_id = rec.organization_id
 	    WHERE rec.parent_folder_id IS NOT NULL AND
-	          NOT JSON_CONTAINS(rec.all_ids, CAST(e.id AS JSON), '$')
+	          NOT `+containsID+`
 	)
-	SELECT id, type, properties
+	SELECT start_id, id, type, properties
 	FROM rec
 	WHERE depth != 0
-	ORDER BY depth ASC
-	`, e.ID).Scan(&entries).Error
+	ORDER BY start_id ASC, depth ASC
+	`, ids).Scan(&rows).Error
 	if err != nil {
-		return xerrors.Errorf("Failed to get path: %w", err)
+		return xerrors.Errorf("Failed to get paths: %w", err)
 	}
 
-	e.Path = entries
+	paths := map[UUID][]TableFilesystemPathEntry{}
+	for _, row := range rows {
+		paths[row.StartID] = append(paths[row.StartID], row.TableFilesystemPathEntry)
+	}
+	for i := range entries {
+		entries[i].Path = paths[entries[i].ID]
+	}
 	return nil
 }
+
+// trimPath removes the ancestors above the outermost of the roots from the path of the entry,
+// which is a descendant of one of the roots or the root itself.
+func (e *TableFilesystemEntry) trimPath(rootIDs []UUID) {
+	roots := map[UUID]bool{}
+	for _, id := range rootIDs {
+		roots[id] = true
+	}
+	for i, a := range e.Path {
+		if roots[a.ID] {
+			e.Path = e.Path[i:]
+			return
+		}
+	}
+	e.Path = nil
+}
+
+type SearchTableFilesystemEntriesOpts struct {
+	// RootIDs limits the search to the entries and their descendants, and the paths to below them, unless nil
+	RootIDs          []UUID
+	Type             string
+	Property         string
+	Prefix, Contains string
+	Sort             []GetListSortKey
+	Offset, Limit    int
+}
+
+// escapeLike escapes the wildcards in the pattern of LIKE ... ESCAPE '!'.
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+// SearchTableFilesystemEntries returns the folders and tables of the organization whose property matches the options case-insensitively,
+// with their paths, which start at the outermost of RootIDs unless nil.
+func SearchTableFilesystemEntries(db *gorm.DB, organizationID UUID, opts *SearchTableFilesystemEntriesOpts) ([]TableFilesystemEntry, int64, error) {
+	d := DialectOf(db)
+
+	var cte string
+	var params []interface{}
+	if opts.RootIDs != nil {
+		cte = descendantEntriesOfAnyCTE
+		params = append(params, opts.RootIDs)
+	}
+	where := "organization_id = ?"
+	params = append(params, organizationID)
+	if opts.RootIDs != nil {
+		where += " AND id IN (SELECT id FROM rec)"
+	}
+	if opts.Type != "" {
+		where += " AND type = ?"
+		params = append(params, opts.Type)
+	}
+	if opts.Prefix != "" || opts.Contains != "" {
+		if !PropertiesKeyPattern.MatchString(opts.Property) {
+			return nil, 0, fmt.Errorf("Invalid property key: %s", opts.Property)
+		}
+		text := "LOWER(" + jsonTextSQL(d, "properties", opts.Property) + ")"
+		if opts.Prefix != "" {
+			where += " AND " + text + " LIKE LOWER(?) ESCAPE '!'"
+			params = append(params, escapeLike(opts.Prefix)+"%")
+		}
+		if opts.Contains != "" {
+			where += " AND " + text + " LIKE LOWER(?) ESCAPE '!'"
+			params = append(params, "%"+escapeLike(opts.Contains)+"%")
+		}
+	}
+
+	order, err := convertGetListSortKeyToOrderString(d, opts.Sort, []string{"id", "type", "created_at", "updated_at"})
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to convert sort key: %w", err)
+	}
+
+	totalCount, err := countBy(db, cte+`
+	SELECT COUNT(*)
+	FROM table_filesystem_entries
+	WHERE `+where, params...)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to count entries: %w", err)
+	}
+
+	var entries []TableFilesystemEntry
+	err = db.Raw(cte+`
+	SELECT *
+	FROM table_filesystem_entries
+	WHERE `+where+`
+	ORDER BY `+order+`
+	LIMIT ? OFFSET ?
+	`, append(params, opts.Limit, opts.Offset)...).Scan(&entries).Error
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to search entries: %w", err)
+	}
+	if err := ComputePaths(db, entries); err != nil {
+		return nil, 0, xerrors.Errorf("Failed to get paths: %w", err)
+	}
+	if opts.RootIDs != nil {
+		for i := range entries {
+			entries[i].trimPath(opts.RootIDs)
+		}
+	}
+
+	return entries, totalCount, nil
+}
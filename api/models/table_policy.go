@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// TablePolicy restricts records accessible by api keys to those matching Where.
+type TablePolicy struct {
+	ID         UUID
+	TableID    UUID
+	Where      JSON
+	Properties Properties
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func GetTablePolicies(db *gorm.DB, tableID UUID) ([]TablePolicy, error) {
+	var policies []TablePolicy
+	err := db.Where("table_id = ?", tableID).Order("created_at, id").Find(&policies).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get models: %w", err)
+	}
+	return policies, nil
+}
+
+// CountTablePoliciesUnder counts policies of the tables in the folder and its descendants.
+// All tables of the organization are counted if folderID is nil.
+func CountTablePoliciesUnder(db *gorm.DB, organizationID UUID, folderID *UUID) (int64, error) {
+	var count int64
+	var err error
+	if folderID == nil || *folderID == UUID(uuid.Nil) {
+		err = db.Raw(`
+		SELECT COUNT(*)
+		FROM table_policies AS p
+		INNER JOIN table_filesystem_entries AS e
+		ON e.id = p.table_id
+		WHERE e.organization_id = ?
+		`, organizationID).Scan(&count).Error
+	} else {
+		err = db.Raw(descendantEntriesCTE+`
+		SELECT COUNT(*)
+		FROM table_policies
+		WHERE table_id IN (SELECT id FROM rec)
+		`, *folderID).Scan(&count).Error
+	}
+	if err != nil {
+		return 0, xerrors.Errorf("Failed to count policies: %w", err)
+	}
+	return count, nil
+}
+
+func (p *TablePolicy) Create(db *gorm.DB) error {
+	if p.ID == UUID(uuid.Nil) {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return xerrors.Errorf("Failed to generate id: %w", err)
+		}
+		p.ID = UUID(id)
+	}
+
+	err := db.Create(p).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to create model: %w", err)
+	}
+	return nil
+}
+
+func (p *TablePolicy) Save(db *gorm.DB) error {
+	if p.ID == UUID(uuid.Nil) {
+		return fmt.Errorf("Empty id")
+	}
+	err := db.Save(p).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to save model: %w", err)
+	}
+	return nil
+}
+
+func (p *TablePolicy) Get(db *gorm.DB) (*TablePolicy, error) {
+	err := db.Where("id = ?", p.ID).First(p).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get model: %w", err)
+	}
+	return p, nil
+}
+
+func (p *TablePolicy) Delete(db *gorm.DB) error {
+	err := db.Where("id = ?", p.ID).Delete(p).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to delete model: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// descendantEntriesCTE selects the entry of the given id and its descendants as `rec`.
+const descendantEntriesCTE = `
+WITH recursive rec(id) AS (
+    SELECT id
+    FROM table_filesystem_entries
+    WHERE id = ?
+    UNION ALL
+    SELECT e.id
+    FROM rec
+    INNER JOIN table_filesystem_entries AS e
+    ON e.parent_folder_id = rec.id
+)
+`
+
+// descendantEntriesOfAnyCTE is the same as descendantEntriesCTE except that it is given a list of ids.
+const descendantEntriesOfAnyCTE = `
+WITH recursive rec(id) AS (
+    SELECT id
+    FROM table_filesystem_entries
+    WHERE id IN ?
+    UNION ALL
+    SELECT e.id
+    FROM rec
+    INNER JOIN table_filesystem_entries AS e
+    ON e.parent_folder_id = rec.id
+)
+`
+
+func countBy(db *gorm.DB, sql string, params ...interface{}) (int64, error) {
+	var count *int64
+	if err := db.Raw(sql, params...).Scan(&count).Error; err != nil {
+		return 0, xerrors.Errorf("Failed to count: %w", err)
+	}
+	if count == nil {
+		return 0, nil
+	}
+	return *count, nil
+}
+
+func CountTables(db *gorm.DB, organizationID UUID) (int64, error) {
+	return countBy(db, `
+	SELECT COUNT(*)
+	FROM table_filesystem_entries
+	WHERE organization_id = ? AND type = 'table'
+	`, organizationID)
+}
+
+func CountColumns(db *gorm.DB, tableID UUID) (int64, error) {
+	return countBy(db, `
+	SELECT COUNT(*)
+	FROM columns
+	WHERE table_id = ?
+	`, tableID)
+}
+
+func CountRecords(db *gorm.DB, tableID UUID) (int64, error) {
+	return countBy(db, `
+	SELECT COUNT(*)
+	FROM table_records
+	WHERE table_id = ?
+	`, tableID)
+}
+
+// jsonLengthSQL returns the expression of the size of the json column.
+func jsonLengthSQL(d Dialect, column string) string {
+	if d == DialectPostgres {
+		return fmt.Sprintf("OCTET_LENGTH(CAST(%s AS text))", column)
+	}
+	return fmt.Sprintf("LENGTH(%s)", column)
+}
+
+// GetStorageBytes returns the total size of records of the organization.
+func GetStorageBytes(db *gorm.DB, organizationID UUID) (int64, error) {
+	d := DialectOf(db)
+	return countBy(db, `
+	SELECT SUM(`+jsonLengthSQL(d, "r.data")+` + `+jsonLengthSQL(d, "r.properties")+`)
+	FROM table_records AS r
+	INNER JOIN table_filesystem_entries AS e
+	ON e.id = r.table_id
+	WHERE e.organization_id = ?
+	`, organizationID)
+}
+
+// GetStorageBytesUnder returns the total size of records of the tables in the folder and its descendants.
+func GetStorageBytesUnder(db *gorm.DB, entryID UUID) (int64, error) {
+	d := DialectOf(db)
+	return countBy(db, descendantEntriesCTE+`
+	SELECT SUM(`+jsonLengthSQL(d, "data")+` + `+jsonLengthSQL(d, "properties")+`)
+	FROM table_records
+	WHERE table_id IN (SELECT id FROM rec)
+	`, entryID)
+}
+
+// GetRecordsBytes returns the total size of the records of the given ids.
+func GetRecordsBytes(db *gorm.DB, tableID UUID, ids []UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	d := DialectOf(db)
+	return countBy(db, `
+	SELECT SUM(`+jsonLengthSQL(d, "data")+` + `+jsonLengthSQL(d, "properties")+`)
+	FROM table_records
+	WHERE table_id = ? AND id IN ?
+	`, tableID, ids)
+}
@@ -0,0 +1,327 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+type StringList []string
+
+func (l *StringList) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("Invalid type: %v (%T)", value, value)
+	}
+
+	var result []string
+	err := json.Unmarshal(bytes, &result)
+	*l = StringList(result)
+	return err
+}
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		l = []string{}
+	}
+	b, err := json.Marshal(&l)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+type Webhook struct {
+	ID             UUID
+	OrganizationID UUID
+	TableID        *UUID
+	URL            string
+	Secret         string
+	Events         StringList
+	Properties     Properties
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type GetWebhookListOpts struct {
+	OrganizationID UUID
+	TableID        *UUID
+	Sort           []GetListSortKey
+	Offset, Limit  int
+}
+
+func GetWebhookList(db *gorm.DB, opts *GetWebhookListOpts) ([]Webhook, int64, error) {
+	order, err := convertGetListSortKeyToOrderString(DialectOf(db), opts.Sort, []string{"id", "created_at", "updated_at"})
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to convert sort key: %w", err)
+	}
+
+	q := db.Model(&Webhook{}).Where("organization_id = ?", opts.OrganizationID)
+	if opts.TableID != nil {
+		q = q.Where("table_id = ?", *opts.TableID)
+	}
+
+	var webhooks []Webhook
+	var totalCount int64
+	err = q.Count(&totalCount).
+		Order(order).Offset(opts.Offset).Limit(opts.Limit).Find(&webhooks).
+		Error
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to get models: %w", err)
+	}
+	return webhooks, totalCount, nil
+}
+
+// GetSubscribedWebhooks returns webhooks which subscribe the event of the organization (and the table).
+func GetSubscribedWebhooks(db *gorm.DB, organizationID UUID, tableID *UUID, eventType string) ([]Webhook, error) {
+	q := db.Where("organization_id = ?", organizationID)
+	if tableID != nil {
+		q = q.Where("table_id IS NULL OR table_id = ?", *tableID)
+	} else {
+		q = q.Where("table_id IS NULL")
+	}
+
+	var webhooks []Webhook
+	if err := q.Order("created_at, id").Find(&webhooks).Error; err != nil {
+		return nil, xerrors.Errorf("Failed to get models: %w", err)
+	}
+
+	var subscribed []Webhook
+	for _, w := range webhooks {
+		if w.Subscribes(eventType) {
+			subscribed = append(subscribed, w)
+		}
+	}
+	return subscribed, nil
+}
+
+// Subscribes reports whether the webhook subscribes the event type.
+// Events like "record.*" match all event types with the prefix and empty events match all.
+func (w *Webhook) Subscribes(eventType string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType || e == "*" {
+			return true
+		}
+		if strings.HasSuffix(e, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(e, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Webhook) Create(db *gorm.DB) error {
+	if w.ID == UUID(uuid.Nil) {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return xerrors.Errorf("Failed to generate id: %w", err)
+		}
+		w.ID = UUID(id)
+	}
+
+	err := db.Create(w).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to create model: %w", err)
+	}
+	return nil
+}
+
+func (w *Webhook) Save(db *gorm.DB) error {
+	if w.ID == UUID(uuid.Nil) {
+		return fmt.Errorf("Empty id")
+	}
+	err := db.Save(w).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to save model: %w", err)
+	}
+	return nil
+}
+
+func (w *Webhook) Get(db *gorm.DB) (*Webhook, error) {
+	err := db.Where("id = ?", w.ID).First(w).Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get model: %w", err)
+	}
+	return w, nil
+}
+
+func (w *Webhook) Delete(db *gorm.DB) error {
+	err := db.Where("id = ?", w.ID).Delete(w).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to delete model: %w", err)
+	}
+	return nil
+}
+
+const (
+	WebhookDeliveryStatusPending   = "pending"
+	WebhookDeliveryStatusSucceeded = "succeeded"
+	WebhookDeliveryStatusFailed    = "failed"
+)
+
+type WebhookDelivery struct {
+	ID             UUID
+	WebhookID      UUID
+	EventID        UUID
+	EventType      string
+	Payload        JSON
+	Status         string
+	Owner          *string
+	LeaseExpiresAt *time.Time
+	Attempts       int
+	ResponseStatus *int
+	Error          *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+type GetWebhookDeliveryListOpts struct {
+	Offset, Limit int
+}
+
+func (w *Webhook) GetDeliveries(db *gorm.DB, opts *GetWebhookDeliveryListOpts) ([]WebhookDelivery, int64, error) {
+	var deliveries []WebhookDelivery
+	var totalCount int64
+	err := db.Model(&WebhookDelivery{}).Where("webhook_id = ?", w.ID).
+		Count(&totalCount).
+		Order("created_at DESC, id").Offset(opts.Offset).Limit(opts.Limit).Find(&deliveries).
+		Error
+	if err != nil {
+		return nil, 0, xerrors.Errorf("Failed to get models: %w", err)
+	}
+	return deliveries, totalCount, nil
+}
+
+// GetClaimableWebhookDeliveries returns pending deliveries whose lease has expired or been released.
+func GetClaimableWebhookDeliveries(db *gorm.DB, now time.Time) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := db.Where(claimableWebhookDeliveryCondition, WebhookDeliveryStatusPending, now).
+		Order("created_at").
+		Find(&deliveries).
+		Error
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get models: %w", err)
+	}
+	return deliveries, nil
+}
+
+const claimableWebhookDeliveryCondition = "status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)"
+
+// Claim atomically takes the delivery for the owner until leaseExpiresAt. It returns
+// false if the delivery has been taken by another owner or has completed.
+func (d *WebhookDelivery) Claim(db *gorm.DB, owner string, now, leaseExpiresAt time.Time) (bool, error) {
+	result := db.Model(&WebhookDelivery{}).
+		Where("id = ?", d.ID).
+		Where(claimableWebhookDeliveryCondition, WebhookDeliveryStatusPending, now).
+		Updates(map[string]interface{}{
+			"owner":            owner,
+			"lease_expires_at": leaseExpiresAt,
+		})
+	if result.Error != nil {
+		return false, xerrors.Errorf("Failed to claim delivery: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	err := db.Where("id = ?", d.ID).First(d).Error
+	if err != nil {
+		return false, xerrors.Errorf("Failed to get delivery: %w", err)
+	}
+	return true, nil
+}
+
+// RenewLease extends the lease held by the owner. It returns false if the lease has been lost.
+func (d *WebhookDelivery) RenewLease(db *gorm.DB, owner string, leaseExpiresAt time.Time) (bool, error) {
+	result := db.Model(&WebhookDelivery{}).
+		Where("id = ? AND status = ? AND owner = ?", d.ID, WebhookDeliveryStatusPending, owner).
+		Update("lease_expires_at", leaseExpiresAt)
+	if result.Error != nil {
+		return false, xerrors.Errorf("Failed to renew lease: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	d.LeaseExpiresAt = &leaseExpiresAt
+	return true, nil
+}
+
+// Release gives up the lease held by the owner so that the delivery can be claimed at once.
+func (d *WebhookDelivery) Release(db *gorm.DB, owner string) error {
+	err := db.Model(&WebhookDelivery{}).
+		Where("id = ? AND status = ? AND owner = ?", d.ID, WebhookDeliveryStatusPending, owner).
+		Update("lease_expires_at", nil).
+		Error
+	if err != nil {
+		return xerrors.Errorf("Failed to release lease: %w", err)
+	}
+	d.LeaseExpiresAt = nil
+	return nil
+}
+
+// SaveAttempt saves the outcome of the last attempt if the owner still holds the lease,
+// which is extended to leaseExpiresAt, or cleared if nil.
+func (d *WebhookDelivery) SaveAttempt(db *gorm.DB, owner string, leaseExpiresAt *time.Time) (bool, error) {
+	result := db.Model(&WebhookDelivery{}).
+		Where("id = ? AND owner = ?", d.ID, owner).
+		Updates(map[string]interface{}{
+			"status":           d.Status,
+			"attempts":         d.Attempts,
+			"response_status":  d.ResponseStatus,
+			"error":            d.Error,
+			"delivered_at":     d.DeliveredAt,
+			"lease_expires_at": leaseExpiresAt,
+		})
+	if result.Error != nil {
+		return false, xerrors.Errorf("Failed to save attempt: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	d.LeaseExpiresAt = leaseExpiresAt
+	return true, nil
+}
+
+func (d *WebhookDelivery) Create(db *gorm.DB) error {
+	if d.ID == UUID(uuid.Nil) {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return xerrors.Errorf("Failed to generate id: %w", err)
+		}
+		d.ID = UUID(id)
+	}
+	if d.Status == "" {
+		d.Status = WebhookDeliveryStatusPending
+	}
+
+	err := db.Create(d).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to create model: %w", err)
+	}
+	return nil
+}
+
+func (d *WebhookDelivery) Save(db *gorm.DB) error {
+	if d.ID == UUID(uuid.Nil) {
+		return fmt.Errorf("Empty id")
+	}
+	err := db.Save(d).Error
+	if err != nil {
+		return xerrors.Errorf("Failed to save model: %w", err)
+	}
+	return nil
+}
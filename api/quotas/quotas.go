@@ -0,0 +1,222 @@
+package quotas
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/utils/responses"
+)
+
+// Config is the limits of resources of each organization. Zero means unlimited.
+type Config struct {
+	MaxTables          int64
+	MaxColumnsPerTable int64
+	MaxRecordsPerTable int64
+	// MaxStorageBytes is checked with a counter in each process, which is exact only near the limit.
+	// With multiple processes, the usage may exceed it by the records written by the others since the counter was
+	// loaded, up to storageResyncInterval ago.
+	MaxStorageBytes int64
+}
+
+type ExceededError struct {
+	Resource string
+	Limit    int64
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("Quota exceeded: %s (limit=%d)", e.Resource, e.Limit)
+}
+
+// storageResyncInterval is the interval to reload the storage usage of an organization from the database.
+// It bounds the drift of the counter by changes which are not reported with AddStorage (e.g. deleting tables).
+const storageResyncInterval = time.Minute
+
+// storageExactCheckPercent is the percentage of the storage limit above which the usage is computed exactly,
+// since the counter does not include the changes made by other processes.
+const storageExactCheckPercent = 90
+
+// Checker checks usages against the quotas. A nil checker allows everything.
+//
+// Each check returns *ExceededError if the current usage plus adding exceeds the limit.
+// Checks after creating resources in a transaction are done with zero adding,
+// except for storage whose usage is a counter per process updated after commit.
+type Checker struct {
+	Config Config
+
+	mu      sync.Mutex
+	storage map[models.UUID]*storageUsage
+}
+
+type storageUsage struct {
+	bytes    int64
+	loadedAt time.Time
+}
+
+func NewChecker(conf *Config) *Checker {
+	if conf == nil {
+		return nil
+	}
+	return &Checker{
+		Config:  *conf,
+		storage: make(map[models.UUID]*storageUsage),
+	}
+}
+
+func check(resource string, limit, adding int64, count func() (int64, error)) error {
+	if limit <= 0 {
+		return nil
+	}
+	current, err := count()
+	if err != nil {
+		return xerrors.Errorf("Failed to get usage of %s: %w", resource, err)
+	}
+	if current+adding > limit {
+		return &ExceededError{Resource: resource, Limit: limit}
+	}
+	return nil
+}
+
+func (c *Checker) CheckTables(db *gorm.DB, organizationID models.UUID, adding int64) error {
+	if c == nil {
+		return nil
+	}
+	return check("tables", c.Config.MaxTables, adding, func() (int64, error) {
+		return models.CountTables(db, organizationID)
+	})
+}
+
+func (c *Checker) CheckColumns(db *gorm.DB, tableID models.UUID, adding int64) error {
+	if c == nil {
+		return nil
+	}
+	return check("columns per table", c.Config.MaxColumnsPerTable, adding, func() (int64, error) {
+		return models.CountColumns(db, tableID)
+	})
+}
+
+func (c *Checker) CheckRecords(db *gorm.DB, tableID models.UUID, adding int64) error {
+	if c == nil {
+		return nil
+	}
+	return check("records per table", c.Config.MaxRecordsPerTable, adding, func() (int64, error) {
+		return models.CountRecords(db, tableID)
+	})
+}
+
+// CheckStorage checks the storage usage after the records of adding bytes are written with db in a transaction.
+// If the counter gets close to the limit, the usage is computed with db instead, which includes the records written
+// by other processes and the ones of the transaction.
+func (c *Checker) CheckStorage(db *gorm.DB, organizationID models.UUID, adding int64) error {
+	if c == nil || c.Config.MaxStorageBytes <= 0 {
+		return nil
+	}
+	limit := c.Config.MaxStorageBytes
+	current, err := c.storageBytes(db, organizationID)
+	if err != nil {
+		return xerrors.Errorf("Failed to get usage of storage bytes: %w", err)
+	}
+	if (current+adding)*100 <= limit*storageExactCheckPercent {
+		return nil
+	}
+	return check("storage bytes", limit, 0, func() (int64, error) {
+		return models.GetStorageBytes(db, organizationID)
+	})
+}
+
+// storageBytes returns the counter of the storage usage, loading it when it is missing or old.
+func (c *Checker) storageBytes(db *gorm.DB, organizationID models.UUID) (int64, error) {
+	c.mu.Lock()
+	usage, ok := c.storage[organizationID]
+	if ok && time.Since(usage.loadedAt) < storageResyncInterval {
+		bytes := usage.bytes
+		c.mu.Unlock()
+		return bytes, nil
+	}
+	c.mu.Unlock()
+
+	bytes, err := models.GetStorageBytes(db, organizationID)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.storage[organizationID] = &storageUsage{bytes: bytes, loadedAt: time.Now()}
+	c.mu.Unlock()
+	return bytes, nil
+}
+
+// LoadStorage loads the storage usage if it is missing or old.
+// Call it before writing records in a transaction, since the usage loaded in the transaction includes uncommitted records.
+func (c *Checker) LoadStorage(db *gorm.DB, organizationID models.UUID) error {
+	if c == nil || c.Config.MaxStorageBytes <= 0 {
+		return nil
+	}
+	if _, err := c.storageBytes(db, organizationID); err != nil {
+		return xerrors.Errorf("Failed to get usage of storage bytes: %w", err)
+	}
+	return nil
+}
+
+// RecordsBytes returns the size of the records to check and count the storage usage, or 0 if storage is unlimited.
+func (c *Checker) RecordsBytes(db *gorm.DB, tableID models.UUID, ids []models.UUID) (int64, error) {
+	if c == nil || c.Config.MaxStorageBytes <= 0 {
+		return 0, nil
+	}
+	size, err := models.GetRecordsBytes(db, tableID, ids)
+	if err != nil {
+		return 0, xerrors.Errorf("Failed to get records bytes: %w", err)
+	}
+	return size, nil
+}
+
+// AddStorage adds delta to the counter of the storage usage. It must be called after the change is committed.
+func (c *Checker) AddStorage(organizationID models.UUID, delta int64) {
+	if c == nil || delta == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if usage, ok := c.storage[organizationID]; ok {
+		usage.bytes += delta
+	}
+}
+
+// CheckCopy checks quotas after copying the folder or the table into dup in a transaction,
+// and returns the size of the copied records, which is added to the storage usage after commit.
+func (c *Checker) CheckCopy(db *gorm.DB, dup *models.TableFilesystemEntry, includeRecords bool) (int64, error) {
+	if c == nil {
+		return 0, nil
+	}
+	if err := c.CheckTables(db, dup.OrganizationID, 0); err != nil {
+		return 0, err
+	}
+	if !includeRecords || c.Config.MaxStorageBytes <= 0 {
+		return 0, nil
+	}
+	size, err := models.GetStorageBytesUnder(db, dup.ID)
+	if err != nil {
+		return 0, xerrors.Errorf("Failed to get storage bytes: %w", err)
+	}
+	return size, c.CheckStorage(db, dup.OrganizationID, size)
+}
+
+func IsExceeded(err error) bool {
+	var exceeded *ExceededError
+	return xerrors.As(err, &exceeded)
+}
+
+// SendError sends the error response for the error returned by Check functions.
+func SendError(w http.ResponseWriter, r *http.Request, err error) {
+	var exceeded *ExceededError
+	if xerrors.As(err, &exceeded) {
+		responses.SendErrorResponse(w, r, http.StatusForbidden, exceeded.Error(), nil)
+		return
+	}
+	responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to check quota", err)
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/utils/responses"
+)
+
+const pruneInterval = time.Minute
+
+type Config struct {
+	// OrganizationRate is the number of requests per second allowed for each organization. Unlimited if zero.
+	OrganizationRate  float64
+	OrganizationBurst int
+	// KeyRate is the number of requests per second allowed for each api key or JWT subject. Unlimited if zero.
+	KeyRate  float64
+	KeyBurst int
+}
+
+// limiterSet holds token buckets keyed by clients.
+type limiterSet struct {
+	limit     rate.Limit
+	burst     int
+	mutex     sync.Mutex
+	entries   map[string]*entry
+	prunedAt  time.Time
+	idleAfter time.Duration
+}
+
+type entry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newLimiterSet(r float64, burst int) *limiterSet {
+	if r <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = int(math.Ceil(r))
+	}
+	return &limiterSet{
+		limit:   rate.Limit(r),
+		burst:   burst,
+		entries: make(map[string]*entry),
+		// The bucket is full again after this, same as a new one
+		idleAfter: time.Duration(float64(burst) / r * float64(time.Second)),
+	}
+}
+
+func (s *limiterSet) reserve(key string, now time.Time) *rate.Reservation {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if now.Sub(s.prunedAt) > pruneInterval {
+		for k, e := range s.entries {
+			if now.Sub(e.lastSeen) > s.idleAfter {
+				delete(s.entries, k)
+			}
+		}
+		s.prunedAt = now
+	}
+
+	e, exists := s.entries[key]
+	if !exists {
+		e = &entry{limiter: rate.NewLimiter(s.limit, s.burst)}
+		s.entries[key] = e
+	}
+	e.lastSeen = now
+	return e.limiter.ReserveN(now, 1)
+}
+
+// Middleware limits request rates of authenticated clients. Administrators are not limited.
+// It must be used after auth.Middleware.
+func Middleware(conf *Config) func(http.Handler) http.Handler {
+	var organizations, keys *limiterSet
+	if conf != nil {
+		organizations = newLimiterSet(conf.OrganizationRate, conf.OrganizationBurst)
+		keys = newLimiterSet(conf.KeyRate, conf.KeyBurst)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p := auth.FromContext(r.Context())
+			if p == nil || p.Admin {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			var reservations []*rate.Reservation
+			if keys != nil {
+				if key := p.ClientID(); key != "" {
+					reservations = append(reservations, keys.reserve(key, now))
+				}
+			}
+			if organizations != nil && p.OrganizationID != nil {
+				reservations = append(reservations, organizations.reserve(p.OrganizationID.String(), now))
+			}
+
+			var delay time.Duration
+			for _, rsv := range reservations {
+				if !rsv.OK() {
+					delay = time.Second
+				} else if d := rsv.DelayFrom(now); d > delay {
+					delay = d
+				}
+			}
+			if delay > 0 {
+				for _, rsv := range reservations {
+					rsv.CancelAt(now)
+				}
+				w.Header().Set("Retry-After", fmt.Sprintf("%d", int(math.Ceil(delay.Seconds()))))
+				responses.SendErrorResponse(w, r, http.StatusTooManyRequests, "Rate limit exceeded", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
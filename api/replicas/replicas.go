@@ -0,0 +1,30 @@
+package replicas
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tsujio/x-base/databases"
+)
+
+// HeaderReadYourWrites set to true makes the request read from the primary, to see the writes just made by the client.
+const HeaderReadYourWrites = "X-Read-Your-Writes"
+
+// Middleware sends the queries of GET requests to the read replicas, unless HeaderReadYourWrites is set.
+// It must be used after the authentication so that api keys are looked up on the primary.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v, _ := strconv.ParseBool(r.Header.Get(HeaderReadYourWrites)); v {
+			r = r.WithContext(databases.ReadFromPrimary(r.Context()))
+		} else if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			r = r.WithContext(databases.ReadFromReplica(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ReadFromReplica returns the request whose queries are sent to the read replicas even if it is not GET,
+// e.g. to run select queries, unless HeaderReadYourWrites is set. Queries in transactions go to the primary.
+func ReadFromReplica(r *http.Request) *http.Request {
+	return r.WithContext(databases.ReadFromReplica(r.Context()))
+}
@@ -6,12 +6,20 @@ import (
 	"github.com/gorilla/mux"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/changes"
 	"github.com/tsujio/x-base/api/controllers/folder"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/jobs"
+	"github.com/tsujio/x-base/api/quotas"
 )
 
-func SetFolderRoutes(router *mux.Router, db *gorm.DB) {
+func SetFolderRoutes(router *mux.Router, db *gorm.DB, runner *jobs.Runner, bus *events.Bus, recorder *changes.Recorder, checker *quotas.Checker) {
 	controller := folder.FolderController{
-		DB: db,
+		DB:      db,
+		Runner:  runner,
+		Bus:     bus,
+		Changes: recorder,
+		Quotas:  checker,
 	}
 
 	router.HandleFunc("", controller.CreateFolder).Methods(http.MethodPost)
@@ -19,4 +27,8 @@ func SetFolderRoutes(router *mux.Router, db *gorm.DB) {
 	router.HandleFunc("/{folderID}", controller.UpdateFolder).Methods(http.MethodPatch)
 	router.HandleFunc("/{folderID}", controller.DeleteFolder).Methods(http.MethodDelete)
 	router.HandleFunc("/{folderID}/children", controller.GetFolderChildren).Methods(http.MethodGet)
+	router.HandleFunc("/{folderID}/tree", controller.GetFolderTree).Methods(http.MethodGet)
+	router.HandleFunc("/{folderID}/copy", controller.CopyFolder).Methods(http.MethodPost)
+	router.HandleFunc("/{folderID}/xlsx", controller.ExportFolderXLSX).Methods(http.MethodGet)
+	router.HandleFunc("/{folderID}/events", controller.StreamFolderEvents).Methods(http.MethodGet)
 }
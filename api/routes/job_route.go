@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/controllers/job"
+)
+
+func SetJobRoutes(router *mux.Router, db *gorm.DB) {
+	controller := job.JobController{
+		DB: db,
+	}
+
+	router.HandleFunc("/{jobID}", controller.GetJob).Methods(http.MethodGet)
+}
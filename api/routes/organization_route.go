@@ -19,4 +19,12 @@ func SetOrganizationRoutes(router *mux.Router, db *gorm.DB) {
 	router.HandleFunc("/{organizationID}", controller.GetOrganization).Methods(http.MethodGet)
 	router.HandleFunc("/{organizationID}", controller.UpdateOrganization).Methods(http.MethodPatch)
 	router.HandleFunc("/{organizationID}", controller.DeleteOrganization).Methods(http.MethodDelete)
+	router.HandleFunc("/{organizationID}/search", controller.SearchTableFilesystemEntries).Methods(http.MethodGet)
+	router.HandleFunc("/{organizationID}/api-keys", controller.CreateAPIKey).Methods(http.MethodPost)
+	router.HandleFunc("/{organizationID}/api-keys", controller.GetAPIKeyList).Methods(http.MethodGet)
+	router.HandleFunc("/{organizationID}/api-keys/{apiKeyID}", controller.RevokeAPIKey).Methods(http.MethodDelete)
+	router.HandleFunc("/{organizationID}/role-bindings", controller.CreateRoleBinding).Methods(http.MethodPost)
+	router.HandleFunc("/{organizationID}/role-bindings", controller.GetRoleBindingList).Methods(http.MethodGet)
+	router.HandleFunc("/{organizationID}/role-bindings/{roleBindingID}", controller.UpdateRoleBinding).Methods(http.MethodPatch)
+	router.HandleFunc("/{organizationID}/role-bindings/{roleBindingID}", controller.DeleteRoleBinding).Methods(http.MethodDelete)
 }
@@ -6,12 +6,22 @@ import (
 	"github.com/gorilla/mux"
 	"gorm.io/gorm"
 
+	"github.com/tsujio/x-base/api/changes"
 	"github.com/tsujio/x-base/api/controllers/table"
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/jobs"
+	"github.com/tsujio/x-base/api/metrics"
+	"github.com/tsujio/x-base/api/quotas"
 )
 
-func SetTableRoutes(router *mux.Router, db *gorm.DB) {
+func SetTableRoutes(router *mux.Router, db *gorm.DB, runner *jobs.Runner, bus *events.Bus, recorder *changes.Recorder, checker *quotas.Checker, m *metrics.Metrics) {
 	controller := table.TableController{
-		DB: db,
+		DB:      db,
+		Runner:  runner,
+		Bus:     bus,
+		Changes: recorder,
+		Quotas:  checker,
+		Metrics: m,
 	}
 
 	router.HandleFunc("", controller.CreateTable).Methods(http.MethodPost)
@@ -19,8 +29,18 @@ func SetTableRoutes(router *mux.Router, db *gorm.DB) {
 	router.HandleFunc("/{tableID}", controller.UpdateTable).Methods(http.MethodPatch)
 	router.HandleFunc("/{tableID}", controller.DeleteTable).Methods(http.MethodDelete)
 	router.HandleFunc("/{tableID}/columns", controller.CreateColumn).Methods(http.MethodPost)
+	router.HandleFunc("/{tableID}/columns/{columnID}", controller.GetColumn).Methods(http.MethodGet)
 	router.HandleFunc("/{tableID}/columns/{columnID}", controller.UpdateColumn).Methods(http.MethodPatch)
 	router.HandleFunc("/{tableID}/columns/{columnID}", controller.DeleteColumn).Methods(http.MethodDelete)
+	router.HandleFunc("/{tableID}/columns/{columnID}/convert", controller.ConvertColumn).Methods(http.MethodPost)
 	router.HandleFunc("/{tableID}/columns/reorder", controller.ReorderColumn).Methods(http.MethodPost)
+	router.HandleFunc("/{tableID}/policies", controller.CreateTablePolicy).Methods(http.MethodPost)
+	router.HandleFunc("/{tableID}/policies", controller.GetTablePolicyList).Methods(http.MethodGet)
+	router.HandleFunc("/{tableID}/policies/{policyID}", controller.UpdateTablePolicy).Methods(http.MethodPatch)
+	router.HandleFunc("/{tableID}/policies/{policyID}", controller.DeleteTablePolicy).Methods(http.MethodDelete)
 	router.HandleFunc("/{tableID}/query", controller.QueryTableRecord).Methods(http.MethodPost)
+	router.HandleFunc("/{tableID}/duplicate", controller.DuplicateTable).Methods(http.MethodPost)
+	router.HandleFunc("/{tableID}/xlsx", controller.ExportTableXLSX).Methods(http.MethodGet)
+	router.HandleFunc("/{tableID}/xlsx", controller.ImportTableXLSX).Methods(http.MethodPost)
+	router.HandleFunc("/{tableID}/events", controller.StreamTableEvents).Methods(http.MethodGet)
 }
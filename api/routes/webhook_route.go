@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/controllers/webhook"
+	"github.com/tsujio/x-base/api/webhooks"
+)
+
+func SetWebhookRoutes(router *mux.Router, db *gorm.DB, conf *webhooks.Config) {
+	controller := webhook.WebhookController{
+		DB:     db,
+		Config: conf,
+	}
+
+	router.HandleFunc("", controller.CreateWebhook).Methods(http.MethodPost)
+	router.HandleFunc("", controller.GetWebhookList).Methods(http.MethodGet)
+	router.HandleFunc("/{webhookID}", controller.GetWebhook).Methods(http.MethodGet)
+	router.HandleFunc("/{webhookID}", controller.UpdateWebhook).Methods(http.MethodPatch)
+	router.HandleFunc("/{webhookID}", controller.DeleteWebhook).Methods(http.MethodDelete)
+	router.HandleFunc("/{webhookID}/deliveries", controller.GetWebhookDeliveryList).Methods(http.MethodGet)
+}
@@ -0,0 +1,69 @@
+package schemas
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GetAPIKeyListInput struct {
+	PaginationInput
+	Sort string `schema:"sort"`
+}
+
+type CreateAPIKeyInput struct {
+	Role       string                 `json:"role" validate:"omitempty,oneof=owner editor commenter viewer"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type APIKey struct {
+	ID             uuid.UUID              `json:"id"`
+	OrganizationID uuid.UUID              `json:"organizationId"`
+	Prefix         string                 `json:"prefix"`
+	Properties     map[string]interface{} `json:"properties"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+	LastUsedAt     *time.Time             `json:"lastUsedAt"`
+	RevokedAt      *time.Time             `json:"revokedAt"`
+}
+
+func (k APIKey) MarshalJSON() ([]byte, error) {
+	if k.Properties == nil {
+		k.Properties = make(map[string]interface{})
+	}
+	type Alias APIKey
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(k)})
+}
+
+// CreatedAPIKey includes the raw key which is shown only once.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+func (k CreatedAPIKey) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(&k.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	m["key"] = k.Key
+	return json.Marshal(m)
+}
+
+type APIKeyList struct {
+	PaginatedList
+	APIKeys []APIKey `json:"apiKeys"`
+}
+
+func (l APIKeyList) MarshalJSON() ([]byte, error) {
+	if l.APIKeys == nil {
+		l.APIKeys = []APIKey{}
+	}
+	type Alias APIKeyList
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(l)})
+}
@@ -9,6 +9,7 @@ import (
 
 type CreateColumnInput struct {
 	Index      *int                   `json:"index" validate:"omitempty,gte=0,lte=999"`
+	Type       *string                `json:"type" validate:"omitempty,oneof=text number date boolean"`
 	Properties map[string]interface{} `json:"properties"`
 }
 
@@ -17,6 +18,12 @@ type UpdateColumnInput struct {
 	Properties map[string]interface{} `json:"properties"`
 }
 
+type ConvertColumnInput struct {
+	Type    string `json:"type" validate:"required,oneof=text number date boolean"`
+	OnError string `json:"onError" validate:"omitempty,oneof=abort null"`
+	DryRun  bool   `json:"dryRun"`
+}
+
 type ReorderColumnInput struct {
 	Order []uuid.UUID `json:"order"`
 }
@@ -25,6 +32,7 @@ type Column struct {
 	ID         uuid.UUID              `json:"id"`
 	TableID    uuid.UUID              `json:"tableId"`
 	Index      int                    `json:"index"`
+	Type       *string                `json:"type"`
 	Properties map[string]interface{} `json:"properties"`
 	CreatedAt  time.Time              `json:"createdAt"`
 	UpdatedAt  time.Time              `json:"updatedAt"`
@@ -49,3 +57,24 @@ func (c ColumnList) MarshalJSON() ([]byte, error) {
 	type Alias ColumnList
 	return json.Marshal(&struct{ Alias }{Alias: (Alias)(c)})
 }
+
+type ColumnConversionResult struct {
+	Column           Column                    `json:"column"`
+	Applied          bool                      `json:"applied"`
+	ConvertedRecords int64                     `json:"convertedRecords"`
+	FailureCount     int64                     `json:"failureCount"`
+	Failures         []ColumnConversionFailure `json:"failures"`
+}
+
+func (r ColumnConversionResult) MarshalJSON() ([]byte, error) {
+	if r.Failures == nil {
+		r.Failures = []ColumnConversionFailure{}
+	}
+	type Alias ColumnConversionResult
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(r)})
+}
+
+type ColumnConversionFailure struct {
+	RecordID uuid.UUID   `json:"recordId"`
+	Value    interface{} `json:"value"`
+}
@@ -31,6 +31,17 @@ func DecodeJSON(source io.Reader, dest interface{}) error {
 }
 
 func DecodeQuery(source map[string][]string, dest interface{}) error {
+	// access_token is the credential read by the authentication middleware
+	if _, exists := source["access_token"]; exists {
+		params := make(map[string][]string)
+		for k, v := range source {
+			if k != "access_token" {
+				params[k] = v
+			}
+		}
+		source = params
+	}
+
 	decoder := schema.NewDecoder()
 	err := decoder.Decode(dest, source)
 	if err != nil {
@@ -1,5 +1,6 @@
 package schemas
 
 type Error struct {
-	Message string `json:"message"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestId,omitempty"`
 }
@@ -0,0 +1,28 @@
+package schemas
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+type StreamEventsInput struct {
+	OrganizationID uuid.UUID `schema:"organizationId"`
+	LastEventID    *int64    `schema:"lastEventId" validate:"omitempty,min=0"`
+}
+
+// DecodeLastEventIDHeader overwrites LastEventID with the Last-Event-ID header sent by reconnecting clients.
+func (input *StreamEventsInput) DecodeLastEventIDHeader(header http.Header) error {
+	s := header.Get("Last-Event-ID")
+	if s == "" {
+		return nil
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 0 {
+		return xerrors.Errorf("Invalid Last-Event-ID: %s", s)
+	}
+	input.LastEventID = &id
+	return nil
+}
@@ -29,6 +29,25 @@ type GetFolderChildrenInput struct {
 	Sort           string    `schema:"sort"`
 }
 
+type GetFolderTreeInput struct {
+	OrganizationID uuid.UUID `schema:"organizationId"`
+	Depth          int       `schema:"depth" validate:"gte=0"`
+	Type           string    `schema:"type" validate:"omitempty,oneof=folder table"`
+	Properties     string    `schema:"properties"`
+	Sort           string    `schema:"sort"`
+}
+
+type CopyFolderInput struct {
+	ParentFolderID *uuid.UUID             `json:"parentFolderId"`
+	Properties     map[string]interface{} `json:"properties"`
+	IncludeRecords bool                   `json:"includeRecords"`
+	Async          bool                   `json:"async"`
+}
+
+type ExportFolderXLSXInput struct {
+	OrganizationID uuid.UUID `schema:"organizationId"`
+}
+
 type Folder struct {
 	TableFilesystemEntry
 }
@@ -70,6 +89,37 @@ func (c FolderChild) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct{ Alias }{Alias: (Alias)(c)})
 }
 
+type FolderTree struct {
+	Children []FolderTreeEntry `json:"children"`
+}
+
+func (t FolderTree) MarshalJSON() ([]byte, error) {
+	if t.Children == nil {
+		t.Children = []FolderTreeEntry{}
+	}
+	type Alias FolderTree
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(t)})
+}
+
+// FolderTreeEntry has children only if it is a folder expanded within the depth.
+type FolderTreeEntry struct {
+	ID             uuid.UUID              `json:"id"`
+	OrganizationID uuid.UUID              `json:"organizationId"`
+	Type           string                 `json:"type"`
+	Properties     map[string]interface{} `json:"properties"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+	Children       *[]FolderTreeEntry     `json:"children,omitempty"`
+}
+
+func (e FolderTreeEntry) MarshalJSON() ([]byte, error) {
+	if e.Properties == nil {
+		e.Properties = make(map[string]interface{})
+	}
+	type Alias FolderTreeEntry
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(e)})
+}
+
 type TableFilesystemEntry struct {
 	ID             uuid.UUID                  `json:"id"`
 	OrganizationID uuid.UUID                  `json:"organizationId"`
@@ -0,0 +1,33 @@
+package schemas
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Job struct {
+	ID             uuid.UUID       `json:"id"`
+	OrganizationID uuid.UUID       `json:"organizationId"`
+	Type           string          `json:"type"`
+	Status         string          `json:"status"`
+	Progress       json.RawMessage `json:"progress"`
+	Result         json.RawMessage `json:"result"`
+	Error          *string         `json:"error"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	StartedAt      *time.Time      `json:"startedAt"`
+	FinishedAt     *time.Time      `json:"finishedAt"`
+}
+
+func (j Job) MarshalJSON() ([]byte, error) {
+	if len(j.Progress) == 0 {
+		j.Progress = json.RawMessage("null")
+	}
+	if len(j.Result) == 0 {
+		j.Result = json.RawMessage("null")
+	}
+	type Alias Job
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(j)})
+}
@@ -17,6 +17,16 @@ type GetOrganizationInput struct {
 	Properties string `schema:"properties"`
 }
 
+type SearchTableFilesystemEntriesInput struct {
+	PaginationInput
+	Property   string `schema:"property"`
+	Prefix     string `schema:"prefix"`
+	Contains   string `schema:"contains"`
+	Type       string `schema:"type" validate:"omitempty,oneof=folder table"`
+	Properties string `schema:"properties"`
+	Sort       string `schema:"sort"`
+}
+
 type CreateOrganizationInput struct {
 	Properties map[string]interface{} `json:"properties"`
 }
@@ -52,3 +62,16 @@ func (o OrganizationList) MarshalJSON() ([]byte, error) {
 	type Alias OrganizationList
 	return json.Marshal(&struct{ Alias }{Alias: (Alias)(o)})
 }
+
+type SearchResult struct {
+	PaginatedList
+	Entries []FolderChild `json:"entries"`
+}
+
+func (s SearchResult) MarshalJSON() ([]byte, error) {
+	if s.Entries == nil {
+		s.Entries = []FolderChild{}
+	}
+	type Alias SearchResult
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(s)})
+}
@@ -0,0 +1,48 @@
+package schemas
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GetRoleBindingListInput struct {
+	PaginationInput
+	APIKeyID   *uuid.UUID `schema:"apiKeyId"`
+	ResourceID *uuid.UUID `schema:"resourceId"`
+	Sort       string     `schema:"sort"`
+}
+
+type CreateRoleBindingInput struct {
+	APIKeyID   uuid.UUID  `json:"apiKeyId" validate:"required"`
+	ResourceID *uuid.UUID `json:"resourceId"`
+	Role       string     `json:"role" validate:"required,oneof=owner editor commenter viewer"`
+}
+
+type UpdateRoleBindingInput struct {
+	Role string `json:"role" validate:"required,oneof=owner editor commenter viewer"`
+}
+
+type RoleBinding struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	APIKeyID       uuid.UUID  `json:"apiKeyId"`
+	ResourceID     *uuid.UUID `json:"resourceId"`
+	Role           string     `json:"role"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+type RoleBindingList struct {
+	PaginatedList
+	RoleBindings []RoleBinding `json:"roleBindings"`
+}
+
+func (l RoleBindingList) MarshalJSON() ([]byte, error) {
+	if l.RoleBindings == nil {
+		l.RoleBindings = []RoleBinding{}
+	}
+	type Alias RoleBindingList
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(l)})
+}
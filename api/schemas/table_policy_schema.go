@@ -0,0 +1,84 @@
+package schemas
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateTablePolicyInput struct {
+	Where      interface{}            `json:"where" validate:"required"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type UpdateTablePolicyInput struct {
+	Where      interface{}            `json:"where"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type TablePolicy struct {
+	ID         uuid.UUID              `json:"id"`
+	TableID    uuid.UUID              `json:"tableId"`
+	Where      json.RawMessage        `json:"where"`
+	Properties map[string]interface{} `json:"properties"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	UpdatedAt  time.Time              `json:"updatedAt"`
+}
+
+func (p TablePolicy) MarshalJSON() ([]byte, error) {
+	if p.Properties == nil {
+		p.Properties = make(map[string]interface{})
+	}
+	type Alias TablePolicy
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(p)})
+}
+
+type TablePolicyList struct {
+	Policies []TablePolicy `json:"policies"`
+}
+
+func (l TablePolicyList) MarshalJSON() ([]byte, error) {
+	if l.Policies == nil {
+		l.Policies = []TablePolicy{}
+	}
+	type Alias TablePolicyList
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(l)})
+}
+
+// DecodePolicyWhere decodes the where expression of a table policy.
+// Each {"attribute": KEY} in the expression is replaced with the value of KEY in attributes (null if missing).
+func DecodePolicyWhere(input interface{}, attributes map[string]interface{}) (interface{}, error) {
+	expr, err := DecodeExpr(bindAttributes(input, attributes), "where")
+	if err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(expr).Elem().Interface(), nil
+}
+
+func bindAttributes(input interface{}, attributes map[string]interface{}) interface{} {
+	switch in := input.(type) {
+	case map[string]interface{}:
+		if key, ok := in["attribute"].(string); ok && len(in) == 1 {
+			return map[string]interface{}{"value": attributes[key]}
+		}
+		if _, exists := in["value"]; exists {
+			// Literal
+			return input
+		}
+		out := make(map[string]interface{}, len(in))
+		for k, v := range in {
+			out[k] = bindAttributes(v, attributes)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(in))
+		for i, v := range in {
+			out[i] = bindAttributes(v, attributes)
+		}
+		return out
+	default:
+		return input
+	}
+}
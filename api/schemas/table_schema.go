@@ -14,7 +14,7 @@ type GetTableInput struct {
 type CreateTableInput struct {
 	OrganizationID uuid.UUID              `json:"organizationId" validate:"required"`
 	ParentFolderID *uuid.UUID             `json:"parentFolderId"`
-	Columns        []CreateColumnInput    `json:"columns"`
+	Columns        []CreateColumnInput    `json:"columns" validate:"dive"`
 	Properties     map[string]interface{} `json:"properties"`
 }
 
@@ -23,6 +23,16 @@ type UpdateTableInput struct {
 	Properties     map[string]interface{} `json:"properties"`
 }
 
+type DuplicateTableInput struct {
+	ParentFolderID *uuid.UUID             `json:"parentFolderId"`
+	Properties     map[string]interface{} `json:"properties"`
+	IncludeRecords bool                   `json:"includeRecords"`
+}
+
+type ImportTableXLSXInput struct {
+	Sheet string `schema:"sheet"`
+}
+
 type Table struct {
 	TableFilesystemEntry
 	Columns []Column `json:"columns"`
@@ -0,0 +1,117 @@
+package schemas
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GetWebhookListInput struct {
+	PaginationInput
+	OrganizationID uuid.UUID  `schema:"organizationId" validate:"required"`
+	TableID        *uuid.UUID `schema:"tableId"`
+	Sort           string     `schema:"sort"`
+}
+
+type CreateWebhookInput struct {
+	OrganizationID uuid.UUID              `json:"organizationId" validate:"required"`
+	TableID        *uuid.UUID             `json:"tableId"`
+	URL            string                 `json:"url" validate:"required,url"`
+	Secret         string                 `json:"secret"`
+	Events         []string               `json:"events"`
+	Properties     map[string]interface{} `json:"properties"`
+}
+
+type UpdateWebhookInput struct {
+	URL        *string                `json:"url" validate:"omitempty,url"`
+	Secret     *string                `json:"secret" validate:"omitempty,min=1"`
+	Events     *[]string              `json:"events"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type GetWebhookDeliveryListInput struct {
+	PaginationInput
+}
+
+type Webhook struct {
+	ID             uuid.UUID              `json:"id"`
+	OrganizationID uuid.UUID              `json:"organizationId"`
+	TableID        *uuid.UUID             `json:"tableId"`
+	URL            string                 `json:"url"`
+	Events         []string               `json:"events"`
+	Properties     map[string]interface{} `json:"properties"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+}
+
+func (wh Webhook) MarshalJSON() ([]byte, error) {
+	if wh.Events == nil {
+		wh.Events = []string{}
+	}
+	if wh.Properties == nil {
+		wh.Properties = make(map[string]interface{})
+	}
+	type Alias Webhook
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(wh)})
+}
+
+// CreatedWebhook includes the secret which is shown only on creation.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+func (wh CreatedWebhook) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(&wh.Webhook)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	m["secret"] = wh.Secret
+	return json.Marshal(m)
+}
+
+type WebhookList struct {
+	PaginatedList
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+func (l WebhookList) MarshalJSON() ([]byte, error) {
+	if l.Webhooks == nil {
+		l.Webhooks = []Webhook{}
+	}
+	type Alias WebhookList
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(l)})
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhookId"`
+	EventID        uuid.UUID       `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"responseStatus"`
+	Error          *string         `json:"error"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+}
+
+type WebhookDeliveryList struct {
+	PaginatedList
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+func (l WebhookDeliveryList) MarshalJSON() ([]byte, error) {
+	if l.Deliveries == nil {
+		l.Deliveries = []WebhookDelivery{}
+	}
+	type Alias WebhookDeliveryList
+	return json.Marshal(&struct{ Alias }{Alias: (Alias)(l)})
+}
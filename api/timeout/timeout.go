@@ -0,0 +1,65 @@
+package timeout
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const DefaultTimeout = 30 * time.Second
+
+// defaultRoutes are the routes with their own timeouts by default.
+// Event streams last until the client disconnects.
+var defaultRoutes = map[string]time.Duration{
+	"/tables/{tableID}/events":   0,
+	"/folders/{folderID}/events": 0,
+}
+
+type Config struct {
+	// Default is the timeout of requests. DefaultTimeout if zero.
+	Default time.Duration
+	// Routes maps route templates like "/tables/{tableID}/query" to their timeouts, which override Default.
+	// Requests of the routes mapped to zero have no timeout.
+	Routes map[string]time.Duration
+}
+
+func (conf *Config) timeoutOf(route string) time.Duration {
+	if conf != nil {
+		if t, exists := conf.Routes[route]; exists {
+			return t
+		}
+	}
+	if t, exists := defaultRoutes[route]; exists {
+		return t
+	}
+	if conf == nil || conf.Default <= 0 {
+		return DefaultTimeout
+	}
+	return conf.Default
+}
+
+// Middleware sets the deadline of the route to the request context.
+// Queries bound to the context are cancelled when the deadline is hit.
+// It must be used as a middleware of mux.Router so that the route is matched.
+func Middleware(conf *Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var route string
+			if cr := mux.CurrentRoute(r); cr != nil {
+				route, _ = cr.GetPathTemplate()
+			}
+
+			t := conf.timeoutOf(route)
+			if t <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), t)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
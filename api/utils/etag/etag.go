@@ -0,0 +1,113 @@
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/api/utils/responses"
+)
+
+// Of returns the entity tag of the JSON representation of v.
+func Of(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", xerrors.Errorf("Failed to encode: %w", err)
+	}
+	h := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(h[:16]) + `"`, nil
+}
+
+func parseList(header string) []string {
+	var tags []string
+	for _, t := range strings.Split(header, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// NotModified sets the ETag header and sends 304 Not Modified if If-None-Match of the request matches the tag.
+// It reports whether the response has been sent.
+func NotModified(w http.ResponseWriter, r *http.Request, tag string) bool {
+	w.Header().Set("ETag", tag)
+
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, t := range parseList(header) {
+		// Weak comparison
+		if t == "*" || strings.TrimPrefix(t, "W/") == tag {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+var ErrPreconditionFailed = errors.New("Precondition failed")
+
+// Check returns ErrPreconditionFailed if If-Match of the request does not match the tag of the current representation.
+// current is called only if the request has If-Match.
+//
+// To update resources atomically, call it in the transaction after locking the resource and return the error from the transaction.
+func Check(r *http.Request, current func() (interface{}, error)) error {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return nil
+	}
+
+	v, err := current()
+	if err != nil {
+		return xerrors.Errorf("Failed to get current representation: %w", err)
+	}
+	tag, err := Of(v)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range parseList(header) {
+		// Strong comparison
+		if t == "*" || t == tag {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
+
+// PreconditionFailed sends 412 Precondition Failed if If-Match of the request does not match the tag of the current representation.
+// It reports whether the response has been sent. current is called only if the request has If-Match.
+func PreconditionFailed(w http.ResponseWriter, r *http.Request, current func() (interface{}, error)) bool {
+	err := Check(r, current)
+	if err == nil {
+		return false
+	}
+	SendError(w, r, err)
+	return true
+}
+
+// SendError sends 412 Precondition Failed if err is ErrPreconditionFailed, otherwise 500.
+func SendError(w http.ResponseWriter, r *http.Request, err error) {
+	if xerrors.Is(err, ErrPreconditionFailed) {
+		responses.SendErrorResponse(w, r, http.StatusPreconditionFailed, "Precondition failed", nil)
+		return
+	}
+	responses.SendErrorResponse(w, r, http.StatusInternalServerError, "Failed to check precondition", err)
+}
+
+// Set sets the ETag header of the representation v.
+func Set(w http.ResponseWriter, v interface{}) error {
+	tag, err := Of(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("ETag", tag)
+	return nil
+}
@@ -0,0 +1,60 @@
+package recorder
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// Recorder records the status code, and the body if created by WithBody.
+// It is a http.Flusher to keep event streams working.
+type Recorder struct {
+	http.ResponseWriter
+	status     int
+	recordBody bool
+	body       bytes.Buffer
+}
+
+func New(w http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: w}
+}
+
+// WithBody returns the recorder which keeps the body written as well.
+func WithBody(w http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: w, recordBody: true}
+}
+
+func (rec *Recorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *Recorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	if rec.recordBody {
+		rec.body.Write(b)
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *Recorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Status returns the status code written, which is 200 if nothing is written.
+func (rec *Recorder) Status() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
+
+// Body returns the body written if created by WithBody.
+func (rec *Recorder) Body() []byte {
+	return rec.body.Bytes()
+}
@@ -1,15 +1,33 @@
 package responses
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"golang.org/x/xerrors"
+
 	"github.com/tsujio/x-base/api/schemas"
 	"github.com/tsujio/x-base/logging"
 )
 
+// StatusClientClosedRequest is logged for requests cancelled by the client.
+const StatusClientClosedRequest = 499
+
 func SendErrorResponse(w http.ResponseWriter, r *http.Request, status int, message string, err error) {
+	// Failures caused by the request context are not server errors
+	if status == http.StatusInternalServerError {
+		switch ctxErr := r.Context().Err(); {
+		case xerrors.Is(err, context.DeadlineExceeded) || xerrors.Is(ctxErr, context.DeadlineExceeded):
+			logging.Warning(fmt.Sprintf("%s: %+v", message, err), r)
+			status, message, err = http.StatusGatewayTimeout, "Request timed out", nil
+		case xerrors.Is(err, context.Canceled) || xerrors.Is(ctxErr, context.Canceled):
+			logging.Info("Request cancelled", r)
+			status, message, err = StatusClientClosedRequest, "Request cancelled", nil
+		}
+	}
+
 	if status == http.StatusInternalServerError {
 		if err != nil {
 			logging.Error(fmt.Sprintf("%s: %+v", message, err), r)
@@ -24,7 +42,8 @@ func SendErrorResponse(w http.ResponseWriter, r *http.Request, status int, messa
 		msg += ": " + err.Error()
 	}
 	if e := json.NewEncoder(w).Encode(&schemas.Error{
-		Message: msg,
+		Message:   msg,
+		RequestID: logging.RequestID(r),
 	}); e != nil {
 		logging.Error(fmt.Sprintf("%+v", e), r)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -0,0 +1,208 @@
+package xlsx
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/api/models"
+)
+
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+const maxSheetNameLength = 31
+
+var invalidSheetNameChars = regexp.MustCompile(`[\[\]:*?/\\]`)
+
+const defaultSheetName = "Sheet1"
+
+type Workbook struct {
+	File       *excelize.File
+	sheetNames map[string]bool
+}
+
+func NewWorkbook() *Workbook {
+	return &Workbook{
+		File:       excelize.NewFile(),
+		sheetNames: map[string]bool{},
+	}
+}
+
+func (w *Workbook) makeSheetName(name string) string {
+	base := strings.TrimSpace(invalidSheetNameChars.ReplaceAllString(name, "_"))
+	base = strings.Trim(base, "'")
+	if base == "" {
+		base = defaultSheetName
+	}
+	if r := []rune(base); len(r) > maxSheetNameLength {
+		base = string(r[:maxSheetNameLength])
+	}
+
+	sheet := base
+	for i := 2; w.sheetNames[strings.ToLower(sheet)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		r := []rune(base)
+		if len(r)+len(suffix) > maxSheetNameLength {
+			r = r[:maxSheetNameLength-len(suffix)]
+		}
+		sheet = string(r) + suffix
+	}
+	w.sheetNames[strings.ToLower(sheet)] = true
+
+	return sheet
+}
+
+func (w *Workbook) AddSheet(name string, header []string, records [][]interface{}) error {
+	sheet := w.makeSheetName(name)
+	if len(w.sheetNames) == 1 {
+		// Reuse the default sheet created with the workbook
+		w.File.SetSheetName(defaultSheetName, sheet)
+	} else {
+		w.File.NewSheet(sheet)
+	}
+
+	sw, err := w.File.NewStreamWriter(sheet)
+	if err != nil {
+		return xerrors.Errorf("Failed to create stream writer: %w", err)
+	}
+
+	var row []interface{}
+	for _, h := range header {
+		row = append(row, h)
+	}
+	if err := sw.SetRow("A1", row); err != nil {
+		return xerrors.Errorf("Failed to write header: %w", err)
+	}
+
+	for i, record := range records {
+		row := make([]interface{}, len(record))
+		for j, v := range record {
+			cell, err := toCellValue(v)
+			if err != nil {
+				return xerrors.Errorf("Failed to convert cell value: %w", err)
+			}
+			row[j] = cell
+		}
+		axis, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return xerrors.Errorf("Failed to get cell name: %w", err)
+		}
+		if err := sw.SetRow(axis, row); err != nil {
+			return xerrors.Errorf("Failed to write record: %w", err)
+		}
+	}
+
+	if err := sw.Flush(); err != nil {
+		return xerrors.Errorf("Failed to flush stream writer: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Workbook) AddTable(table *models.Table, records [][]interface{}) error {
+	var header []string
+	for _, c := range table.Columns {
+		header = append(header, Name(c.ID, c.Properties))
+	}
+	return w.AddSheet(Name(table.ID, table.Properties), header, records)
+}
+
+func Name(id models.UUID, properties models.Properties) string {
+	if name, ok := properties["name"].(string); ok && name != "" {
+		return name
+	}
+	return id.String()
+}
+
+func toCellValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case nil, string, bool, float64, int, int64:
+		return val, nil
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to serialize value: %w", err)
+		}
+		return string(b), nil
+	}
+}
+
+// ReadSheet reads the first row as the header and the following rows as records.
+// Cell values are converted to string, float64, bool or nil according to the cell types.
+func ReadSheet(f *excelize.File, sheet string) ([]string, [][]interface{}, error) {
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	if f.GetSheetIndex(sheet) == -1 {
+		return nil, nil, fmt.Errorf("Sheet not found: %s", sheet)
+	}
+
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, nil, xerrors.Errorf("Failed to get rows: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("Header row not found")
+	}
+
+	var header []string
+	for _, h := range rows[0] {
+		header = append(header, strings.TrimSpace(h))
+	}
+	for len(header) > 0 && header[len(header)-1] == "" {
+		header = header[:len(header)-1]
+	}
+
+	var records [][]interface{}
+	for i, row := range rows[1:] {
+		record := make([]interface{}, len(header))
+		empty := true
+		for j := range header {
+			if j >= len(row) || row[j] == "" {
+				continue
+			}
+			axis, err := excelize.CoordinatesToCellName(j+1, i+2)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("Failed to get cell name: %w", err)
+			}
+			typ, err := f.GetCellType(sheet, axis)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("Failed to get cell type: %w", err)
+			}
+			record[j] = fromCellValue(row[j], typ)
+			empty = false
+		}
+		if !empty {
+			records = append(records, record)
+		}
+	}
+
+	return header, records, nil
+}
+
+// fromCellValue converts the raw value of the cell by its type.
+// Cells without the type, which spreadsheet applications write for numbers, are converted only if the number
+// represents the same text, so that text like "00123", "1e3" or long ids is not corrupted.
+func fromCellValue(raw string, typ excelize.CellType) interface{} {
+	switch typ {
+	case excelize.CellTypeBool:
+		return raw == "1" || strings.ToLower(raw) == "true"
+	case excelize.CellTypeNumber:
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f
+		}
+		return raw
+	case excelize.CellTypeUnset:
+		if f, err := strconv.ParseFloat(raw, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == raw {
+			return f
+		}
+		return raw
+	default:
+		return raw
+	}
+}
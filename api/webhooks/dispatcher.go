@@ -0,0 +1,324 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/events"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/logging"
+)
+
+const (
+	SignatureHeader = "X-XBase-Signature"
+	TimestampHeader = "X-XBase-Timestamp"
+	EventHeader     = "X-XBase-Event"
+	DeliveryHeader  = "X-XBase-Delivery"
+)
+
+// DefaultLeaseDuration is how long a delivery stays claimed by a dispatcher for an attempt.
+// It should be longer than the timeout of the client.
+const DefaultLeaseDuration = time.Minute
+
+type Dispatcher struct {
+	DB             *gorm.DB
+	Client         *http.Client
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// ID identifies the dispatcher as the owner of the deliveries it claims.
+	ID            string
+	LeaseDuration time.Duration
+
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	stopped  bool
+	stopping chan struct{}
+}
+
+func NewDispatcher(db *gorm.DB, conf *Config) *Dispatcher {
+	return &Dispatcher{
+		DB:             db,
+		Client:         newClient(conf),
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		ID:             uuid.NewString(),
+		LeaseDuration:  DefaultLeaseDuration,
+		stopping:       make(chan struct{}),
+	}
+}
+
+// Subscribe makes the dispatcher deliver events published to the bus.
+func (d *Dispatcher) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(e *events.Event) {
+		if !d.add() {
+			logging.Warning(fmt.Sprintf("Event %s is not dispatched since the dispatcher is stopped", e.ID), nil)
+			return
+		}
+		go func() {
+			defer d.wg.Done()
+			if err := d.dispatch(e); err != nil {
+				logging.Error(fmt.Sprintf("Failed to dispatch event %s: %+v", e.ID, err), nil)
+			}
+		}()
+	})
+}
+
+// Resume restarts pending deliveries which are released by stopped dispatchers or whose lease has expired.
+// Deliveries claimed by another dispatcher in the meantime are skipped.
+func (d *Dispatcher) Resume() error {
+	deliveries, err := models.GetClaimableWebhookDeliveries(d.DB, time.Now().UTC())
+	if err != nil {
+		return xerrors.Errorf("Failed to get claimable deliveries: %w", err)
+	}
+	for i := range deliveries {
+		delivery := deliveries[i]
+		if !d.add() {
+			return nil
+		}
+		go func() {
+			defer d.wg.Done()
+			if err := d.resume(&delivery); err != nil {
+				logging.Error(fmt.Sprintf("Failed to resume delivery %s: %+v", delivery.ID, err), nil)
+			}
+		}()
+	}
+	return nil
+}
+
+// ResumePeriodically calls Resume every LeaseDuration in background until Stop,
+// to pick up deliveries left by stopped instances once their leases expire.
+func (d *Dispatcher) ResumePeriodically() {
+	if !d.add() {
+		return
+	}
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.LeaseDuration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stopping:
+				return
+			case <-ticker.C:
+				if err := d.Resume(); err != nil {
+					logging.Error(fmt.Sprintf("Failed to resume webhook deliveries: %+v", err), nil)
+				}
+			}
+		}
+	}()
+}
+
+func (d *Dispatcher) resume(delivery *models.WebhookDelivery) error {
+	now := time.Now().UTC()
+	claimed, err := delivery.Claim(d.DB, d.ID, now, now.Add(d.LeaseDuration))
+	if err != nil {
+		return xerrors.Errorf("Failed to claim delivery: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+
+	webhook, err := (&models.Webhook{ID: delivery.WebhookID}).Get(d.DB)
+	if err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			msg := "Webhook not found"
+			delivery.Status = models.WebhookDeliveryStatusFailed
+			delivery.Error = &msg
+			if _, err := delivery.SaveAttempt(d.DB, d.ID, nil); err != nil {
+				return xerrors.Errorf("Failed to save delivery: %w", err)
+			}
+			return nil
+		}
+		if err := delivery.Release(d.DB, d.ID); err != nil {
+			logging.Error(fmt.Sprintf("Failed to release delivery %s: %+v", delivery.ID, err), nil)
+		}
+		return xerrors.Errorf("Failed to get webhook: %w", err)
+	}
+
+	d.deliver(webhook, delivery)
+	return nil
+}
+
+// Wait blocks until all started deliveries finish.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Stop stops dispatching events and waits for the deliveries being sent.
+// Deliveries waiting to retry are left pending and released to be restarted by Resume.
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	if !d.stopped {
+		d.stopped = true
+		close(d.stopping)
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+// add adds a goroutine to wait for unless stopped.
+func (d *Dispatcher) add() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return false
+	}
+	d.wg.Add(1)
+	return true
+}
+
+func (d *Dispatcher) dispatch(e *events.Event) error {
+	webhooks, err := models.GetSubscribedWebhooks(d.DB, models.UUID(e.OrganizationID), (*models.UUID)(e.TableID), e.Type)
+	if err != nil {
+		return xerrors.Errorf("Failed to get webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return xerrors.Errorf("Failed to serialize event: %w", err)
+	}
+
+	for i := range webhooks {
+		webhook := &webhooks[i]
+		leaseExpiresAt := time.Now().UTC().Add(d.LeaseDuration)
+		delivery := &models.WebhookDelivery{
+			WebhookID:      webhook.ID,
+			EventID:        models.UUID(e.ID),
+			EventType:      e.Type,
+			Payload:        models.JSON(payload),
+			Owner:          &d.ID,
+			LeaseExpiresAt: &leaseExpiresAt,
+		}
+		if err := delivery.Create(d.DB); err != nil {
+			return xerrors.Errorf("Failed to create delivery: %w", err)
+		}
+		if !d.add() {
+			return nil
+		}
+		go func() {
+			defer d.wg.Done()
+			d.deliver(webhook, delivery)
+		}()
+	}
+
+	return nil
+}
+
+// deliver sends the delivery claimed by the dispatcher until it succeeds or fails MaxAttempts times.
+// The lease is renewed to cover the backoff and the next attempt, and released when stopped while waiting.
+func (d *Dispatcher) deliver(webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	backoff := d.InitialBackoff
+	for delivery.Status == models.WebhookDeliveryStatusPending {
+		status, err := d.send(webhook, delivery)
+
+		delivery.Attempts++
+		delivery.ResponseStatus = status
+		if err == nil {
+			now := time.Now().UTC()
+			delivery.Status = models.WebhookDeliveryStatusSucceeded
+			delivery.Error = nil
+			delivery.DeliveredAt = &now
+		} else {
+			msg := err.Error()
+			delivery.Error = &msg
+			if delivery.Attempts >= d.MaxAttempts {
+				delivery.Status = models.WebhookDeliveryStatusFailed
+			}
+		}
+
+		var leaseExpiresAt *time.Time
+		if delivery.Status == models.WebhookDeliveryStatusPending {
+			t := time.Now().UTC().Add(backoff + d.LeaseDuration)
+			leaseExpiresAt = &t
+		}
+		saved, err := delivery.SaveAttempt(d.DB, d.ID, leaseExpiresAt)
+		if err != nil {
+			logging.Error(fmt.Sprintf("Failed to save delivery %s: %+v", delivery.ID, err), nil)
+			return
+		}
+		if !saved {
+			logging.Warning(fmt.Sprintf("Lease of delivery %s was lost", delivery.ID), nil)
+			return
+		}
+
+		if delivery.Status == models.WebhookDeliveryStatusPending {
+			select {
+			case <-d.stopping:
+				if err := delivery.Release(d.DB, d.ID); err != nil {
+					logging.Error(fmt.Sprintf("Failed to release delivery %s: %+v", delivery.ID, err), nil)
+				}
+				return
+			case <-time.After(backoff):
+			}
+
+			// Make sure that no other dispatcher has taken over the delivery before retrying
+			renewed, err := delivery.RenewLease(d.DB, d.ID, time.Now().UTC().Add(d.LeaseDuration))
+			if err != nil {
+				logging.Error(fmt.Sprintf("Failed to renew lease of delivery %s: %+v", delivery.ID, err), nil)
+				return
+			}
+			if !renewed {
+				logging.Warning(fmt.Sprintf("Lease of delivery %s was lost", delivery.ID), nil)
+				return
+			}
+			backoff *= 2
+			if backoff > d.MaxBackoff {
+				backoff = d.MaxBackoff
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) send(webhook *models.Webhook, delivery *models.WebhookDelivery) (*int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to make request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	res, err := d.Client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return &res.StatusCode, fmt.Errorf("Unexpected status code: %d", res.StatusCode)
+	}
+	return &res.StatusCode, nil
+}
+
+// Sign computes the hex encoded HMAC-SHA256 of "<timestamp>.<payload>".
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type Config struct {
+	// AllowPrivateNetworks permits webhook URLs on loopback, private and link-local addresses.
+	AllowPrivateNetworks bool
+}
+
+// blockedNetworks are the networks which webhooks cannot be sent to unless AllowPrivateNetworks.
+// They include the metadata endpoints of cloud providers (169.254.169.254 and fd00:ec2::254).
+var blockedNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"224.0.0.0/4",
+		"::/128",
+		"::1/128",
+		"fc00::/7",
+		"fe80::/10",
+		"ff00::/8",
+	} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+func isBlockedIP(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, n := range blockedNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateURL returns a message describing why webhooks cannot be sent to the url, or "" if they can.
+// Host names are checked again with the resolved addresses when sending.
+func ValidateURL(rawURL string, conf *Config) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "Invalid url"
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "Url scheme must be http or https"
+	}
+	if u.Host == "" {
+		return "Invalid url"
+	}
+	if conf != nil && conf.AllowPrivateNetworks {
+		return ""
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "Url host is not allowed"
+	}
+	if ip := net.ParseIP(host); ip != nil && isBlockedIP(ip) {
+		return "Url host is not allowed"
+	}
+	return ""
+}
+
+// newClient returns the client which refuses to connect to blocked addresses unless AllowPrivateNetworks.
+func newClient(conf *Config) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+	}
+	if conf == nil || !conf.AllowPrivateNetworks {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isBlockedIP(ip) {
+				return fmt.Errorf("Address is not allowed: %s", host)
+			}
+			return nil
+		}
+	}
+
+	// Connect directly so that the addresses of the webhooks, not of a proxy, are checked.
+	// Redirected requests are checked by the dialer as well.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+	}
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/api/schemas"
+)
+
+type Config struct {
+	// APIKey is sent as the bearer token. It may be a JWT in the jwt auth mode.
+	APIKey string
+	// HTTPClient sends requests. http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// Client calls the API served at the base URL like "https://xbase.example.com".
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+func New(baseURL string, conf *Config) *Client {
+	if conf == nil {
+		conf = &Config{}
+	}
+	httpClient := conf.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     conf.APIKey,
+		HTTPClient: httpClient,
+	}
+}
+
+// Error is returned when the API responds with a non-2xx status.
+type Error struct {
+	StatusCode int
+	Message    string
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("%d %s (request id %s)", e.StatusCode, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+}
+
+// StatusCode returns the status code of the error returned by the client, or zero if it is not an API error.
+func StatusCode(err error) int {
+	var e *Error
+	if xerrors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, input, output interface{}) error {
+	// Make request
+	var body io.Reader
+	if input != nil {
+		b, err := json.Marshal(input)
+		if err != nil {
+			return xerrors.Errorf("Failed to encode request body: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return xerrors.Errorf("Failed to make request: %w", err)
+	}
+	if input != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	// Send
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("Failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return xerrors.Errorf("Failed to read response body: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		apiErr := &Error{
+			StatusCode: res.StatusCode,
+			Message:    http.StatusText(res.StatusCode),
+		}
+		var e schemas.Error
+		if err := json.Unmarshal(b, &e); err == nil && e.Message != "" {
+			apiErr.Message = e.Message
+			apiErr.RequestID = e.RequestID
+		}
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = res.Header.Get("X-Request-ID")
+		}
+		return apiErr
+	}
+
+	if output != nil {
+		if err := json.Unmarshal(b, output); err != nil {
+			return xerrors.Errorf("Failed to decode response body: %w", err)
+		}
+	}
+	return nil
+}
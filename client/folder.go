@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/tsujio/x-base/api/schemas"
+)
+
+func (c *Client) CreateFolder(ctx context.Context, input *schemas.CreateFolderInput) (*schemas.Folder, error) {
+	var output schemas.Folder
+	if err := c.do(ctx, http.MethodPost, "/folders", nil, input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) GetFolder(ctx context.Context, id uuid.UUID) (*schemas.Folder, error) {
+	var output schemas.Folder
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/folders/%s", id), nil, nil, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) UpdateFolder(ctx context.Context, id uuid.UUID, input *schemas.UpdateFolderInput) (*schemas.Folder, error) {
+	var output schemas.Folder
+	if err := c.do(ctx, http.MethodPatch, fmt.Sprintf("/folders/%s", id), nil, input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/folders/%s", id), nil, nil, nil)
+}
+
+// GetFolderChildren lists the tables and folders in the folder.
+// The root folder of the organization is given by uuid.Nil as the folder id.
+func (c *Client) GetFolderChildren(ctx context.Context, organizationID, folderID uuid.UUID, opts *ListOptions) (*schemas.FolderChildren, error) {
+	query := opts.query()
+	if folderID == uuid.Nil {
+		query.Set("organizationId", organizationID.String())
+	}
+	var output schemas.FolderChildren
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/folders/%s/children", folderID), query, nil, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// EachFolderChild calls fn for each child of the folder, fetching pages from opts.Page.
+func (c *Client) EachFolderChild(ctx context.Context, organizationID, folderID uuid.UUID, opts *ListOptions, fn func(*schemas.FolderChild) error) error {
+	return pages(opts, func(opts *ListOptions) (int, int64, error) {
+		list, err := c.GetFolderChildren(ctx, organizationID, folderID, opts)
+		if err != nil {
+			return 0, 0, err
+		}
+		for i := range list.Children {
+			if err := fn(&list.Children[i]); err != nil {
+				return 0, 0, err
+			}
+		}
+		return len(list.Children), list.TotalCount, nil
+	})
+}
@@ -0,0 +1,19 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/tsujio/x-base/api/schemas"
+)
+
+func (c *Client) GetJob(ctx context.Context, id uuid.UUID) (*schemas.Job, error) {
+	var output schemas.Job
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/jobs/%s", id), nil, nil, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
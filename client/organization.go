@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/tsujio/x-base/api/schemas"
+)
+
+func (c *Client) CreateOrganization(ctx context.Context, input *schemas.CreateOrganizationInput) (*schemas.Organization, error) {
+	var output schemas.Organization
+	if err := c.do(ctx, http.MethodPost, "/organizations", nil, input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) GetOrganization(ctx context.Context, id uuid.UUID) (*schemas.Organization, error) {
+	var output schemas.Organization
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/organizations/%s", id), nil, nil, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) UpdateOrganization(ctx context.Context, id uuid.UUID, input *schemas.UpdateOrganizationInput) (*schemas.Organization, error) {
+	var output schemas.Organization
+	if err := c.do(ctx, http.MethodPatch, fmt.Sprintf("/organizations/%s", id), nil, input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/organizations/%s", id), nil, nil, nil)
+}
+
+func (c *Client) GetOrganizationList(ctx context.Context, opts *ListOptions) (*schemas.OrganizationList, error) {
+	var output schemas.OrganizationList
+	if err := c.do(ctx, http.MethodGet, "/organizations", opts.query(), nil, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// EachOrganization calls fn for each organization, fetching pages from opts.Page.
+// fn may return ErrStop to stop the iteration.
+func (c *Client) EachOrganization(ctx context.Context, opts *ListOptions, fn func(*schemas.Organization) error) error {
+	return pages(opts, func(opts *ListOptions) (int, int64, error) {
+		list, err := c.GetOrganizationList(ctx, opts)
+		if err != nil {
+			return 0, 0, err
+		}
+		for i := range list.Organizations {
+			if err := fn(&list.Organizations[i]); err != nil {
+				return 0, 0, err
+			}
+		}
+		return len(list.Organizations), list.TotalCount, nil
+	})
+}
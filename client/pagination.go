@@ -0,0 +1,71 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrStop stops the iteration of Each* methods without an error.
+var ErrStop = xerrors.New("Stop iteration")
+
+// ListOptions are the parameters of list endpoints. Zero values are not sent.
+type ListOptions struct {
+	Page     int
+	PageSize int
+	// Sort is like "createdAt:desc,property.name:asc".
+	Sort string
+	// Properties limits the returned properties to the keys.
+	Properties []string
+}
+
+func (opts *ListOptions) query() url.Values {
+	q := url.Values{}
+	if opts == nil {
+		return q
+	}
+	if opts.Page > 0 {
+		q.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.PageSize > 0 {
+		q.Set("pageSize", strconv.Itoa(opts.PageSize))
+	}
+	if opts.Sort != "" {
+		q.Set("sort", opts.Sort)
+	}
+	if len(opts.Properties) > 0 {
+		q.Set("properties", strings.Join(opts.Properties, ","))
+	}
+	return q
+}
+
+// pages calls fetch for each page from opts.Page until all items are fetched.
+// fetch returns the number of items in the page and the total count.
+func pages(opts *ListOptions, fetch func(opts *ListOptions) (int, int64, error)) error {
+	o := ListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Page <= 0 {
+		o.Page = 1
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = 100
+	}
+
+	for fetched := int64((o.Page - 1) * o.PageSize); ; o.Page++ {
+		n, totalCount, err := fetch(&o)
+		if err != nil {
+			if xerrors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+		fetched += int64(n)
+		if n == 0 || fetched >= totalCount {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Package query builds queries of the record query API, mirroring the query schemas of the server.
+//
+//	q := query.Select(query.Metadata("id"), query.Property("name")).
+//		Where(query.Property("age").Ge(query.Value(20))).
+//		OrderBy(query.Property("name"), query.Desc).
+//		Limit(100)
+package query
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Asc  = "asc"
+	Desc = "desc"
+)
+
+// Expr is an expression such as a column reference, a value or an operation.
+type Expr struct {
+	v interface{}
+}
+
+func (e Expr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.v)
+}
+
+// Metadata refers to the metadata of records, "id" or "createdAt".
+func Metadata(name string) Expr {
+	return Expr{map[string]interface{}{"metadata": name}}
+}
+
+// Property refers to the property of records, which is stored apart from the columns.
+func Property(key string) Expr {
+	return Expr{map[string]interface{}{"property": key}}
+}
+
+func Column(id uuid.UUID) Expr {
+	return Expr{map[string]interface{}{"column": id}}
+}
+
+func Value(v interface{}) Expr {
+	return Expr{map[string]interface{}{"value": v}}
+}
+
+// Count counts the records, or the non-null values of the args.
+func Count(args ...Expr) Expr {
+	f := map[string]interface{}{"func": "count"}
+	if len(args) > 0 {
+		f["args"] = args
+	}
+	return Expr{f}
+}
+
+func unaryOp(op string, e Expr) Expr {
+	return Expr{map[string]interface{}{op: e}}
+}
+
+func binOp(op string, e1, e2 Expr) Expr {
+	return Expr{map[string]interface{}{op: []Expr{e1, e2}}}
+}
+
+func Eq(e1, e2 Expr) Expr   { return binOp("eq", e1, e2) }
+func Ne(e1, e2 Expr) Expr   { return binOp("ne", e1, e2) }
+func Gt(e1, e2 Expr) Expr   { return binOp("gt", e1, e2) }
+func Ge(e1, e2 Expr) Expr   { return binOp("ge", e1, e2) }
+func Lt(e1, e2 Expr) Expr   { return binOp("lt", e1, e2) }
+func Le(e1, e2 Expr) Expr   { return binOp("le", e1, e2) }
+func Like(e1, e2 Expr) Expr { return binOp("like", e1, e2) }
+func Add(e1, e2 Expr) Expr  { return binOp("add", e1, e2) }
+func Sub(e1, e2 Expr) Expr  { return binOp("sub", e1, e2) }
+func Mul(e1, e2 Expr) Expr  { return binOp("mul", e1, e2) }
+func Div(e1, e2 Expr) Expr  { return binOp("div", e1, e2) }
+func Mod(e1, e2 Expr) Expr  { return binOp("mod", e1, e2) }
+func IsNull(e Expr) Expr    { return unaryOp("isNull", e) }
+func Not(e Expr) Expr       { return unaryOp("not", e) }
+func Neg(e Expr) Expr       { return unaryOp("neg", e) }
+
+// And joins the conditions. It returns the condition itself if only one is given.
+func And(e Expr, es ...Expr) Expr {
+	for _, e2 := range es {
+		e = binOp("and", e, e2)
+	}
+	return e
+}
+
+// Or joins the conditions. It returns the condition itself if only one is given.
+func Or(e Expr, es ...Expr) Expr {
+	for _, e2 := range es {
+		e = binOp("or", e, e2)
+	}
+	return e
+}
+
+func (e Expr) Eq(e2 Expr) Expr   { return Eq(e, e2) }
+func (e Expr) Ne(e2 Expr) Expr   { return Ne(e, e2) }
+func (e Expr) Gt(e2 Expr) Expr   { return Gt(e, e2) }
+func (e Expr) Ge(e2 Expr) Expr   { return Ge(e, e2) }
+func (e Expr) Lt(e2 Expr) Expr   { return Lt(e, e2) }
+func (e Expr) Le(e2 Expr) Expr   { return Le(e, e2) }
+func (e Expr) Like(e2 Expr) Expr { return Like(e, e2) }
+func (e Expr) Add(e2 Expr) Expr  { return Add(e, e2) }
+func (e Expr) Sub(e2 Expr) Expr  { return Sub(e, e2) }
+func (e Expr) Mul(e2 Expr) Expr  { return Mul(e, e2) }
+func (e Expr) Div(e2 Expr) Expr  { return Div(e, e2) }
+func (e Expr) Mod(e2 Expr) Expr  { return Mod(e, e2) }
+func (e Expr) And(e2 Expr) Expr  { return And(e, e2) }
+func (e Expr) Or(e2 Expr) Expr   { return Or(e, e2) }
+func (e Expr) IsNull() Expr      { return IsNull(e) }
+func (e Expr) Not() Expr         { return Not(e) }
+func (e Expr) Neg() Expr         { return Neg(e) }
+
+type sortKey struct {
+	Key   Expr   `json:"key"`
+	Order string `json:"order"`
+}
+
+type SelectQuery struct {
+	columns []Expr
+	where   *Expr
+	orderBy []sortKey
+	offset  *int
+	limit   *int
+}
+
+// Select selects the columns of records. The server defaults to 10 records ordered by createdAt and id.
+func Select(columns ...Expr) *SelectQuery {
+	return &SelectQuery{columns: columns}
+}
+
+func (q *SelectQuery) Where(cond Expr) *SelectQuery {
+	q.where = &cond
+	return q
+}
+
+// OrderBy appends the sort key. order is Asc or Desc.
+func (q *SelectQuery) OrderBy(key Expr, order string) *SelectQuery {
+	q.orderBy = append(q.orderBy, sortKey{Key: key, Order: order})
+	return q
+}
+
+func (q *SelectQuery) Offset(offset int) *SelectQuery {
+	q.offset = &offset
+	return q
+}
+
+func (q *SelectQuery) Limit(limit int) *SelectQuery {
+	q.limit = &limit
+	return q
+}
+
+// Page returns the offset and the limit of the query, with the defaults of the server if not set.
+func (q *SelectQuery) Page() (int, int) {
+	offset, limit := 0, 10
+	if q.offset != nil {
+		offset = *q.offset
+	}
+	if q.limit != nil {
+		limit = *q.limit
+	}
+	return offset, limit
+}
+
+func (q *SelectQuery) MarshalJSON() ([]byte, error) {
+	s := map[string]interface{}{"columns": exprs(q.columns)}
+	if q.where != nil {
+		s["where"] = q.where
+	}
+	if len(q.orderBy) > 0 {
+		s["orderBy"] = q.orderBy
+	}
+	if q.offset != nil {
+		s["offset"] = *q.offset
+	}
+	if q.limit != nil {
+		s["limit"] = *q.limit
+	}
+	return json.Marshal(map[string]interface{}{"select": s})
+}
+
+type InsertQuery struct {
+	columns []Expr
+	values  [][]Expr
+}
+
+// Insert inserts records into the columns, given by Column or Property.
+func Insert(columns ...Expr) *InsertQuery {
+	return &InsertQuery{columns: columns}
+}
+
+// Values appends a record. The values are in the order of the columns.
+func (q *InsertQuery) Values(values ...interface{}) *InsertQuery {
+	record := []Expr{}
+	for _, v := range values {
+		record = append(record, Value(v))
+	}
+	q.values = append(q.values, record)
+	return q
+}
+
+func (q *InsertQuery) MarshalJSON() ([]byte, error) {
+	values := q.values
+	if values == nil {
+		values = [][]Expr{}
+	}
+	return json.Marshal(map[string]interface{}{
+		"insert": map[string]interface{}{
+			"columns": exprs(q.columns),
+			"values":  values,
+		},
+	})
+}
+
+type updateSet struct {
+	To    Expr `json:"to"`
+	Value Expr `json:"value"`
+}
+
+type UpdateQuery struct {
+	set   []updateSet
+	where Expr
+}
+
+// Update updates the records matching the condition.
+func Update(where Expr) *UpdateQuery {
+	return &UpdateQuery{where: where}
+}
+
+// Set sets the value to the column, given by Column or Property.
+func (q *UpdateQuery) Set(to, value Expr) *UpdateQuery {
+	q.set = append(q.set, updateSet{To: to, Value: value})
+	return q
+}
+
+func (q *UpdateQuery) MarshalJSON() ([]byte, error) {
+	set := q.set
+	if set == nil {
+		set = []updateSet{}
+	}
+	return json.Marshal(map[string]interface{}{
+		"update": map[string]interface{}{
+			"set":   set,
+			"where": q.where,
+		},
+	})
+}
+
+type DeleteQuery struct {
+	where Expr
+}
+
+// Delete deletes the records matching the condition.
+func Delete(where Expr) *DeleteQuery {
+	return &DeleteQuery{where: where}
+}
+
+func (q *DeleteQuery) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"delete": map[string]interface{}{
+			"where": q.where,
+		},
+	})
+}
+
+func exprs(es []Expr) []Expr {
+	if es == nil {
+		return []Expr{}
+	}
+	return es
+}
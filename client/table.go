@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/client/query"
+)
+
+func (c *Client) CreateTable(ctx context.Context, input *schemas.CreateTableInput) (*schemas.Table, error) {
+	var output schemas.Table
+	if err := c.do(ctx, http.MethodPost, "/tables", nil, input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) GetTable(ctx context.Context, id uuid.UUID) (*schemas.Table, error) {
+	var output schemas.Table
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/tables/%s", id), nil, nil, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) UpdateTable(ctx context.Context, id uuid.UUID, input *schemas.UpdateTableInput) (*schemas.Table, error) {
+	var output schemas.Table
+	if err := c.do(ctx, http.MethodPatch, fmt.Sprintf("/tables/%s", id), nil, input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) DeleteTable(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/tables/%s", id), nil, nil, nil)
+}
+
+func (c *Client) CreateColumn(ctx context.Context, tableID uuid.UUID, input *schemas.CreateColumnInput) (*schemas.Column, error) {
+	var output schemas.Column
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/tables/%s/columns", tableID), nil, input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) GetColumn(ctx context.Context, tableID, columnID uuid.UUID) (*schemas.Column, error) {
+	var output schemas.Column
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/tables/%s/columns/%s", tableID, columnID), nil, nil, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) UpdateColumn(ctx context.Context, tableID, columnID uuid.UUID, input *schemas.UpdateColumnInput) (*schemas.Column, error) {
+	var output schemas.Column
+	if err := c.do(ctx, http.MethodPatch, fmt.Sprintf("/tables/%s/columns/%s", tableID, columnID), nil, input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// DeleteColumn deletes the column and returns the job purging its data.
+func (c *Client) DeleteColumn(ctx context.Context, tableID, columnID uuid.UUID) (*schemas.Job, error) {
+	var output schemas.Job
+	if err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/tables/%s/columns/%s", tableID, columnID), nil, nil, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) ConvertColumn(ctx context.Context, tableID, columnID uuid.UUID, input *schemas.ConvertColumnInput) (*schemas.ColumnConversionResult, error) {
+	var output schemas.ColumnConversionResult
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/tables/%s/columns/%s/convert", tableID, columnID), nil, input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) ReorderColumns(ctx context.Context, tableID uuid.UUID, order []uuid.UUID) (*schemas.ColumnList, error) {
+	var output schemas.ColumnList
+	input := schemas.ReorderColumnInput{Order: order}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/tables/%s/columns/reorder", tableID), nil, &input, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) Select(ctx context.Context, tableID uuid.UUID, q *query.SelectQuery) (*schemas.SelectQueryResult, error) {
+	var output schemas.SelectQueryResult
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/tables/%s/query", tableID), nil, q, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// EachRecord calls fn for each record selected by the query, fetching pages of the query limit from its offset.
+// The offset of the query is advanced while iterating.
+func (c *Client) EachRecord(ctx context.Context, tableID uuid.UUID, q *query.SelectQuery, fn func([]interface{}) error) error {
+	offset, limit := q.Page()
+	for {
+		result, err := c.Select(ctx, tableID, q.Offset(offset).Limit(limit))
+		if err != nil {
+			return err
+		}
+		for _, record := range result.Records {
+			if err := fn(record); err != nil {
+				if xerrors.Is(err, ErrStop) {
+					return nil
+				}
+				return err
+			}
+		}
+		if limit <= 0 || len(result.Records) < limit {
+			return nil
+		}
+		offset += len(result.Records)
+	}
+}
+
+func (c *Client) Insert(ctx context.Context, tableID uuid.UUID, q *query.InsertQuery) (*schemas.InsertQueryResult, error) {
+	var output schemas.InsertQueryResult
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/tables/%s/query", tableID), nil, q, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (c *Client) Update(ctx context.Context, tableID uuid.UUID, q *query.UpdateQuery) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/tables/%s/query", tableID), nil, q, nil)
+}
+
+func (c *Client) Delete(ctx context.Context, tableID uuid.UUID, q *query.DeleteQuery) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/tables/%s/query", tableID), nil, q, nil)
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/databases"
+	"github.com/tsujio/x-base/logging"
+)
+
+// duration is a time.Duration written like "30s" in config files.
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("Invalid duration: %s", b)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+// Config is loaded from the config file, environment variables and flags, later ones taking precedence.
+type Config struct {
+	MigrationsDir string `json:"migrationsDir"`
+	DB            struct {
+		Host     string `json:"host"`
+		Port     int    `json:"port"`
+		User     string `json:"user"`
+		Password string `json:"password"`
+		Name     string `json:"name"`
+		Type     string `json:"type"`
+		// Replicas are the read replicas, connected with the user, password and name of the primary.
+		Replicas []dbReplica `json:"replicas"`
+	} `json:"db"`
+	Server struct {
+		Host            string   `json:"host"`
+		Port            int      `json:"port"`
+		ShutdownTimeout duration `json:"shutdownTimeout"`
+	} `json:"server"`
+	Log struct {
+		Format string `json:"format"`
+		Level  string `json:"level"`
+	} `json:"log"`
+	Auth struct {
+		Mode        string `json:"mode"`
+		AdminAPIKey string `json:"adminApiKey"`
+		JWT         struct {
+			JWKS              string `json:"jwks"`
+			Issuer            string `json:"issuer"`
+			Audience          string `json:"audience"`
+			OrganizationClaim string `json:"organizationClaim"`
+			RoleClaim         string `json:"roleClaim"`
+		} `json:"jwt"`
+	} `json:"auth"`
+	RateLimit struct {
+		OrganizationRPS   float64 `json:"organizationRps"`
+		OrganizationBurst int     `json:"organizationBurst"`
+		KeyRPS            float64 `json:"keyRps"`
+		KeyBurst          int     `json:"keyBurst"`
+	} `json:"rateLimit"`
+	Quota struct {
+		MaxTables          int64 `json:"maxTables"`
+		MaxColumnsPerTable int64 `json:"maxColumnsPerTable"`
+		MaxRecordsPerTable int64 `json:"maxRecordsPerTable"`
+		MaxStorageBytes    int64 `json:"maxStorageBytes"`
+	} `json:"quota"`
+	Idempotency struct {
+		Retention duration `json:"retention"`
+		Lease     duration `json:"lease"`
+	} `json:"idempotency"`
+	Timeout struct {
+		Default duration            `json:"default"`
+		Routes  map[string]duration `json:"routes"`
+	} `json:"timeout"`
+	Webhook struct {
+		AllowPrivateNetworks bool `json:"allowPrivateNetworks"`
+	} `json:"webhook"`
+}
+
+type dbReplica struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+func defaultConfig() *Config {
+	var conf Config
+	conf.MigrationsDir = "migrations"
+	conf.Server.Port = 8000
+	conf.Log.Format = "json"
+	conf.Auth.Mode = auth.ModeAPIKey
+	return &conf
+}
+
+// setting is a config value which can be given by a flag and an environment variable.
+type setting struct {
+	flag, env, usage string
+	set              func(string) error
+}
+
+func stringSetting(p *string) func(string) error {
+	return func(s string) error {
+		*p = s
+		return nil
+	}
+}
+
+func intSetting(p *int) func(string) error {
+	return func(s string) error {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		*p = v
+		return nil
+	}
+}
+
+func int64Setting(p *int64) func(string) error {
+	return func(s string) error {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		*p = v
+		return nil
+	}
+}
+
+func floatSetting(p *float64) func(string) error {
+	return func(s string) error {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		*p = v
+		return nil
+	}
+}
+
+func boolSetting(p *bool) func(string) error {
+	return func(s string) error {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		*p = v
+		return nil
+	}
+}
+
+func durationSetting(p *duration) func(string) error {
+	return func(s string) error {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*p = duration(v)
+		return nil
+	}
+}
+
+// dbReplicasSetting parses "HOST[:PORT],..." like "replica1:3306,replica2". The port of the primary is used if omitted.
+func dbReplicasSetting(p *[]dbReplica) func(string) error {
+	return func(s string) error {
+		var replicas []dbReplica
+		for _, item := range strings.Split(s, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			var r dbReplica
+			if i := strings.LastIndex(item, ":"); i >= 0 {
+				port, err := strconv.Atoi(item[i+1:])
+				if err != nil {
+					return fmt.Errorf("Invalid port: %s", item)
+				}
+				r.Host, r.Port = item[:i], port
+			} else {
+				r.Host = item
+			}
+			replicas = append(replicas, r)
+		}
+		*p = replicas
+		return nil
+	}
+}
+
+// routeTimeoutsSetting parses "ROUTE=DURATION,..." like "/tables/{tableID}/query=1m".
+func routeTimeoutsSetting(p *map[string]duration) func(string) error {
+	return func(s string) error {
+		timeouts := map[string]duration{}
+		for _, item := range strings.Split(s, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			i := strings.LastIndex(item, "=")
+			if i < 0 {
+				return fmt.Errorf("Invalid item: %s", item)
+			}
+			d, err := time.ParseDuration(item[i+1:])
+			if err != nil {
+				return fmt.Errorf("Invalid duration: %s", item)
+			}
+			timeouts[item[:i]] = duration(d)
+		}
+		*p = timeouts
+		return nil
+	}
+}
+
+func (conf *Config) settings() []setting {
+	return []setting{
+		{"migrations-dir", "MIGRATIONS_DIR", "directory of migration files", stringSetting(&conf.MigrationsDir)},
+		{"db-host", "DB_HOST", "database host, or instance connection name if db-type is cloudsql", stringSetting(&conf.DB.Host)},
+		{"db-port", "DB_PORT", "database port", intSetting(&conf.DB.Port)},
+		{"db-user", "DB_USER", "database user", stringSetting(&conf.DB.User)},
+		{"db-password", "DB_PASSWORD", "database password", stringSetting(&conf.DB.Password)},
+		{"db-name", "DB_NAME", "database name, or path of the database file if db-type is sqlite", stringSetting(&conf.DB.Name)},
+		{"db-type", "DB_TYPE", "database type (cloudsql, sqlite, postgres or empty for mysql)", stringSetting(&conf.DB.Type)},
+		{"db-replicas", "DB_REPLICAS", `read replicas like "host1:3306,host2:3306", which serve GET requests and select queries`, dbReplicasSetting(&conf.DB.Replicas)},
+		{"host", "HOST", "host to listen on", stringSetting(&conf.Server.Host)},
+		{"port", "PORT", "port to listen on", intSetting(&conf.Server.Port)},
+		{"shutdown-timeout", "SHUTDOWN_TIMEOUT", "time to wait for in-flight requests on shutdown", durationSetting(&conf.Server.ShutdownTimeout)},
+		{"log-format", "LOG_FORMAT", "log format (json or text)", stringSetting(&conf.Log.Format)},
+		{"log-level", "LOG_LEVEL", "log level (debug, info, warning or error)", stringSetting(&conf.Log.Level)},
+		{"auth-mode", "AUTH_MODE", "authentication mode (apikey, jwt or none)", stringSetting(&conf.Auth.Mode)},
+		{"admin-api-key", "ADMIN_API_KEY", "administrator api key", stringSetting(&conf.Auth.AdminAPIKey)},
+		{"jwt-jwks", "JWT_JWKS", "JWKS file path or URL", stringSetting(&conf.Auth.JWT.JWKS)},
+		{"jwt-issuer", "JWT_ISSUER", "JWT issuer", stringSetting(&conf.Auth.JWT.Issuer)},
+		{"jwt-audience", "JWT_AUDIENCE", "JWT audience", stringSetting(&conf.Auth.JWT.Audience)},
+		{"jwt-organization-claim", "JWT_ORGANIZATION_CLAIM", "JWT claim of the organization id", stringSetting(&conf.Auth.JWT.OrganizationClaim)},
+		{"jwt-role-claim", "JWT_ROLE_CLAIM", "JWT claim of the role", stringSetting(&conf.Auth.JWT.RoleClaim)},
+		{"rate-limit-organization-rps", "RATE_LIMIT_ORGANIZATION_RPS", "requests per second per organization", floatSetting(&conf.RateLimit.OrganizationRPS)},
+		{"rate-limit-organization-burst", "RATE_LIMIT_ORGANIZATION_BURST", "burst of requests per organization", intSetting(&conf.RateLimit.OrganizationBurst)},
+		{"rate-limit-key-rps", "RATE_LIMIT_KEY_RPS", "requests per second per api key or JWT subject", floatSetting(&conf.RateLimit.KeyRPS)},
+		{"rate-limit-key-burst", "RATE_LIMIT_KEY_BURST", "burst of requests per api key or JWT subject", intSetting(&conf.RateLimit.KeyBurst)},
+		{"quota-max-tables", "QUOTA_MAX_TABLES", "max tables per organization", int64Setting(&conf.Quota.MaxTables)},
+		{"quota-max-columns-per-table", "QUOTA_MAX_COLUMNS_PER_TABLE", "max columns per table", int64Setting(&conf.Quota.MaxColumnsPerTable)},
+		{"quota-max-records-per-table", "QUOTA_MAX_RECORDS_PER_TABLE", "max records per table", int64Setting(&conf.Quota.MaxRecordsPerTable)},
+		{"quota-max-storage-bytes", "QUOTA_MAX_STORAGE_BYTES", "max size of records per organization, checked per server instance", int64Setting(&conf.Quota.MaxStorageBytes)},
+		{"idempotency-key-retention", "IDEMPOTENCY_KEY_RETENTION", "how long responses to idempotency keys are stored", durationSetting(&conf.Idempotency.Retention)},
+		{"idempotency-key-lease", "IDEMPOTENCY_KEY_LEASE", "how long idempotency keys are reserved for requests in progress", durationSetting(&conf.Idempotency.Lease)},
+		{"request-timeout", "REQUEST_TIMEOUT", "default request timeout", durationSetting(&conf.Timeout.Default)},
+		{"request-timeout-routes", "REQUEST_TIMEOUT_ROUTES", `request timeouts per route like "/tables/{tableID}/query=1m,..."`, routeTimeoutsSetting(&conf.Timeout.Routes)},
+		{"webhook-allow-private-networks", "WEBHOOK_ALLOW_PRIVATE_NETWORKS", "allow webhooks to loopback, private and link-local addresses", boolSetting(&conf.Webhook.AllowPrivateNetworks)},
+	}
+}
+
+// loadConfig parses the flags of the subcommand and loads the config.
+// The config file is given by the -config flag or CONFIG_FILE.
+func loadConfig(fs *flag.FlagSet, args []string) (*Config, error) {
+	conf := defaultConfig()
+	settings := conf.settings()
+
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "config file (YAML)")
+	flagValues := map[string]string{}
+	for _, s := range settings {
+		name := s.flag
+		fs.Func(name, fmt.Sprintf("%s (env %s)", s.usage, s.env), func(v string) error {
+			flagValues[name] = v
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		b, err := ioutil.ReadFile(*configFile)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(b, conf); err != nil {
+			return nil, xerrors.Errorf("Failed to parse config file: %w", err)
+		}
+	}
+	for _, s := range settings {
+		if v, exists := os.LookupEnv(s.env); exists && v != "" {
+			if err := s.set(v); err != nil {
+				return nil, xerrors.Errorf("Invalid %s: %w", s.env, err)
+			}
+		}
+	}
+	for _, s := range settings {
+		if v, exists := flagValues[s.flag]; exists {
+			if err := s.set(v); err != nil {
+				return nil, xerrors.Errorf("Invalid -%s: %w", s.flag, err)
+			}
+		}
+	}
+
+	return conf, nil
+}
+
+func (conf *Config) dbConfig() *databases.DBConfig {
+	c := &databases.DBConfig{
+		Host:     conf.DB.Host,
+		Port:     conf.DB.Port,
+		User:     conf.DB.User,
+		Password: conf.DB.Password,
+		DBName:   conf.DB.Name,
+		DBType:   conf.DB.Type,
+	}
+	for _, r := range conf.DB.Replicas {
+		c.Replicas = append(c.Replicas, databases.DBConfig{
+			Host: r.Host,
+			Port: r.Port,
+		})
+	}
+	return c
+}
+
+func (conf *Config) apiConfig() *api.Config {
+	c := &api.Config{}
+	c.Auth.Mode = conf.Auth.Mode
+	c.Auth.AdminAPIKey = conf.Auth.AdminAPIKey
+	c.Auth.JWT.JWKS = conf.Auth.JWT.JWKS
+	c.Auth.JWT.Issuer = conf.Auth.JWT.Issuer
+	c.Auth.JWT.Audience = conf.Auth.JWT.Audience
+	c.Auth.JWT.OrganizationClaim = conf.Auth.JWT.OrganizationClaim
+	c.Auth.JWT.RoleClaim = conf.Auth.JWT.RoleClaim
+	c.RateLimit.OrganizationRate = conf.RateLimit.OrganizationRPS
+	c.RateLimit.OrganizationBurst = conf.RateLimit.OrganizationBurst
+	c.RateLimit.KeyRate = conf.RateLimit.KeyRPS
+	c.RateLimit.KeyBurst = conf.RateLimit.KeyBurst
+	c.Quota.MaxTables = conf.Quota.MaxTables
+	c.Quota.MaxColumnsPerTable = conf.Quota.MaxColumnsPerTable
+	c.Quota.MaxRecordsPerTable = conf.Quota.MaxRecordsPerTable
+	c.Quota.MaxStorageBytes = conf.Quota.MaxStorageBytes
+	c.Idempotency.Retention = time.Duration(conf.Idempotency.Retention)
+	c.Idempotency.Lease = time.Duration(conf.Idempotency.Lease)
+	c.Timeout.Default = time.Duration(conf.Timeout.Default)
+	if conf.Timeout.Routes != nil {
+		c.Timeout.Routes = map[string]time.Duration{}
+		for route, d := range conf.Timeout.Routes {
+			c.Timeout.Routes[route] = time.Duration(d)
+		}
+	}
+	c.Webhook.AllowPrivateNetworks = conf.Webhook.AllowPrivateNetworks
+	c.ShutdownTimeout = time.Duration(conf.Server.ShutdownTimeout)
+	return c
+}
+
+// setUpLogger sets the logger of the config writing JSON logs to out.
+func (conf *Config) setUpLogger(out io.Writer) error {
+	level, err := logging.ParseLevel(conf.Log.Level)
+	if err != nil {
+		return err
+	}
+	switch conf.Log.Format {
+	case "text":
+		logging.SetLogger(logging.DefaultLogger{})
+	case "", "json":
+		logging.SetLogger(logging.NewJSONLogger(out, level))
+	default:
+		return fmt.Errorf("Invalid log format: %s", conf.Log.Format)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv unsets the environment variables of the settings during the test.
+func clearEnv(t *testing.T) {
+	names := []string{"CONFIG_FILE"}
+	for _, s := range defaultConfig().settings() {
+		names = append(names, s.env)
+	}
+	for _, name := range names {
+		if v, exists := os.LookupEnv(name); exists {
+			name := name
+			t.Cleanup(func() { os.Setenv(name, v) })
+		} else {
+			name := name
+			t.Cleanup(func() { os.Unsetenv(name) })
+		}
+		os.Unsetenv(name)
+	}
+}
+
+func setenv(t *testing.T, name, value string) {
+	if v, exists := os.LookupEnv(name); exists {
+		t.Cleanup(func() { os.Setenv(name, v) })
+	} else {
+		t.Cleanup(func() { os.Unsetenv(name) })
+	}
+	os.Setenv(name, value)
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// captureStdout returns what f writes to stdout.
+func captureStdout(t *testing.T, f func() error) (string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	var out bytes.Buffer
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(&out, r)
+		close(copied)
+	}()
+
+	err = f()
+	w.Close()
+	<-copied
+	return out.String(), err
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	clearEnv(t)
+
+	conf, err := loadConfig(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if conf.MigrationsDir != "migrations" || conf.Server.Port != 8000 || conf.Log.Format != "json" || conf.Auth.Mode != "apikey" {
+		t.Errorf("Unexpected defaults: %+v", conf)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	clearEnv(t)
+
+	file := writeFile(t, "config.yaml", `
+db:
+  host: file-host
+  name: file-db
+  replicas:
+    - host: file-replica
+server:
+  port: 1000
+  shutdownTimeout: 10s
+log:
+  level: debug
+`)
+	setenv(t, "CONFIG_FILE", file)
+	setenv(t, "PORT", "2000")
+	setenv(t, "DB_HOST", "env-host")
+	setenv(t, "DB_REPLICAS", "env-replica1:3307,env-replica2")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	conf, err := loadConfig(fs, []string{"-port", "3000", "-shutdown-timeout", "1m", "arg"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// Flags over environment variables over the file
+	if conf.Server.Port != 3000 {
+		t.Errorf("Port mismatch: %d", conf.Server.Port)
+	}
+	if time.Duration(conf.Server.ShutdownTimeout) != time.Minute {
+		t.Errorf("Shutdown timeout mismatch: %v", time.Duration(conf.Server.ShutdownTimeout))
+	}
+	if conf.DB.Host != "env-host" {
+		t.Errorf("DB host mismatch: %s", conf.DB.Host)
+	}
+	if len(conf.DB.Replicas) != 2 ||
+		conf.DB.Replicas[0] != (dbReplica{Host: "env-replica1", Port: 3307}) ||
+		conf.DB.Replicas[1] != (dbReplica{Host: "env-replica2"}) {
+		t.Errorf("DB replicas mismatch: %+v", conf.DB.Replicas)
+	}
+	if conf.DB.Name != "file-db" || conf.Log.Level != "debug" {
+		t.Errorf("Values of the file are not loaded: %+v", conf)
+	}
+	if conf.Log.Format != "json" {
+		t.Errorf("Default is overwritten: %s", conf.Log.Format)
+	}
+	if fs.NArg() != 1 || fs.Arg(0) != "arg" {
+		t.Errorf("Arguments mismatch: %v", fs.Args())
+	}
+}
+
+func TestLoadConfigFileFlag(t *testing.T) {
+	clearEnv(t)
+
+	setenv(t, "CONFIG_FILE", writeFile(t, "env.yaml", "server:\n  port: 1000\n"))
+	file := writeFile(t, "flag.yaml", "server:\n  port: 2000\n")
+
+	conf, err := loadConfig(flag.NewFlagSet("test", flag.ContinueOnError), []string{"-config", file})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if conf.Server.Port != 2000 {
+		t.Errorf("Port mismatch: %d", conf.Server.Port)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	testCases := []struct {
+		title string
+		env   map[string]string
+		file  string
+		args  []string
+		err   string
+	}{
+		{
+			title: "Invalid environment variable",
+			env:   map[string]string{"PORT": "port"},
+			err:   "Invalid PORT",
+		},
+		{
+			title: "Invalid flag value",
+			args:  []string{"-request-timeout", "1 minute"},
+			err:   "Invalid -request-timeout",
+		},
+		{
+			title: "Invalid route timeouts",
+			args:  []string{"-request-timeout-routes", "/tables"},
+			err:   "Invalid -request-timeout-routes",
+		},
+		{
+			title: "Invalid file",
+			file:  "server: [",
+			err:   "Failed to parse config file",
+		},
+		{
+			title: "Invalid duration in file",
+			file:  "server:\n  shutdownTimeout: 10\n",
+			err:   "Failed to parse config file",
+		},
+		{
+			title: "Missing file",
+			args:  []string{"-config", "/nonexistent/config.yaml"},
+			err:   "Failed to read config file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tc.env {
+				setenv(t, k, v)
+			}
+			if tc.file != "" {
+				setenv(t, "CONFIG_FILE", writeFile(t, "config.yaml", tc.file))
+			}
+
+			_, err := loadConfig(flag.NewFlagSet("test", flag.ContinueOnError), tc.args)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Error mismatch: expected=%s, actual=%v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestAPIConfig(t *testing.T) {
+	clearEnv(t)
+
+	conf, err := loadConfig(flag.NewFlagSet("test", flag.ContinueOnError), []string{
+		"-request-timeout-routes", "/tables/{tableID}/query=1m, /tables/{tableID}/events=0s",
+		"-idempotency-key-lease", "30s",
+		"-quota-max-tables", "10",
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	c := conf.apiConfig()
+	if c.Timeout.Routes["/tables/{tableID}/query"] != time.Minute {
+		t.Errorf("Route timeout mismatch: %v", c.Timeout.Routes)
+	}
+	if d, exists := c.Timeout.Routes["/tables/{tableID}/events"]; !exists || d != 0 {
+		t.Errorf("Route timeout mismatch: %v", c.Timeout.Routes)
+	}
+	if c.Idempotency.Lease != 30*time.Second {
+		t.Errorf("Idempotency lease mismatch: %v", c.Idempotency.Lease)
+	}
+	if c.Quota.MaxTables != 10 {
+		t.Errorf("Quota mismatch: %d", c.Quota.MaxTables)
+	}
+}
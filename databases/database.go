@@ -2,17 +2,25 @@ package databases
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"golang.org/x/xerrors"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+
+// DBConfig is the connection config. DBType is "cloudsql", "sqlite", "postgres" or empty for MySQL.
+// DBName is the path of the database file for SQLite.
 type DBConfig struct {
 	Host     string
 	Port     int
@@ -20,8 +28,15 @@ type DBConfig struct {
 	DBName   string
 	Password string
 	DBType   string
+	// Replicas are the read replicas. Their empty fields are the same as the primary, and DBType is ignored.
+	Replicas []DBConfig
 }
 
+const (
+	DBTypeSQLite   = "sqlite"
+	DBTypePostgres = "postgres"
+)
+
 func getUrl(conf *DBConfig) string {
 	var endpoint string
 	if conf.DBType == "cloudsql" {
@@ -34,15 +49,43 @@ func getUrl(conf *DBConfig) string {
 		conf.User, conf.Password, endpoint, conf.DBName)
 }
 
+func getSQLitePath(conf *DBConfig) string {
+	// Wait for the lock of the other connections instead of failing, and take it at the beginning of transactions
+	// so that transactions reading before writing do not fail to upgrade the lock
+	return conf.DBName + "?_foreign_keys=1&_busy_timeout=10000&_journal_mode=WAL&_txlock=immediate"
+}
+
+func getPostgresUrl(conf *DBConfig) string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(conf.User, conf.Password),
+		Host:     fmt.Sprintf("%s:%d", conf.Host, conf.Port),
+		Path:     "/" + conf.DBName,
+		RawQuery: "sslmode=disable&timezone=UTC",
+	}
+	return u.String()
+}
+
+// MigrationsDir returns the directory of the migrations for the database,
+// which is the sqlite or postgres subdirectory for SQLite and PostgreSQL.
+func MigrationsDir(conf *DBConfig, migrationsDir string) string {
+	switch conf.DBType {
+	case DBTypeSQLite, DBTypePostgres:
+		return filepath.Join(migrationsDir, conf.DBType)
+	}
+	return migrationsDir
+}
+
 func Setup(conf *DBConfig, migrationsDir string) error {
 	// Run migrations
-	m, err := migrate.New("file://"+migrationsDir, "mysql://"+getUrl(conf))
+	m, err := NewMigrator(conf, migrationsDir)
 	if err != nil {
-		return xerrors.Errorf("Failed to initiate migration: %w", err)
+		return err
 	}
-	err = m.Up()
-	if err != nil && !xerrors.Is(err, migrate.ErrNoChange) {
-		return xerrors.Errorf("Failed to migrate db: %w", err)
+	defer m.Close()
+	err = m.Up(0)
+	if err != nil {
+		return err
 	}
 
 	// Check db connection
@@ -67,8 +110,18 @@ func Open(conf *DBConfig, lgr logger.Interface) (*gorm.DB, error) {
 		lgr = logger.Default
 	}
 
+	var dialector gorm.Dialector
+	switch conf.DBType {
+	case DBTypeSQLite:
+		dialector = sqlite.Open(getSQLitePath(conf))
+	case DBTypePostgres:
+		dialector = postgres.Open(getPostgresUrl(conf))
+	default:
+		dialector = mysql.Open(getUrl(conf))
+	}
+
 	// Open db
-	db, err := gorm.Open(mysql.Open(getUrl(conf)), &gorm.Config{
+	db, err := gorm.Open(dialector, &gorm.Config{
 		NowFunc: func() time.Time {
 			return time.Now().UTC().Truncate(time.Second)
 		},
@@ -78,5 +131,50 @@ func Open(conf *DBConfig, lgr logger.Interface) (*gorm.DB, error) {
 		return nil, xerrors.Errorf("Failed to open database: %w", err)
 	}
 
+	if len(conf.Replicas) > 0 {
+		if err := useReplicas(db, conf); err != nil {
+			return nil, xerrors.Errorf("Failed to open replicas: %w", err)
+		}
+	}
+
 	return db, nil
 }
+
+// MigrationVersion returns the version of the last applied migration and whether it failed halfway.
+func MigrationVersion(db *gorm.DB) (int64, bool, error) {
+	var rows []struct {
+		Version int64
+		Dirty   bool
+	}
+	err := db.Raw("SELECT version, dirty FROM schema_migrations").Scan(&rows).Error
+	if err != nil {
+		return 0, false, xerrors.Errorf("Failed to get migration version: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, false, nil
+	}
+	return rows[0].Version, rows[0].Dirty, nil
+}
+
+// LatestMigrationVersion returns the largest version of the migrations in the directory.
+func LatestMigrationVersion(migrationsDir string) (int64, error) {
+	files, err := ioutil.ReadDir(migrationsDir)
+	if err != nil {
+		return 0, xerrors.Errorf("Failed to read migrations directory: %w", err)
+	}
+	var latest int64
+	for _, f := range files {
+		m := migrationFilePattern.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return 0, xerrors.Errorf("Invalid migration file name %s: %w", f.Name(), err)
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,104 @@
+package databases
+
+import (
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"golang.org/x/xerrors"
+)
+
+// Migrator applies the migrations in a directory to the database.
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// NewMigrator returns the migrator of the migrations for the database in migrationsDir (see MigrationsDir).
+func NewMigrator(conf *DBConfig, migrationsDir string) (*Migrator, error) {
+	var url string
+	switch conf.DBType {
+	case DBTypeSQLite:
+		url = "sqlite3://" + getSQLitePath(conf)
+	case DBTypePostgres:
+		url = getPostgresUrl(conf)
+	default:
+		url = "mysql://" + getUrl(conf)
+	}
+	m, err := migrate.New("file://"+MigrationsDir(conf, migrationsDir), url)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to initiate migration: %w", err)
+	}
+	return &Migrator{m: m}, nil
+}
+
+func ignoreNoChange(err error) error {
+	if err != nil && !xerrors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Up applies the next n migrations, or all of them if n is zero.
+func (m *Migrator) Up(n int) error {
+	var err error
+	if n > 0 {
+		err = m.m.Steps(n)
+	} else {
+		err = m.m.Up()
+	}
+	if err := ignoreNoChange(err); err != nil {
+		return xerrors.Errorf("Failed to migrate up: %w", err)
+	}
+	return nil
+}
+
+// Down rolls back the last n migrations.
+func (m *Migrator) Down(n int) error {
+	if n <= 0 {
+		return xerrors.Errorf("Invalid number of migrations: %d", n)
+	}
+	if err := ignoreNoChange(m.m.Steps(-n)); err != nil {
+		return xerrors.Errorf("Failed to migrate down: %w", err)
+	}
+	return nil
+}
+
+// To migrates up or down to the version.
+func (m *Migrator) To(version uint) error {
+	if err := ignoreNoChange(m.m.Migrate(version)); err != nil {
+		return xerrors.Errorf("Failed to migrate to %d: %w", version, err)
+	}
+	return nil
+}
+
+// Force sets the version without running migrations, e.g. to clear the dirty state after fixing a failed migration by hand.
+func (m *Migrator) Force(version int) error {
+	if err := m.m.Force(version); err != nil {
+		return xerrors.Errorf("Failed to force version %d: %w", version, err)
+	}
+	return nil
+}
+
+// Version returns the current version and whether the last migration failed halfway. The version is zero if no migration is applied.
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil {
+		if xerrors.Is(err, migrate.ErrNilVersion) {
+			return 0, false, nil
+		}
+		return 0, false, xerrors.Errorf("Failed to get version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	if srcErr != nil {
+		return xerrors.Errorf("Failed to close source: %w", srcErr)
+	}
+	if dbErr != nil {
+		return xerrors.Errorf("Failed to close database: %w", dbErr)
+	}
+	return nil
+}
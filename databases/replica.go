@@ -0,0 +1,191 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/logging"
+)
+
+// replicaDownDuration is how long a failed replica is skipped.
+const replicaDownDuration = 30 * time.Second
+
+type readPreference int
+
+const (
+	readPreferenceReplica readPreference = iota + 1
+	readPreferencePrimary
+)
+
+type readPreferenceContextKey struct{}
+
+// ReadFromReplica returns the context whose queries are sent to a read replica, unless ReadFromPrimary is given to it.
+func ReadFromReplica(ctx context.Context) context.Context {
+	if p, _ := ctx.Value(readPreferenceContextKey{}).(readPreference); p == readPreferencePrimary {
+		return ctx
+	}
+	return context.WithValue(ctx, readPreferenceContextKey{}, readPreferenceReplica)
+}
+
+// ReadFromPrimary returns the context whose queries are sent to the primary, to read the writes just made.
+func ReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readPreferenceContextKey{}, readPreferencePrimary)
+}
+
+func readsFromReplica(ctx context.Context) bool {
+	p, _ := ctx.Value(readPreferenceContextKey{}).(readPreference)
+	return p == readPreferenceReplica
+}
+
+type replica struct {
+	db        *sql.DB
+	downUntil int64
+}
+
+func (r *replica) isDown() bool {
+	return time.Now().UnixNano() < atomic.LoadInt64(&r.downUntil)
+}
+
+func (r *replica) markDown() {
+	atomic.StoreInt64(&r.downUntil, time.Now().Add(replicaDownDuration).UnixNano())
+}
+
+// replicaPool sends the queries in the contexts given by ReadFromReplica to the read replicas in turn,
+// and the others to the primary. Transactions begin on the primary, so all the queries in them go to the primary.
+// A query failed on a replica is retried on the primary, and the replica is skipped for a while if the primary succeeds.
+type replicaPool struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     uint32
+}
+
+func (p *replicaPool) pick() *replica {
+	for range p.replicas {
+		r := p.replicas[int(atomic.AddUint32(&p.next, 1))%len(p.replicas)]
+		if !r.isDown() {
+			return r
+		}
+	}
+	return nil
+}
+
+func (p *replicaPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.primary.PrepareContext(ctx, query)
+}
+
+func (p *replicaPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.primary.ExecContext(ctx, query, args...)
+}
+
+func (p *replicaPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if !readsFromReplica(ctx) {
+		return p.primary.QueryContext(ctx, query, args...)
+	}
+	r := p.pick()
+	if r == nil {
+		return p.primary.QueryContext(ctx, query, args...)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err == nil || ctx.Err() != nil {
+		return rows, err
+	}
+	rows, primaryErr := p.primary.QueryContext(ctx, query, args...)
+	if primaryErr == nil {
+		logging.Warning(fmt.Sprintf("Read replica failed, falling back to the primary: %+v", err), nil)
+		r.markDown()
+	}
+	return rows, primaryErr
+}
+
+// QueryRowContext queries the primary, since the error of the row is not known until it is scanned.
+func (p *replicaPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.primary.QueryRowContext(ctx, query, args...)
+}
+
+func (p *replicaPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return p.primary.BeginTx(ctx, opts)
+}
+
+// GetDBConn returns the primary, e.g. for db.DB().
+func (p *replicaPool) GetDBConn() (*sql.DB, error) {
+	return p.primary, nil
+}
+
+// replicaConfig returns the config of the replica, whose empty fields are taken from the primary.
+func (conf *DBConfig) replicaConfig(r *DBConfig) *DBConfig {
+	c := *conf
+	c.Replicas = nil
+	if r.Host != "" {
+		c.Host = r.Host
+	}
+	if r.Port != 0 {
+		c.Port = r.Port
+	}
+	if r.User != "" {
+		c.User = r.User
+	}
+	if r.Password != "" {
+		c.Password = r.Password
+	}
+	if r.DBName != "" {
+		c.DBName = r.DBName
+	}
+	return &c
+}
+
+func openSQLDB(conf *DBConfig) (*sql.DB, error) {
+	switch conf.DBType {
+	case DBTypeSQLite:
+		return sql.Open("sqlite3", getSQLitePath(conf))
+	case DBTypePostgres:
+		return sql.Open("pgx", getPostgresUrl(conf))
+	default:
+		return sql.Open("mysql", getUrl(conf))
+	}
+}
+
+// useReplicas makes db send reads to the replicas of the config.
+// The replicas are connected lazily, so that an unavailable replica does not stop the startup.
+func useReplicas(db *gorm.DB, conf *DBConfig) error {
+	primary, err := db.DB()
+	if err != nil {
+		return xerrors.Errorf("Failed to get sqlDB object: %w", err)
+	}
+	pool := &replicaPool{primary: primary}
+	for i := range conf.Replicas {
+		sqlDB, err := openSQLDB(conf.replicaConfig(&conf.Replicas[i]))
+		if err != nil {
+			return xerrors.Errorf("Failed to open replica %d: %w", i, err)
+		}
+		pool.replicas = append(pool.replicas, &replica{db: sqlDB})
+	}
+	db.ConnPool = pool
+	db.Statement.ConnPool = pool
+	return nil
+}
+
+// Close closes the connections to the database and its replicas.
+func Close(db *gorm.DB) error {
+	if pool, ok := db.ConnPool.(*replicaPool); ok {
+		for _, r := range pool.replicas {
+			if err := r.db.Close(); err != nil {
+				return xerrors.Errorf("Failed to close replica: %w", err)
+			}
+		}
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return xerrors.Errorf("Failed to get sqlDB object: %w", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return xerrors.Errorf("Failed to close db: %w", err)
+	}
+	return nil
+}
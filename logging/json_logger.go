@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fields are passed as the payload to log structured data.
+// The "message" field is the message of the log line.
+type Fields map[string]interface{}
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarning, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("Invalid log level: %s", s)
+	}
+}
+
+// JSONLogger writes a JSON object per line with the time, the level, the message and the fields.
+// Lines for requests have the request id, the method and the path.
+type JSONLogger struct {
+	Level Level
+	out   io.Writer
+	mu    sync.Mutex
+}
+
+func NewJSONLogger(out io.Writer, level Level) *JSONLogger {
+	return &JSONLogger{
+		Level: level,
+		out:   out,
+	}
+}
+
+func (l *JSONLogger) Debug(payload interface{}, r *http.Request) {
+	l.write(LevelDebug, payload, r)
+}
+
+func (l *JSONLogger) Info(payload interface{}, r *http.Request) {
+	l.write(LevelInfo, payload, r)
+}
+
+func (l *JSONLogger) Warning(payload interface{}, r *http.Request) {
+	l.write(LevelWarning, payload, r)
+}
+
+func (l *JSONLogger) Error(payload interface{}, r *http.Request) {
+	l.write(LevelError, payload, r)
+}
+
+func (l *JSONLogger) Close() {}
+
+func (l *JSONLogger) write(level Level, payload interface{}, r *http.Request) {
+	if level < l.Level {
+		return
+	}
+
+	entry := map[string]interface{}{}
+	if r != nil {
+		entry["method"] = r.Method
+		entry["path"] = r.URL.Path
+		if id := RequestID(r); id != "" {
+			entry["requestId"] = id
+		}
+	}
+	switch p := payload.(type) {
+	case Fields:
+		for k, v := range p {
+			entry[k] = v
+		}
+	case string:
+		entry["message"] = p
+	case error:
+		entry["message"] = fmt.Sprintf("%+v", p)
+	default:
+		entry["message"] = fmt.Sprintf("%v", p)
+	}
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		b, _ = json.Marshal(map[string]interface{}{
+			"time":    entry["time"],
+			"level":   LevelError.String(),
+			"message": fmt.Sprintf("Failed to encode log entry: %v", err),
+		})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(append(b, '\n'))
+}
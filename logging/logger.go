@@ -55,3 +55,7 @@ func Warning(payload interface{}, r *http.Request) {
 func Error(payload interface{}, r *http.Request) {
 	logger.Error(payload, r)
 }
+
+func Close() {
+	logger.Close()
+}
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tsujio/x-base/api/utils/recorder"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// requestInfo is shared by the middleware and handlers of a request.
+type requestInfo struct {
+	id     string
+	mu     sync.Mutex
+	fields Fields
+}
+
+type contextKey struct{}
+
+// RequestID returns the id of the request given by Middleware.
+func RequestID(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	if info, ok := r.Context().Value(contextKey{}).(*requestInfo); ok {
+		return info.id
+	}
+	return ""
+}
+
+// AddFields adds the fields to the access log of the request.
+func AddFields(r *http.Request, fields Fields) {
+	info, ok := r.Context().Value(contextKey{}).(*requestInfo)
+	if !ok {
+		return
+	}
+	info.mu.Lock()
+	defer info.mu.Unlock()
+	for k, v := range fields {
+		info.fields[k] = v
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware gives an id to the request and writes the access log.
+// The id is taken from the X-Request-ID header if valid, and is returned in the same header.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(HeaderRequestID)
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+		info := &requestInfo{id: id, fields: Fields{}}
+		r = r.WithContext(context.WithValue(r.Context(), contextKey{}, info))
+		w.Header().Set(HeaderRequestID, id)
+
+		rec := recorder.New(w)
+		next.ServeHTTP(rec, r)
+
+		fields := Fields{
+			"message":   "access",
+			"status":    rec.Status(),
+			"latencyMs": float64(time.Since(start).Microseconds()) / 1000,
+		}
+		info.mu.Lock()
+		for k, v := range info.fields {
+			fields[k] = v
+		}
+		info.mu.Unlock()
+		Info(fields, r)
+	})
+}
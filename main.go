@@ -3,57 +3,62 @@ package main
 import (
 	"fmt"
 	"os"
-	"strconv"
-
-	"github.com/tsujio/x-base/api"
-	"github.com/tsujio/x-base/databases"
-	"github.com/tsujio/x-base/logging"
+	"strings"
 )
 
+const usage = `Usage: x-base [COMMAND] [FLAGS] [ARGS]
+
+Commands:
+  serve                       Run the api server (default)
+  migrate up [N]              Apply all or the next N migrations
+  migrate down [N]            Revert the last N migrations (default 1)
+  migrate to VERSION          Migrate up or down to VERSION
+  migrate force VERSION       Set the version without running migrations, to recover from a dirty state
+  migrate status              Show the current and latest migration versions
+  org create [-properties JSON]
+                              Create an organization
+  org list [-page N] [-page-size N]
+                              List organizations
+  org delete ID               Delete an organization
+
+Every command reads the config from the file given by -config (or CONFIG_FILE),
+environment variables and flags, later ones taking precedence.
+Run "x-base COMMAND -h" to see the flags.
+`
+
 func main() {
-	// Initialize logger
-	logging.SetLogger(logging.DefaultLogger{})
-
-	// Set up db
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbPort, _ := strconv.Atoi(os.Getenv("DB_PORT"))
-	dbHost := os.Getenv("DB_HOST")
-	dbName := os.Getenv("DB_NAME")
-	dbType := os.Getenv("DB_TYPE")
-	dbConfig := &databases.DBConfig{
-		User:     dbUser,
-		Password: dbPassword,
-		Host:     dbHost,
-		Port:     dbPort,
-		DBName:   dbName,
-		DBType:   dbType,
-	}
-	migrationsDir := os.Getenv("MIGRATIONS_DIR")
-	if migrationsDir == "" {
-		migrationsDir = "migrations"
-	}
-	err := databases.Setup(dbConfig, migrationsDir)
-	if err != nil {
-		logging.Error(fmt.Sprintf("Failed to set up db: %+v", err), nil)
-		return
-	}
-	db, err := databases.Open(dbConfig, nil)
-	if err != nil {
-		logging.Error(fmt.Sprintf("Failed to open db: %+v", err), nil)
-		return
-	}
+	args := os.Args[1:]
 
-	host := os.Getenv("HOST")
-	port, err := strconv.Atoi(os.Getenv("PORT"))
-	if err != nil {
-		port = 8000
+	// Serve by default for compatibility with the server run without arguments
+	command := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
 	}
 
-	// Run api
-	err = api.Run(host, port, db)
+	var err error
+	switch command {
+	case "serve":
+		err = serve(args)
+	case "migrate":
+		err = migrateCommand(args)
+	case "org":
+		err = orgCommand(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		err = fmt.Errorf("Unknown command: %s", command)
+	}
 	if err != nil {
-		logging.Error(fmt.Sprintf("Failed to run api: %+v", err), nil)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// subcommand splits args into the action and the rest.
+func subcommand(args []string, name string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%s needs an action; run \"x-base help\"", name)
+	}
+	return args[0], args[1:], nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/tsujio/x-base/databases"
+)
+
+type migrationStatus struct {
+	Version       uint  `json:"version"`
+	Dirty         bool  `json:"dirty"`
+	LatestVersion int64 `json:"latestVersion"`
+}
+
+func migrateCommand(args []string) (err error) {
+	action, args, err := subcommand(args, "migrate")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	conf, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := conf.setUpLogger(os.Stderr); err != nil {
+		return err
+	}
+
+	// Parse arguments before connecting to the database
+	var n int
+	switch action {
+	case "up", "down":
+		if fs.NArg() > 1 {
+			return fmt.Errorf("Usage: migrate %s [N]", action)
+		}
+		if fs.NArg() == 1 {
+			if n, err = strconv.Atoi(fs.Arg(0)); err != nil || n <= 0 {
+				return fmt.Errorf("Invalid number of migrations: %s", fs.Arg(0))
+			}
+		} else if action == "down" {
+			n = 1
+		}
+	case "to", "force":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("Usage: migrate %s VERSION", action)
+		}
+		if n, err = strconv.Atoi(fs.Arg(0)); err != nil || n < 0 {
+			return fmt.Errorf("Invalid version: %s", fs.Arg(0))
+		}
+		// Version 0 has no migration file to migrate to
+		if action == "to" && n == 0 {
+			return fmt.Errorf("Invalid version: 0 (use migrate down N to revert migrations)")
+		}
+	case "status":
+		if fs.NArg() > 0 {
+			return fmt.Errorf("Usage: migrate status")
+		}
+	default:
+		return fmt.Errorf("Unknown action: migrate %s", action)
+	}
+
+	m, err := databases.NewMigrator(conf.dbConfig(), conf.MigrationsDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := m.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	switch action {
+	case "up":
+		err = m.Up(n)
+	case "down":
+		err = m.Down(n)
+	case "to":
+		err = m.To(uint(n))
+	case "force":
+		err = m.Force(n)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Show the resulting status
+	var status migrationStatus
+	status.Version, status.Dirty, err = m.Version()
+	if err != nil {
+		return err
+	}
+	status.LatestVersion, err = databases.LatestMigrationVersion(databases.MigrationsDir(conf.dbConfig(), conf.MigrationsDir))
+	if err != nil {
+		return err
+	}
+	return printJSON(&status)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tsujio/x-base/databases"
+)
+
+// sqliteArgs returns the flags to use a new SQLite database.
+func sqliteArgs(t *testing.T) []string {
+	return []string{
+		"-db-type", "sqlite",
+		"-db-name", filepath.Join(t.TempDir(), "test.db"),
+		"-migrations-dir", "migrations",
+		"-log-format", "text",
+	}
+}
+
+func TestMigrateCommandArguments(t *testing.T) {
+	testCases := []struct {
+		args []string
+		err  string
+	}{
+		{args: nil, err: "migrate needs an action"},
+		{args: []string{"sideways"}, err: "Unknown action: migrate sideways"},
+		{args: []string{"up", "1", "2"}, err: "Usage: migrate up [N]"},
+		{args: []string{"up", "0"}, err: "Invalid number of migrations: 0"},
+		{args: []string{"down", "x"}, err: "Invalid number of migrations: x"},
+		{args: []string{"to"}, err: "Usage: migrate to VERSION"},
+		{args: []string{"to", "0"}, err: "Invalid version: 0 (use migrate down N to revert migrations)"},
+		{args: []string{"force", "v1"}, err: "Invalid version: v1"},
+		{args: []string{"status", "now"}, err: "Usage: migrate status"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			clearEnv(t)
+			err := migrateCommand(tc.args)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Error mismatch: expected=%s, actual=%v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	clearEnv(t)
+	args := sqliteArgs(t)
+
+	latest, err := databases.LatestMigrationVersion(filepath.Join("migrations", "sqlite"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	migrate := func(action ...string) migrationStatus {
+		out, err := captureStdout(t, func() error {
+			return migrateCommand(append(append([]string{action[0]}, args...), action[1:]...))
+		})
+		if err != nil {
+			t.Fatalf("[%v] %+v", action, err)
+		}
+		var status migrationStatus
+		if err := json.Unmarshal([]byte(out), &status); err != nil {
+			t.Fatalf("[%v] Invalid output: %s", action, out)
+		}
+		return status
+	}
+
+	if status := migrate("up"); int64(status.Version) != latest || status.Dirty || status.LatestVersion != latest {
+		t.Errorf("Unexpected status after up: %+v", status)
+	}
+	down := migrate("down")
+	if int64(down.Version) >= latest {
+		t.Errorf("Unexpected status after down: %+v", down)
+	}
+	if status := migrate("status"); status != down {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if status := migrate("up", "1"); int64(status.Version) != latest {
+		t.Errorf("Unexpected status after up 1: %+v", status)
+	}
+}
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id BINARY(16) NOT NULL,
+    organization_id BINARY(16) NOT NULL,
+    type CHAR(32) NOT NULL,
+    status CHAR(16) NOT NULL,
+    params JSON NOT NULL,
+    result JSON,
+    error TEXT,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    started_at DATETIME,
+    finished_at DATETIME,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_jobs_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_jobs_01 (status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1 @@
+ALTER TABLE jobs ADD COLUMN progress JSON AFTER params;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+    id BINARY(16) NOT NULL,
+    organization_id BINARY(16) NOT NULL,
+    table_id BINARY(16),
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events JSON NOT NULL,
+    properties JSON NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_webhooks_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_webhooks_02 FOREIGN KEY (table_id) REFERENCES tables(id) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
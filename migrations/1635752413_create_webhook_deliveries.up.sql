@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BINARY(16) NOT NULL,
+    webhook_id BINARY(16) NOT NULL,
+    event_id BINARY(16) NOT NULL,
+    event_type CHAR(32) NOT NULL,
+    payload JSON NOT NULL,
+    status CHAR(16) NOT NULL,
+    attempts INT UNSIGNED NOT NULL,
+    response_status INT,
+    error TEXT,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    delivered_at DATETIME,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_webhook_deliveries_01 FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_webhook_deliveries_01 (webhook_id, created_at),
+    INDEX idx_webhook_deliveries_02 (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
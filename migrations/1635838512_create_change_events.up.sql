@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS change_events (
+    seq BIGINT NOT NULL AUTO_INCREMENT,
+    id BINARY(16) NOT NULL,
+    organization_id BINARY(16) NOT NULL,
+    table_id BINARY(16),
+    folder_ids JSON NOT NULL,
+    type CHAR(32) NOT NULL,
+    payload JSON NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (seq),
+    CONSTRAINT fk_change_events_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    INDEX idx_change_events_01 (table_id, seq),
+    INDEX idx_change_events_02 (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
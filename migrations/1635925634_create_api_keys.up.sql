@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BINARY(16) NOT NULL,
+    organization_id BINARY(16) NOT NULL,
+    prefix CHAR(12) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    properties JSON NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    last_used_at DATETIME,
+    revoked_at DATETIME,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_api_keys_01 (key_hash),
+    CONSTRAINT fk_api_keys_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
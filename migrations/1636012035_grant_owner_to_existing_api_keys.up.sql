@@ -0,0 +1,3 @@
+INSERT INTO role_bindings (id, organization_id, api_key_id, resource_id, role, created_at, updated_at)
+SELECT UUID_TO_BIN(UUID()), organization_id, id, NULL, 'owner', UTC_TIMESTAMP(), UTC_TIMESTAMP()
+FROM api_keys;
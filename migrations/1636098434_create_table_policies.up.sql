@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS table_policies (
+    id BINARY(16) NOT NULL,
+    table_id BINARY(16) NOT NULL,
+    `where` JSON NOT NULL,
+    properties JSON NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_table_policies_01 FOREIGN KEY (table_id) REFERENCES tables(id) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
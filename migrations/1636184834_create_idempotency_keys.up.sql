@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key_hash CHAR(64) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INT,
+    header JSON,
+    body MEDIUMBLOB,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (key_hash),
+    INDEX idx_idempotency_keys_01 (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1 @@
+ALTER TABLE jobs DROP COLUMN owner, DROP COLUMN lease_expires_at;
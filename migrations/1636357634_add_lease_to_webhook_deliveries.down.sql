@@ -0,0 +1 @@
+ALTER TABLE webhook_deliveries DROP COLUMN owner, DROP COLUMN lease_expires_at;
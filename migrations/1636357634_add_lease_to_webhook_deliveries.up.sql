@@ -0,0 +1 @@
+ALTER TABLE webhook_deliveries ADD COLUMN owner CHAR(36) AFTER status, ADD COLUMN lease_expires_at DATETIME AFTER owner;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS change_event_records (
+    event_id BINARY(16) NOT NULL,
+    phase CHAR(6) NOT NULL,
+    id BINARY(16) NOT NULL,
+    id_string CHAR(36) AS (BIN_TO_UUID(id)) STORED NOT NULL,
+    data JSON NOT NULL,
+    properties JSON NOT NULL,
+    created_at DATETIME NOT NULL,
+    captured_at DATETIME NOT NULL,
+    PRIMARY KEY (event_id, phase, id),
+    INDEX idx_change_event_records_01 (captured_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
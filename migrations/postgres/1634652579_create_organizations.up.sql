@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id BYTEA NOT NULL,
+    properties JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id)
+);
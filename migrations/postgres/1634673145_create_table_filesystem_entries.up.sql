@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS table_filesystem_entries (
+    id BYTEA NOT NULL,
+    organization_id BYTEA NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    parent_folder_id BYTEA,
+    properties JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_table_filesystem_entries_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
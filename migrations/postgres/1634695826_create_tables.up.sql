@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS tables (
+    id BYTEA NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_tables_01 FOREIGN KEY (id) REFERENCES table_filesystem_entries(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE table_filesystem_entries DROP CONSTRAINT fk_table_filesystem_entries_02;
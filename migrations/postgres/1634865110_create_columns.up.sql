@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS columns (
+    id BYTEA NOT NULL,
+    table_id BYTEA NOT NULL,
+    "index" INTEGER NOT NULL,
+    properties JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_columns_01 FOREIGN KEY (table_id) REFERENCES tables(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT uq_columns_01 UNIQUE (table_id, "index")
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS table_records (
+    id BYTEA NOT NULL,
+    id_string VARCHAR(36) GENERATED ALWAYS AS (substr(encode(id, 'hex'), 1, 8) || '-' || substr(encode(id, 'hex'), 9, 4) || '-' || substr(encode(id, 'hex'), 13, 4) || '-' || substr(encode(id, 'hex'), 17, 4) || '-' || substr(encode(id, 'hex'), 21)) STORED NOT NULL,
+    table_id BYTEA NOT NULL,
+    data JSONB NOT NULL,
+    properties JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_table_records_01 FOREIGN KEY (table_id) REFERENCES tables(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT uq_table_records_01 UNIQUE (id_string)
+);
+CREATE INDEX IF NOT EXISTS idx_table_records_01 ON table_records (table_id, created_at);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id BYTEA NOT NULL,
+    organization_id BYTEA NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    params JSONB NOT NULL,
+    result JSONB,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_jobs_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_01 ON jobs (status, created_at);
@@ -0,0 +1 @@
+ALTER TABLE columns ADD COLUMN type VARCHAR(16);
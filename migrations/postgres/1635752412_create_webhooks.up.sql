@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+    id BYTEA NOT NULL,
+    organization_id BYTEA NOT NULL,
+    table_id BYTEA,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events JSONB NOT NULL,
+    properties JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_webhooks_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_webhooks_02 FOREIGN KEY (table_id) REFERENCES tables(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BYTEA NOT NULL,
+    webhook_id BYTEA NOT NULL,
+    event_id BYTEA NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INTEGER NOT NULL,
+    response_status INT,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    delivered_at TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_webhook_deliveries_01 FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_01 ON webhook_deliveries (webhook_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_02 ON webhook_deliveries (status);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS change_events (
+    seq BIGSERIAL NOT NULL,
+    id BYTEA NOT NULL,
+    organization_id BYTEA NOT NULL,
+    table_id BYTEA,
+    folder_ids JSONB NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (seq),
+    CONSTRAINT fk_change_events_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_change_events_01 ON change_events (table_id, seq);
+CREATE INDEX IF NOT EXISTS idx_change_events_02 ON change_events (created_at);
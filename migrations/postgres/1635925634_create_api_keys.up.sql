@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BYTEA NOT NULL,
+    organization_id BYTEA NOT NULL,
+    prefix VARCHAR(12) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    properties JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT uk_api_keys_01 UNIQUE (key_hash),
+    CONSTRAINT fk_api_keys_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+INSERT INTO role_bindings (id, organization_id, api_key_id, resource_id, role, created_at, updated_at)
+SELECT decode(replace(CAST(gen_random_uuid() AS text), '-', ''), 'hex'), organization_id, id, NULL, 'owner', now() AT TIME ZONE 'UTC', now() AT TIME ZONE 'UTC'
+FROM api_keys;
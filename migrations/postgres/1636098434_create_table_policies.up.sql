@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS table_policies (
+    id BYTEA NOT NULL,
+    table_id BYTEA NOT NULL,
+    "where" JSONB NOT NULL,
+    properties JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_table_policies_01 FOREIGN KEY (table_id) REFERENCES tables(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
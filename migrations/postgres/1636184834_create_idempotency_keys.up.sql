@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key_hash VARCHAR(64) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INT,
+    header JSONB,
+    body BYTEA,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (key_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_01 ON idempotency_keys (created_at);
@@ -0,0 +1 @@
+ALTER TABLE jobs DROP COLUMN lease_expires_at, DROP COLUMN owner;
@@ -0,0 +1 @@
+ALTER TABLE jobs ADD COLUMN owner VARCHAR(36), ADD COLUMN lease_expires_at TIMESTAMP;
@@ -0,0 +1 @@
+ALTER TABLE webhook_deliveries ADD COLUMN owner VARCHAR(36), ADD COLUMN lease_expires_at TIMESTAMP;
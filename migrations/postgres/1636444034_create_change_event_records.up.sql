@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS change_event_records (
+    event_id BYTEA NOT NULL,
+    phase VARCHAR(6) NOT NULL,
+    id BYTEA NOT NULL,
+    id_string VARCHAR(36) GENERATED ALWAYS AS (substr(encode(id, 'hex'), 1, 8) || '-' || substr(encode(id, 'hex'), 9, 4) || '-' || substr(encode(id, 'hex'), 13, 4) || '-' || substr(encode(id, 'hex'), 17, 4) || '-' || substr(encode(id, 'hex'), 21)) STORED NOT NULL,
+    data JSONB NOT NULL,
+    properties JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    captured_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (event_id, phase, id)
+);
+CREATE INDEX IF NOT EXISTS idx_change_event_records_01 ON change_event_records (captured_at);
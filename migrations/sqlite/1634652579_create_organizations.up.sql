@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id BLOB NOT NULL,
+    properties TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,14 @@
+-- SQLite cannot add constraints to existing tables, so the parent folder constraint is here
+-- instead of 1634768325_add_parent_folder_id_constraint
+CREATE TABLE IF NOT EXISTS table_filesystem_entries (
+    id BLOB NOT NULL,
+    organization_id BLOB NOT NULL,
+    type CHAR(16) NOT NULL,
+    parent_folder_id BLOB,
+    properties TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_table_filesystem_entries_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_table_filesystem_entries_02 FOREIGN KEY (parent_folder_id) REFERENCES folders(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
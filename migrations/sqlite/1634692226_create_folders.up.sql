@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS folders (
+    id BLOB NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_folders_01 FOREIGN KEY (id) REFERENCES table_filesystem_entries(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
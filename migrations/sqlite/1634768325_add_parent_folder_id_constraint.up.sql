@@ -0,0 +1,2 @@
+-- The constraint is created with the table in 1634673145_create_table_filesystem_entries
+SELECT 1;
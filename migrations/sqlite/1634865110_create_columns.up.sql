@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS columns (
+    id BLOB NOT NULL,
+    table_id BLOB NOT NULL,
+    `index` INTEGER NOT NULL,
+    properties TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_columns_01 FOREIGN KEY (table_id) REFERENCES tables(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT uq_columns_01 UNIQUE (table_id, `index`)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS table_records (
+    id BLOB NOT NULL,
+    id_string CHAR(36) AS (lower(substr(hex(id), 1, 8) || '-' || substr(hex(id), 9, 4) || '-' || substr(hex(id), 13, 4) || '-' || substr(hex(id), 17, 4) || '-' || substr(hex(id), 21))) STORED NOT NULL,
+    table_id BLOB NOT NULL,
+    data TEXT NOT NULL,
+    properties TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_table_records_01 FOREIGN KEY (table_id) REFERENCES tables(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT uq_table_records_01 UNIQUE (id_string)
+);
+CREATE INDEX IF NOT EXISTS idx_table_records_01 ON table_records (table_id, created_at);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id BLOB NOT NULL,
+    organization_id BLOB NOT NULL,
+    type CHAR(32) NOT NULL,
+    status CHAR(16) NOT NULL,
+    params TEXT NOT NULL,
+    result TEXT,
+    error TEXT,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    started_at DATETIME,
+    finished_at DATETIME,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_jobs_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_01 ON jobs (status, created_at);
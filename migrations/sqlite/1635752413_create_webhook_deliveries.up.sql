@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BLOB NOT NULL,
+    webhook_id BLOB NOT NULL,
+    event_id BLOB NOT NULL,
+    event_type CHAR(32) NOT NULL,
+    payload TEXT NOT NULL,
+    status CHAR(16) NOT NULL,
+    attempts INTEGER NOT NULL,
+    response_status INTEGER,
+    error TEXT,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    delivered_at DATETIME,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_webhook_deliveries_01 FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_01 ON webhook_deliveries (webhook_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_02 ON webhook_deliveries (status);
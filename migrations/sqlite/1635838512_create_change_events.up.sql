@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS change_events (
+    seq INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    id BLOB NOT NULL,
+    organization_id BLOB NOT NULL,
+    table_id BLOB,
+    folder_ids TEXT NOT NULL,
+    type CHAR(32) NOT NULL,
+    payload TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT fk_change_events_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_change_events_01 ON change_events (table_id, seq);
+CREATE INDEX IF NOT EXISTS idx_change_events_02 ON change_events (created_at);
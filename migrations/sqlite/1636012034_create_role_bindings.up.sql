@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS role_bindings (
+    id BLOB NOT NULL,
+    organization_id BLOB NOT NULL,
+    api_key_id BLOB NOT NULL,
+    resource_id BLOB,
+    role CHAR(16) NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_role_bindings_01 FOREIGN KEY (organization_id) REFERENCES organizations(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_role_bindings_02 FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_role_bindings_03 FOREIGN KEY (resource_id) REFERENCES table_filesystem_entries(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_role_bindings_01 ON role_bindings (api_key_id, resource_id);
@@ -0,0 +1 @@
+DELETE FROM role_bindings WHERE resource_id IS NULL AND role = 'owner';
@@ -0,0 +1,3 @@
+INSERT INTO role_bindings (id, organization_id, api_key_id, resource_id, role, created_at, updated_at)
+SELECT randomblob(16), organization_id, id, NULL, 'owner', datetime('now'), datetime('now')
+FROM api_keys;
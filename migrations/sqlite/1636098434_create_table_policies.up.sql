@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS table_policies (
+    id BLOB NOT NULL,
+    table_id BLOB NOT NULL,
+    `where` TEXT NOT NULL,
+    properties TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_table_policies_01 FOREIGN KEY (table_id) REFERENCES tables(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key_hash CHAR(64) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INTEGER,
+    header TEXT,
+    body BLOB,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (key_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_01 ON idempotency_keys (created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE webhook_deliveries ADD COLUMN owner TEXT;
+ALTER TABLE webhook_deliveries ADD COLUMN lease_expires_at DATETIME;
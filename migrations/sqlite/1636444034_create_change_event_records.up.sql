@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS change_event_records (
+    event_id BLOB NOT NULL,
+    phase CHAR(6) NOT NULL,
+    id BLOB NOT NULL,
+    id_string CHAR(36) AS (lower(substr(hex(id), 1, 8) || '-' || substr(hex(id), 9, 4) || '-' || substr(hex(id), 13, 4) || '-' || substr(hex(id), 17, 4) || '-' || substr(hex(id), 21))) STORED NOT NULL,
+    data TEXT NOT NULL,
+    properties TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    captured_at DATETIME NOT NULL,
+    PRIMARY KEY (event_id, phase, id)
+);
+CREATE INDEX IF NOT EXISTS idx_change_event_records_01 ON change_event_records (captured_at);
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/databases"
+)
+
+func orgCommand(args []string) error {
+	action, args, err := subcommand(args, "org")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("org "+action, flag.ExitOnError)
+	properties := fs.String("properties", "", "properties of the organization in JSON (create)")
+	page := fs.Int("page", 1, "page number (list)")
+	pageSize := fs.Int("page-size", 100, "number of organizations per page (list)")
+	conf, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := conf.setUpLogger(os.Stderr); err != nil {
+		return err
+	}
+
+	// Parse arguments before connecting to the database
+	var run func(db *gorm.DB) error
+	switch action {
+	case "create":
+		if fs.NArg() > 0 {
+			return fmt.Errorf("Usage: org create [-properties JSON]")
+		}
+		var props map[string]interface{}
+		if *properties != "" {
+			if err := json.Unmarshal([]byte(*properties), &props); err != nil {
+				return xerrors.Errorf("Invalid properties: %w", err)
+			}
+		}
+		if result := models.ValidateProperties(props); result != "" {
+			return fmt.Errorf("%s", result)
+		}
+		run = func(db *gorm.DB) error {
+			return createOrganization(db, props)
+		}
+	case "list":
+		if fs.NArg() > 0 {
+			return fmt.Errorf("Usage: org list [-page N] [-page-size N]")
+		}
+		if *page < 1 || *pageSize < 1 {
+			return fmt.Errorf("Invalid page or page size")
+		}
+		run = func(db *gorm.DB) error {
+			return listOrganizations(db, *page, *pageSize)
+		}
+	case "delete":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("Usage: org delete ID")
+		}
+		id, err := uuid.Parse(fs.Arg(0))
+		if err != nil {
+			return xerrors.Errorf("Invalid organization id: %w", err)
+		}
+		run = func(db *gorm.DB) error {
+			return deleteOrganization(db, id)
+		}
+	default:
+		return fmt.Errorf("Unknown action: org %s", action)
+	}
+
+	db, err := databases.Open(conf.dbConfig(), nil)
+	if err != nil {
+		return err
+	}
+	defer databases.Close(db)
+
+	return run(db)
+}
+
+func printOrganization(o *models.Organization) error {
+	var output schemas.Organization
+	if err := copier.Copy(&output, o); err != nil {
+		return xerrors.Errorf("Failed to make output data: %w", err)
+	}
+	return printJSON(&output)
+}
+
+func createOrganization(db *gorm.DB, props map[string]interface{}) error {
+	o := models.Organization{
+		Properties: props,
+	}
+	if err := o.Create(db); err != nil {
+		return err
+	}
+	return printOrganization(&o)
+}
+
+func listOrganizations(db *gorm.DB, page, pageSize int) error {
+	organizations, totalCount, err := models.GetOrganizationList(db, &models.GetOrganizationListOpts{
+		Sort:   []models.GetListSortKey{{Key: "createdAt", OrderAsc: true}},
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	var output schemas.OrganizationList
+	if err := copier.Copy(&output.Organizations, &organizations); err != nil {
+		return xerrors.Errorf("Failed to make output data: %w", err)
+	}
+	output.TotalCount = totalCount
+	return printJSON(&output)
+}
+
+func deleteOrganization(db *gorm.DB, id uuid.UUID) error {
+	o := models.Organization{ID: models.UUID(id)}
+	if _, err := o.Get(db); err != nil {
+		if xerrors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("Organization not found: %s", id)
+		}
+		return err
+	}
+	if err := o.Delete(db); err != nil {
+		return err
+	}
+	return printOrganization(&o)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOrgCommandArguments(t *testing.T) {
+	testCases := []struct {
+		args []string
+		err  string
+	}{
+		{args: nil, err: "org needs an action"},
+		{args: []string{"rename"}, err: "Unknown action: org rename"},
+		{args: []string{"create", "org1"}, err: "Usage: org create [-properties JSON]"},
+		{args: []string{"create", "-properties", "{"}, err: "Invalid properties"},
+		{args: []string{"create", "-properties", `{"a b": 1}`}, err: "Invalid property key: a b"},
+		{args: []string{"list", "-page", "0"}, err: "Invalid page or page size"},
+		{args: []string{"list", "all"}, err: "Usage: org list [-page N] [-page-size N]"},
+		{args: []string{"delete"}, err: "Usage: org delete ID"},
+		{args: []string{"delete", "org1"}, err: "Invalid organization id"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			clearEnv(t)
+			err := orgCommand(tc.args)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Error mismatch: expected=%s, actual=%v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestOrgCommand(t *testing.T) {
+	clearEnv(t)
+	args := sqliteArgs(t)
+	if _, err := captureStdout(t, func() error {
+		return migrateCommand(append([]string{"up"}, args...))
+	}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	org := func(action string, flags ...string) (map[string]interface{}, error) {
+		out, err := captureStdout(t, func() error {
+			a := append([]string{action}, args...)
+			return orgCommand(append(a, flags...))
+		})
+		if err != nil {
+			return nil, err
+		}
+		var output map[string]interface{}
+		if err := json.Unmarshal([]byte(out), &output); err != nil {
+			t.Fatalf("[%s] Invalid output: %s", action, out)
+		}
+		return output, nil
+	}
+
+	created, err := org("create", "-properties", `{"name": "org1"}`)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	id, _ := created["id"].(string)
+	if id == "" || created["properties"].(map[string]interface{})["name"] != "org1" {
+		t.Errorf("Unexpected output of create: %v", created)
+	}
+
+	list, err := org("list", "-page-size", "10")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if list["totalCount"] != float64(1) {
+		t.Errorf("Unexpected output of list: %v", list)
+	}
+
+	deleted, err := org("delete", id)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if deleted["id"] != id {
+		t.Errorf("Unexpected output of delete: %v", deleted)
+	}
+
+	if _, err := org("delete", id); err == nil || !strings.Contains(err.Error(), "Organization not found") {
+		t.Errorf("Unexpected error of deleting again: %v", err)
+	}
+}
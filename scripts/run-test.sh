@@ -5,6 +5,63 @@ DB_NAME=x_base_test
 
 SCRIPT_DIR=$(cd $(dirname $0); pwd)
 
+# SQLite needs no database server
+if [ "$DB_TYPE" = "sqlite" ]; then
+    cd $SCRIPT_DIR/..
+    DB_NAME=$(mktemp -d)/${DB_NAME}.db \
+        MIGRATIONS_DIR=$SCRIPT_DIR/../migrations \
+        go test -v $@ github.com/tsujio/x-base/tests
+    exit $?
+fi
+
+if [ "$DB_TYPE" = "postgres" ]; then
+    PG_CONTAINER_NAME=postgres-x-base-test
+
+    if [ `docker ps -f name=$PG_CONTAINER_NAME --quiet | wc -l` -eq 0 ]; then
+        docker run \
+            --name $PG_CONTAINER_NAME \
+            --rm \
+            -e POSTGRES_PASSWORD=password \
+            -e POSTGRES_DB=$DB_NAME \
+            -d \
+            postgres:14 \
+            || exit 1
+
+        echo -n "Waiting for launching postgres "
+        until docker exec $PG_CONTAINER_NAME pg_isready -U postgres -d $DB_NAME > /dev/null 2>&1
+        do
+                echo -n "."
+                sleep 1
+        done
+        echo
+    fi
+
+    if [ -n "$BUILD_CACHE_DIR" ]; then
+        DOCKEROPTS="-v $BUILD_CACHE_DIR:/go"
+    fi
+
+    docker run \
+        --rm \
+        -v $SCRIPT_DIR/../:/app:ro \
+        --link $PG_CONTAINER_NAME:postgres \
+        -e DB_HOST=postgres \
+        -e DB_PORT=5432 \
+        -e DB_USER=postgres \
+        -e DB_PASSWORD=password \
+        -e DB_NAME=$DB_NAME \
+        -e DB_TYPE=postgres \
+        -e MIGRATIONS_DIR=/app/migrations \
+        --workdir /app \
+        $DOCKEROPTS \
+        golang:1.16 \
+        bash -c "
+            echo 'Start testing...'
+            go mod download && \
+            go test -v $@ github.com/tsujio/x-base/tests
+        "
+    exit $?
+fi
+
 if [ `docker ps -f name=$DB_CONTAINER_NAME --quiet | wc -l` -eq 0 ]; then
     docker run \
         --name $DB_CONTAINER_NAME \
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"golang.org/x/xerrors"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/databases"
+	"github.com/tsujio/x-base/logging"
+)
+
+func serve(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	migrateUp := fs.Bool("migrate", true, "apply migrations before serving")
+	conf, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("Unexpected arguments: %v", fs.Args())
+	}
+
+	// Initialize logger
+	if err := conf.setUpLogger(os.Stdout); err != nil {
+		return err
+	}
+	defer logging.Close()
+
+	// Set up db
+	dbConfig := conf.dbConfig()
+	if *migrateUp {
+		if err := databases.Setup(dbConfig, conf.MigrationsDir); err != nil {
+			return xerrors.Errorf("Failed to set up db: %w", err)
+		}
+	}
+	db, err := databases.Open(dbConfig, nil)
+	if err != nil {
+		return xerrors.Errorf("Failed to open db: %w", err)
+	}
+	defer func() {
+		if err := databases.Close(db); err != nil {
+			logging.Error(fmt.Sprintf("Failed to close db: %+v", err), nil)
+		}
+	}()
+
+	apiConf := conf.apiConfig()
+	apiConf.Health.MigrationVersion, err = databases.LatestMigrationVersion(databases.MigrationsDir(dbConfig, conf.MigrationsDir))
+	if err != nil {
+		return xerrors.Errorf("Failed to get migration version: %w", err)
+	}
+	if (apiConf.Auth.Mode == auth.ModeAPIKey || apiConf.Auth.Mode == auth.ModeJWT) && apiConf.Auth.AdminAPIKey == "" {
+		logging.Warning("ADMIN_API_KEY is not set, so organizations cannot be created", nil)
+	}
+
+	// Run api
+	if err := api.Run(conf.Server.Host, conf.Server.Port, db, apiConf); err != nil {
+		return xerrors.Errorf("Failed to run api: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServeArguments(t *testing.T) {
+	testCases := []struct {
+		title string
+		env   map[string]string
+		args  []string
+		err   string
+	}{
+		{
+			title: "Unexpected argument",
+			args:  []string{"-port", "8080", "now"},
+			err:   "Unexpected arguments: [now]",
+		},
+		{
+			title: "Invalid setting",
+			env:   map[string]string{"PORT": "http"},
+			err:   "Invalid PORT",
+		},
+		{
+			title: "Invalid log format",
+			args:  []string{"-log-format", "xml"},
+			err:   "Invalid log format: xml",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tc.env {
+				setenv(t, k, v)
+			}
+			err := serve(tc.args)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Error mismatch: expected=%s, actual=%v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestAccessControl(t *testing.T) {
+	config := &api.Config{
+		Auth: auth.Config{
+			Mode: auth.ModeAPIKey,
+		},
+	}
+	bearer := func(key string) http.Header {
+		return http.Header{"Authorization": []string{"Bearer " + key}}
+	}
+
+	// viewer key can read everything and edit folder-01 and its descendants
+	viewer := "xb_viewerxxxxxxxxxx"
+	// restricted key has no role on the organization and views only table-03
+	restricted := "xb_restrictedxxxxxx"
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(fmt.Sprintf(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: folder-01
+		        children:
+		          - id: folder-02
+		            children:
+		              - id: table-01
+		                columns:
+		                  - id: column-01
+		      - id: table-02
+		        columns:
+		          - id: column-02
+		      - id: table-03
+		    apiKeys:
+		      - key: %s
+		        role: viewer
+		        roleBindings:
+		          - resourceId: folder-01
+		            role: editor
+		      - key: %s
+		        role: none
+		        roleBindings:
+		          - resourceId: table-03
+		            role: viewer
+		`, viewer, restricted))
+	}
+	deleteQuery := func(columnID string) map[string]interface{} {
+		return makeJSON(`
+		delete:
+		  where:
+		    eq:
+		      - {column: {{ .column }} }
+		      - {value: a}
+		`, map[string]interface{}{
+			"column": testutils.GetUUID(columnID),
+		})
+	}
+
+	folderPath := func(ids ...string) []interface{} {
+		path := []interface{}{}
+		for _, id := range ids {
+			path = append(path, map[string]interface{}{
+				"id":         testutils.GetUUID(id),
+				"type":       "folder",
+				"properties": map[string]interface{}{},
+			})
+		}
+		return path
+	}
+	tableOutput := func(id interface{}, path []interface{}, columns []interface{}, properties map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"id":             id,
+			"organizationId": testutils.GetUUID("org1"),
+			"type":           "table",
+			"path":           path,
+			"columns":        columns,
+			"properties":     properties,
+			"createdAt":      testutils.Timestamp{},
+			"updatedAt":      testutils.Timestamp{},
+		}
+	}
+	column := func(id, tableID string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":         testutils.GetUUID(id),
+			"tableId":    testutils.GetUUID(tableID),
+			"index":      float64(0),
+			"type":       nil,
+			"properties": map[string]interface{}{},
+			"createdAt":  testutils.Timestamp{},
+			"updatedAt":  testutils.Timestamp{},
+		}
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Viewer can read",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-02")),
+			Header:     bearer(viewer),
+			StatusCode: http.StatusOK,
+			Output: tableOutput(testutils.GetUUID("table-02"), folderPath(),
+				[]interface{}{column("column-02", "table-02")}, map[string]interface{}{}),
+		},
+		{
+			Title:   "Viewer cannot update",
+			Prepare: prepare,
+			Method:  http.MethodPatch,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-02")),
+			Header:  bearer(viewer),
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:   "Editor role is inherited from ancestor folder",
+			Prepare: prepare,
+			Method:  http.MethodPatch,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:  bearer(viewer),
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusOK,
+			Output: tableOutput(testutils.GetUUID("table-01"), folderPath("folder-01", "folder-02"),
+				[]interface{}{column("column-01", "table-01")}, map[string]interface{}{"key": "value"}),
+		},
+		{
+			Title:   "Select query needs read permission",
+			Prepare: prepare,
+			Method:  http.MethodPost,
+			Path:    fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-02")),
+			Header:  bearer(viewer),
+			Body: makeJSON(`
+			select:
+			  columns:
+			    - metadata: id
+			`, nil),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"records": []interface{}{},
+				"limit":   float64(10),
+			},
+		},
+		{
+			Title:   "Insert query needs write permission",
+			Prepare: prepare,
+			Method:  http.MethodPost,
+			Path:    fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-02")),
+			Header:  bearer(viewer),
+			Body: makeJSON(`
+			insert:
+			  columns:
+			    - column: {{ .column }}
+			  values:
+			    - - value: a
+			`, map[string]interface{}{
+				"column": testutils.GetUUID("column-02"),
+			}),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "Delete query needs write permission",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-02")),
+			Header:     bearer(viewer),
+			Body:       deleteQuery("column-02"),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "Delete query in editable folder",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Header:     bearer(viewer),
+			Body:       deleteQuery("column-01"),
+			StatusCode: http.StatusOK,
+			Output:     map[string]interface{}{},
+		},
+		{
+			Title:   "Create table in editable folder",
+			Prepare: prepare,
+			Method:  http.MethodPost,
+			Path:    "/tables",
+			Header:  bearer(viewer),
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"parentFolderId": testutils.GetUUID("folder-02"),
+			},
+			StatusCode: http.StatusOK,
+			Output: tableOutput(testutils.UUID{}, folderPath("folder-01", "folder-02"),
+				[]interface{}{}, map[string]interface{}{}),
+		},
+		{
+			Title:   "Create table in root folder",
+			Prepare: prepare,
+			Method:  http.MethodPost,
+			Path:    "/tables",
+			Header:  bearer(viewer),
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:   "Move table out of editable folder",
+			Prepare: prepare,
+			Method:  http.MethodPatch,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:  bearer(viewer),
+			Body: map[string]interface{}{
+				"parentFolderId": uuid.Nil,
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "Viewer cannot manage api keys",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/organizations/%s/api-keys", testutils.GetUUID("org1")),
+			Header:     bearer(viewer),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "Role on table only",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-03")),
+			Header:     bearer(restricted),
+			StatusCode: http.StatusOK,
+			Output: tableOutput(testutils.GetUUID("table-03"), folderPath(),
+				[]interface{}{}, map[string]interface{}{}),
+		},
+		{
+			Title:      "No role on other tables",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-02")),
+			Header:     bearer(restricted),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "No role on root folder",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/folders/%s/children", uuid.Nil),
+			Query:      url.Values{"organizationId": []string{testutils.GetUUID("org1").String()}},
+			Header:     bearer(restricted),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Config = config
+		testutils.RunTestCase(t, tc)
+	}
+}
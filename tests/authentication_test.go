@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestAuthentication(t *testing.T) {
+	config := &api.Config{
+		Auth: auth.Config{
+			Mode:        auth.ModeAPIKey,
+			AdminAPIKey: "admin-secret",
+		},
+	}
+	bearer := func(key string) http.Header {
+		return http.Header{"Authorization": []string{"Bearer " + key}}
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    apiKeys:
+		      - id: key-01
+		        key: xb_key1aaaaaaaaaaaa
+		      - id: key-02
+		        key: xb_key2bbbbbbbbbbbb
+		        revoked: true
+		    tables:
+		      - id: table-01
+		  - id: org2
+		    tables:
+		      - id: table-02
+		`)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "No credentials",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			StatusCode: http.StatusUnauthorized,
+			Output: map[string]interface{}{
+				"message": "Authentication required",
+			},
+		},
+		{
+			Title:      "Invalid key",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:     bearer("xb_unknownkeyxxxxxxx"),
+			StatusCode: http.StatusUnauthorized,
+			Output: map[string]interface{}{
+				"message": "Invalid API key",
+			},
+		},
+		{
+			Title:      "Revoked key",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:     bearer("xb_key2bbbbbbbbbbbb"),
+			StatusCode: http.StatusUnauthorized,
+			Output: map[string]interface{}{
+				"message": "Invalid API key",
+			},
+		},
+		{
+			Title:      "Own organization",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:     bearer("xb_key1aaaaaaaaaaaa"),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.GetUUID("table-01"),
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "table",
+				"path":           testutils.AnyVal{},
+				"columns":        []interface{}{},
+				"properties":     map[string]interface{}{},
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				k, err := (&models.APIKey{ID: models.UUID(testutils.GetUUID("key-01"))}).Get(testutils.GetDB())
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if k.LastUsedAt == nil {
+					t.Errorf("[%s] lastUsedAt is not updated", tc.Title)
+				}
+			},
+		},
+		{
+			Title:   "Access token parameter on event stream",
+			Prepare: prepare,
+			Method:  http.MethodGet,
+			Path:    fmt.Sprintf("/tables/%s/events", testutils.GetUUID("table-01")),
+			Query:   url.Values{"access_token": []string{"xb_key1aaaaaaaaaaaa"}},
+			// Authenticated and the parameter is not rejected as unknown, so the invalid header is reported
+			Header: http.Header{
+				"Last-Event-Id": []string{"abc"},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request header`},
+			},
+		},
+		{
+			Title:      "Access token parameter on other requests",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/organizations/%s", testutils.GetUUID("org1")),
+			Query:      url.Values{"access_token": []string{"xb_key1aaaaaaaaaaaa"}},
+			StatusCode: http.StatusUnauthorized,
+			Output: map[string]interface{}{
+				"message": "Authentication required",
+			},
+		},
+		{
+			Title:      "Another organization's table",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-02")),
+			Header:     bearer("xb_key1aaaaaaaaaaaa"),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "Another organization",
+			Prepare:    prepare,
+			Method:     http.MethodDelete,
+			Path:       fmt.Sprintf("/organizations/%s", testutils.GetUUID("org2")),
+			Header:     bearer("xb_key1aaaaaaaaaaaa"),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:   "Create table in another organization",
+			Prepare: prepare,
+			Method:  http.MethodPost,
+			Path:    "/tables",
+			Header:  bearer("xb_key1aaaaaaaaaaaa"),
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org2"),
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "Organization list is limited to own organization",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       "/organizations",
+			Header:     bearer("xb_key1aaaaaaaaaaaa"),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"organizations": []interface{}{
+					map[string]interface{}{
+						"id":         testutils.GetUUID("org1"),
+						"properties": map[string]interface{}{},
+						"createdAt":  testutils.Timestamp{},
+						"updatedAt":  testutils.Timestamp{},
+					},
+				},
+				"totalCount": float64(1),
+			},
+		},
+		{
+			Title:      "Organization key cannot create organization",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       "/organizations",
+			Header:     bearer("xb_key1aaaaaaaaaaaa"),
+			Body:       map[string]interface{}{},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "Admin key",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/organizations/%s", testutils.GetUUID("org2")),
+			Header:     bearer("admin-secret"),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":         testutils.GetUUID("org2"),
+				"properties": map[string]interface{}{},
+				"createdAt":  testutils.Timestamp{},
+				"updatedAt":  testutils.Timestamp{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Config = config
+		testutils.RunTestCase(t, tc)
+	}
+}
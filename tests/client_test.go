@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/schemas"
+	"github.com/tsujio/x-base/client"
+	"github.com/tsujio/x-base/client/query"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestClient(t *testing.T) {
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		        columns:
+		          - id: column-01
+		            type: integer
+		  - id: org2
+		  - id: org3
+		`)
+	}
+	ctx := context.Background()
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Query records",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				server := httptest.NewServer(router)
+				defer server.Close()
+				c := client.New(server.URL, nil)
+				tableID := testutils.GetUUID("table-01")
+				column := query.Column(testutils.GetUUID("column-01"))
+
+				inserted, err := c.Insert(ctx, tableID, query.Insert(column, query.Property("name")).
+					Values(1, "a").
+					Values(2, "b").
+					Values(3, "c").
+					Values(4, "d"))
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if len(inserted.RecordIDs) != 4 {
+					t.Errorf("[%s] Inserted records mismatch: %v", tc.Title, inserted.RecordIDs)
+				}
+
+				err = c.Update(ctx, tableID, query.Update(column.Eq(query.Value(4))).
+					Set(query.Property("name"), query.Value("z")))
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				err = c.Delete(ctx, tableID, query.Delete(column.Eq(query.Value(1))))
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+
+				var names []interface{}
+				q := query.Select(query.Property("name")).
+					Where(query.And(column.Ge(query.Value(2)), column.Le(query.Value(4)))).
+					OrderBy(column, query.Asc).
+					Limit(2)
+				err = c.EachRecord(ctx, tableID, q, func(record []interface{}) error {
+					names = append(names, record[0])
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if diff := testutils.CompareJson([]interface{}{"b", "c", "z"}, names); diff != "" {
+					t.Errorf("[%s] Records mismatch:\n%s", tc.Title, diff)
+				}
+
+				result, err := c.Select(ctx, tableID, query.Select(query.Count()))
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if diff := testutils.CompareJson([][]interface{}{{float64(3)}}, result.Records); diff != "" {
+					t.Errorf("[%s] Count mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title:      "Tables, columns and errors",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				server := httptest.NewServer(router)
+				defer server.Close()
+				c := client.New(server.URL, nil)
+
+				columnType := "text"
+				table, err := c.CreateTable(ctx, &schemas.CreateTableInput{
+					OrganizationID: testutils.GetUUID("org1"),
+					Columns:        []schemas.CreateColumnInput{{Type: &columnType}},
+					Properties:     map[string]interface{}{"name": "table"},
+				})
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if len(table.Columns) != 1 || table.Properties["name"] != "table" {
+					t.Errorf("[%s] Table mismatch: %+v", tc.Title, table)
+				}
+
+				column, err := c.UpdateColumn(ctx, table.ID, table.Columns[0].ID, &schemas.UpdateColumnInput{
+					Properties: map[string]interface{}{"name": "column"},
+				})
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if column.Properties["name"] != "column" {
+					t.Errorf("[%s] Column mismatch: %+v", tc.Title, column)
+				}
+
+				_, err = c.GetColumn(ctx, table.ID, uuid.New())
+				if !client.IsNotFound(err) {
+					t.Errorf("[%s] Expected not found: %+v", tc.Title, err)
+				}
+				_, err = c.CreateTable(ctx, &schemas.CreateTableInput{})
+				if client.StatusCode(err) != http.StatusBadRequest {
+					t.Errorf("[%s] Expected bad request: %+v", tc.Title, err)
+				}
+			},
+		},
+		{
+			Title:      "Pagination",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       "/organizations",
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				server := httptest.NewServer(router)
+				defer server.Close()
+				c := client.New(server.URL, nil)
+
+				var ids []uuid.UUID
+				err := c.EachOrganization(ctx, &client.ListOptions{PageSize: 2}, func(o *schemas.Organization) error {
+					ids = append(ids, o.ID)
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if len(ids) != 3 {
+					t.Errorf("[%s] Organizations mismatch: %v", tc.Title, ids)
+				}
+
+				var children int
+				err = c.EachFolderChild(ctx, testutils.GetUUID("org1"), uuid.Nil, &client.ListOptions{PageSize: 1}, func(*schemas.FolderChild) error {
+					children++
+					return client.ErrStop
+				})
+				if err != nil || children != 1 {
+					t.Errorf("[%s] Iteration not stopped: %d, %+v", tc.Title, children, err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,308 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestConvertColumn(t *testing.T) {
+	makePath := func(tableID, columnID uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/columns/%s/convert", tableID, columnID)
+	}
+
+	fixture := `
+	organizations:
+	  - id: org1
+	    tables:
+	      - id: table-01
+	        columns:
+	          - id: column-01
+	            type: text
+	          - id: column-02
+	            type: text
+	        records:
+	          - id: record-01
+	            data: [a, "10"]
+	            createdAt: "2021-10-01T00:00:00Z"
+	          - id: record-02
+	            data: [b, "1,234.5"]
+	            createdAt: "2021-10-02T00:00:00Z"
+	          - id: record-03
+	            data: [c, abc]
+	            createdAt: "2021-10-03T00:00:00Z"
+	          - id: record-04
+	            data: [d, null]
+	            createdAt: "2021-10-04T00:00:00Z"
+	`
+
+	makeColumn := func(typ string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":         testutils.GetUUID("column-02"),
+			"tableId":    testutils.GetUUID("table-01"),
+			"type":       typ,
+			"index":      float64(1),
+			"properties": map[string]interface{}{},
+			"createdAt":  testutils.Timestamp{},
+			"updatedAt":  testutils.Timestamp{},
+		}
+	}
+
+	checkRecords := func(tc *testutils.APITestCase, router http.Handler, values []interface{}) {
+		res := selectTable(router, testutils.GetUUID("table-01"), makeJSON(`
+		select:
+		  columns:
+		    - column: {{ .column02 }}
+		  orderBy:
+		    - key:
+		        metadata: createdAt
+		`, map[string]interface{}{
+			"column02": testutils.GetUUID("column-02"),
+		}))
+		var records []interface{}
+		for _, v := range values {
+			records = append(records, []interface{}{v})
+		}
+		expected := map[string]interface{}{
+			"records": records,
+			"limit":   float64(10),
+		}
+		if diff := testutils.CompareJson(expected, res); diff != "" {
+			t.Errorf("[%s] Selected records mismatch:\n%s", tc.Title, diff)
+		}
+	}
+
+	checkColumnType := func(tc *testutils.APITestCase, router http.Handler, typ string) {
+		res := testutils.ServeGet(router, fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")), nil)
+		if diff := testutils.CompareJson(makeColumn(typ), res["columns"].([]interface{})[1]); diff != "" {
+			t.Errorf("[%s] Column mismatch:\n%s", tc.Title, diff)
+		}
+	}
+
+	failures := []interface{}{
+		map[string]interface{}{
+			"recordId": testutils.GetUUID("record-03"),
+			"value":    "abc",
+		},
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "Abort on error",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-02")),
+			Body: map[string]interface{}{
+				"type": "number",
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"column":           makeColumn("text"),
+				"applied":          false,
+				"convertedRecords": float64(3),
+				"failureCount":     float64(1),
+				"failures":         failures,
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				checkRecords(tc, router, []interface{}{"10", "1,234.5", "abc", nil})
+				checkColumnType(tc, router, "text")
+			},
+		},
+		{
+			Title: "Null on error",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-02")),
+			Body: map[string]interface{}{
+				"type":    "number",
+				"onError": "null",
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"column":           makeColumn("number"),
+				"applied":          true,
+				"convertedRecords": float64(3),
+				"failureCount":     float64(1),
+				"failures":         failures,
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				checkRecords(tc, router, []interface{}{float64(10), float64(1234.5), nil, nil})
+				checkColumnType(tc, router, "number")
+			},
+		},
+		{
+			Title: "Dry run",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-02")),
+			Body: map[string]interface{}{
+				"type":    "number",
+				"onError": "null",
+				"dryRun":  true,
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"column":           makeColumn("text"),
+				"applied":          false,
+				"convertedRecords": float64(3),
+				"failureCount":     float64(1),
+				"failures":         failures,
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				checkRecords(tc, router, []interface{}{"10", "1,234.5", "abc", nil})
+				checkColumnType(tc, router, "text")
+			},
+		},
+		{
+			Title: "Failures are filtered by table policies",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fmt.Sprintf(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				        columns:
+				          - id: column-01
+				            type: text
+				          - id: column-02
+				            type: text
+				        records:
+				          - id: record-01
+				            data: [a, "10"]
+				          - id: record-02
+				            data: [b, "1,234.5"]
+				          - id: record-03
+				            data: [c, abc]
+				          - id: record-04
+				            data: [d, null]
+				        policies:
+				          - where:
+				              eq:
+				                - {column: %s}
+				                - {attribute: customer}
+				    apiKeys:
+				      - key: xb_customerxxxxxxxx
+				        role: editor
+				        properties: {customer: a}
+				`, testutils.GetUUID("column-01")))
+			},
+			Config: &api.Config{
+				Auth: auth.Config{
+					Mode: auth.ModeAPIKey,
+				},
+			},
+			Header: http.Header{"Authorization": []string{"Bearer xb_customerxxxxxxxx"}},
+			Path:   makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-02")),
+			Body: map[string]interface{}{
+				"type": "number",
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"column":           makeColumn("text"),
+				"applied":          false,
+				"convertedRecords": float64(3),
+				"failureCount":     float64(1),
+				"failures":         []interface{}{},
+			},
+		},
+		{
+			Title: "Column order is kept",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				        columns:
+				          - id: column-01
+				            type: text
+				          - id: column-02
+				          - id: column-03
+				        records:
+				          - data: ["1", a, c]
+				          - data: ["2", b, d]
+				`)
+			},
+			Path: makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-01")),
+			Body: map[string]interface{}{
+				"type":    "number",
+				"onError": "null",
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"column": map[string]interface{}{
+					"id":         testutils.GetUUID("column-01"),
+					"tableId":    testutils.GetUUID("table-01"),
+					"type":       "number",
+					"index":      float64(0),
+					"properties": map[string]interface{}{},
+					"createdAt":  testutils.Timestamp{},
+					"updatedAt":  testutils.Timestamp{},
+				},
+				"applied":          true,
+				"convertedRecords": float64(2),
+				"failureCount":     float64(0),
+				"failures":         []interface{}{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := testutils.ServeGet(router, fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")), nil)
+				var columns []interface{}
+				for _, c := range res["columns"].([]interface{}) {
+					c := c.(map[string]interface{})
+					columns = append(columns, []interface{}{c["id"], c["index"]})
+				}
+				expected := []interface{}{
+					[]interface{}{testutils.GetUUID("column-01").String(), float64(0)},
+					[]interface{}{testutils.GetUUID("column-02").String(), float64(1)},
+					[]interface{}{testutils.GetUUID("column-03").String(), float64(2)},
+				}
+				if diff := testutils.CompareJson(expected, columns); diff != "" {
+					t.Errorf("[%s] Column order mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title: "Invalid type",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-02")),
+			Body: map[string]interface{}{
+				"type": "integer",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request body`},
+			},
+		},
+		{
+			Title: "Column not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-03")),
+			Body: map[string]interface{}{
+				"type": "number",
+			},
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Column not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPost
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,285 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func waitJob(router http.Handler, id interface{}) map[string]interface{} {
+	for i := 0; i < 100; i++ {
+		job := testutils.ServeGet(router, fmt.Sprintf("/jobs/%s", id), nil)
+		if job["status"] == "succeeded" || job["status"] == "failed" {
+			return job
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+func TestCopyFolder(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/folders/%s/copy", id)
+	}
+
+	fixture := `
+	organizations:
+	  - id: org1
+	    tables:
+	      - id: folder-01
+	        properties:
+	          name: Template
+	        children:
+	          - id: folder-02
+	            properties:
+	              name: Sub
+	            children:
+	              - id: table-01
+	                properties:
+	                  name: Tasks
+	                columns:
+	                  - id: column-01
+	                records:
+	                  - data: [task1]
+	          - id: table-02
+	            properties:
+	              name: Members
+	      - id: folder-03
+	  - id: org2
+	    tables:
+	      - id: folder-04
+	`
+
+	checkCopied := func(t *testing.T, tc *testutils.APITestCase, router http.Handler, folderID interface{}, includeRecords bool) {
+		children := testutils.ServeGet(router, fmt.Sprintf("/folders/%s/children", folderID), nil)
+		expected := map[string]interface{}{
+			"totalCount": float64(2),
+			"children": []interface{}{
+				map[string]interface{}{
+					"id":             testutils.UUID{},
+					"organizationId": testutils.GetUUID("org1"),
+					"type":           testutils.AnyVal{},
+					"path":           testutils.AnyVal{},
+					"properties":     testutils.AnyVal{},
+					"createdAt":      testutils.Timestamp{},
+					"updatedAt":      testutils.Timestamp{},
+				},
+				map[string]interface{}{
+					"id":             testutils.UUID{},
+					"organizationId": testutils.GetUUID("org1"),
+					"type":           testutils.AnyVal{},
+					"path":           testutils.AnyVal{},
+					"properties":     testutils.AnyVal{},
+					"createdAt":      testutils.Timestamp{},
+					"updatedAt":      testutils.Timestamp{},
+				},
+			},
+		}
+		if diff := testutils.CompareJson(expected, children); diff != "" {
+			t.Errorf("[%s] Children mismatch:\n%s", tc.Title, diff)
+			return
+		}
+
+		var subFolderID interface{}
+		for _, c := range children["children"].([]interface{}) {
+			child := c.(map[string]interface{})
+			if child["id"] == testutils.GetUUID("folder-02").String() || child["id"] == testutils.GetUUID("table-02").String() {
+				t.Errorf("[%s] Child is not copied: %v", tc.Title, child["id"])
+			}
+			if child["type"] == "folder" {
+				subFolderID = child["id"]
+			}
+		}
+		if subFolderID == nil {
+			t.Errorf("[%s] Sub folder not found", tc.Title)
+			return
+		}
+
+		subChildren := testutils.ServeGet(router, fmt.Sprintf("/folders/%s/children", subFolderID), nil)
+		tables := subChildren["children"].([]interface{})
+		if len(tables) != 1 {
+			t.Errorf("[%s] Sub folder children mismatch: %v", tc.Title, tables)
+			return
+		}
+		table := testutils.ServeGet(router, fmt.Sprintf("/tables/%s", tables[0].(map[string]interface{})["id"]), nil)
+		if diff := testutils.CompareJson(map[string]interface{}{"name": "Tasks"}, table["properties"]); diff != "" {
+			t.Errorf("[%s] Table properties mismatch:\n%s", tc.Title, diff)
+		}
+		columnID := table["columns"].([]interface{})[0].(map[string]interface{})["id"]
+		res := selectTable(router, uuid.MustParse(table["id"].(string)), makeJSON(`
+		select:
+		  columns:
+		    - column: {{ .column01 }}
+		`, map[string]interface{}{
+			"column01": columnID,
+		}))
+		records := []interface{}{}
+		if includeRecords {
+			records = append(records, []interface{}{"task1"})
+		}
+		if diff := testutils.CompareJson(map[string]interface{}{"records": records, "limit": float64(10)}, res); diff != "" {
+			t.Errorf("[%s] Selected records mismatch:\n%s", tc.Title, diff)
+		}
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "Include records",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("folder-01")),
+			Body: map[string]interface{}{
+				"parentFolderId": testutils.GetUUID("folder-03"),
+				"properties": map[string]interface{}{
+					"name": "Project A",
+				},
+				"includeRecords": true,
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "folder",
+				"path": []interface{}{
+					map[string]interface{}{
+						"id":         testutils.GetUUID("folder-03"),
+						"type":       "folder",
+						"properties": map[string]interface{}{},
+					},
+				},
+				"properties": map[string]interface{}{
+					"name": "Project A",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				checkCopied(t, tc, router, output["id"], true)
+			},
+		},
+		{
+			Title: "Exclude records to root",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path:       makePath(testutils.GetUUID("folder-01")),
+			Body:       map[string]interface{}{},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "folder",
+				"path":           []interface{}{},
+				"properties": map[string]interface{}{
+					"name": "Template",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				checkCopied(t, tc, router, output["id"], false)
+			},
+		},
+		{
+			Title: "Async",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("folder-01")),
+			Body: map[string]interface{}{
+				"parentFolderId": testutils.GetUUID("folder-03"),
+				"includeRecords": true,
+				"async":          true,
+			},
+			StatusCode: http.StatusAccepted,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "copy_folder",
+				"status":         "pending",
+				"progress":       nil,
+				"result":         nil,
+				"error":          nil,
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+				"startedAt":      nil,
+				"finishedAt":     nil,
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				job := waitJob(router, output["id"])
+				expected := map[string]interface{}{
+					"id":             output["id"],
+					"organizationId": testutils.GetUUID("org1"),
+					"type":           "copy_folder",
+					"status":         "succeeded",
+					"progress":       nil,
+					"result": map[string]interface{}{
+						"folderId": testutils.UUID{},
+					},
+					"error":      nil,
+					"createdAt":  testutils.Timestamp{},
+					"updatedAt":  testutils.Timestamp{},
+					"startedAt":  testutils.Timestamp{},
+					"finishedAt": testutils.Timestamp{},
+				}
+				if diff := testutils.CompareJson(expected, job); diff != "" {
+					t.Errorf("[%s] Job mismatch:\n%s", tc.Title, diff)
+					return
+				}
+				checkCopied(t, tc, router, job["result"].(map[string]interface{})["folderId"], true)
+			},
+		},
+		{
+			Title: "Into itself",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("folder-01")),
+			Body: map[string]interface{}{
+				"parentFolderId": testutils.GetUUID("folder-02"),
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Cannot copy into itself",
+			},
+		},
+		{
+			Title: "Another organization",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("folder-01")),
+			Body: map[string]interface{}{
+				"parentFolderId": testutils.GetUUID("folder-04"),
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Cannot copy to another organization",
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path:       makePath(testutils.GetUUID("folder-05")),
+			Body:       map[string]interface{}{},
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPost
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	makePath := func(organizationID uuid.UUID) string {
+		return fmt.Sprintf("/organizations/%s/api-keys", organizationID)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Path: makePath(testutils.GetUUID("org1")),
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{
+					"name": "ci",
+				},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"prefix":         testutils.Regexp{Pattern: `^xb_[\w-]{9}$`},
+				"key":            testutils.Regexp{Pattern: `^xb_[\w-]{43}$`},
+				"properties": map[string]interface{}{
+					"name": "ci",
+				},
+				"createdAt":  testutils.Timestamp{},
+				"updatedAt":  testutils.Timestamp{},
+				"lastUsedAt": nil,
+				"revokedAt":  nil,
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				// Only the hash of the key is saved
+				key := output["key"].(string)
+				k, err := models.GetAPIKeyByHash(testutils.GetDB(), auth.HashAPIKey(key))
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if k.ID.String() != output["id"] || k.KeyHash == key {
+					t.Errorf("[%s] Saved key mismatch: %+v", tc.Title, k)
+				}
+
+				// The least privileged role is granted by default
+				bindings, err := models.GetAPIKeyRoleBindings(testutils.GetDB(), k.ID)
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if len(bindings) != 1 || bindings[0].Role != string(auth.RoleViewer) || bindings[0].ResourceID != nil {
+					t.Errorf("[%s] Role bindings mismatch: %+v", tc.Title, bindings)
+				}
+			},
+		},
+		{
+			Title: "Organization not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("org2")),
+			Body:       map[string]interface{}{},
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+		{
+			Title: "Invalid property key",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Path: makePath(testutils.GetUUID("org1")),
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{
+					"prop key": "value1",
+				},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `Invalid property key`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPost
+		testutils.RunTestCase(t, tc)
+	}
+}
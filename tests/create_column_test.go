@@ -46,6 +46,7 @@ func TestCreateColumn(t *testing.T) {
 			Output: map[string]interface{}{
 				"id":         testutils.UUID{},
 				"tableId":    testutils.GetUUID("table-01"),
+				"type":       nil,
 				"index":      float64(0),
 				"properties": map[string]interface{}{},
 				"createdAt":  testutils.Timestamp{},
@@ -82,6 +83,7 @@ func TestCreateColumn(t *testing.T) {
 			Output: map[string]interface{}{
 				"id":         testutils.UUID{},
 				"tableId":    testutils.GetUUID("table-01"),
+				"type":       nil,
 				"index":      float64(1),
 				"properties": map[string]interface{}{},
 				"createdAt":  testutils.Timestamp{},
@@ -121,6 +123,7 @@ func TestCreateColumn(t *testing.T) {
 			Output: map[string]interface{}{
 				"id":         testutils.UUID{},
 				"tableId":    testutils.GetUUID("table-01"),
+				"type":       nil,
 				"index":      float64(0),
 				"properties": map[string]interface{}{},
 				"createdAt":  testutils.Timestamp{},
@@ -179,6 +182,7 @@ func TestCreateColumn(t *testing.T) {
 			Output: map[string]interface{}{
 				"id":         testutils.UUID{},
 				"tableId":    testutils.GetUUID("table-01"),
+				"type":       nil,
 				"index":      float64(1),
 				"properties": map[string]interface{}{},
 				"createdAt":  testutils.Timestamp{},
@@ -254,6 +258,7 @@ func TestCreateColumn(t *testing.T) {
 			Output: map[string]interface{}{
 				"id":      testutils.UUID{},
 				"tableId": testutils.GetUUID("table-01"),
+				"type":    nil,
 				"index":   float64(0),
 				"properties": map[string]interface{}{
 					"key1": "value1",
@@ -262,6 +267,50 @@ func TestCreateColumn(t *testing.T) {
 				"updatedAt": testutils.Timestamp{},
 			},
 		},
+		{
+			Title: "Type",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				`)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"type": "number",
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":         testutils.UUID{},
+				"tableId":    testutils.GetUUID("table-01"),
+				"type":       "number",
+				"index":      float64(0),
+				"properties": map[string]interface{}{},
+				"createdAt":  testutils.Timestamp{},
+				"updatedAt":  testutils.Timestamp{},
+			},
+		},
+		{
+			Title: "Invalid type",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				`)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"type": "integer",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request body`},
+			},
+		},
 		{
 			Title: "Invalid property key",
 			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
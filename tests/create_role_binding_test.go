@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestCreateRoleBinding(t *testing.T) {
+	makePath := func(organizationID uuid.UUID) string {
+		return fmt.Sprintf("/organizations/%s/role-bindings", organizationID)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "On folder",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: folder-01
+				    apiKeys:
+				      - id: key-01
+				        key: xb_key1aaaaaaaaaaaa
+				        role: viewer
+				`)
+			},
+			Path: makePath(testutils.GetUUID("org1")),
+			Body: map[string]interface{}{
+				"apiKeyId":   testutils.GetUUID("key-01"),
+				"resourceId": testutils.GetUUID("folder-01"),
+				"role":       "editor",
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"apiKeyId":       testutils.GetUUID("key-01"),
+				"resourceId":     testutils.GetUUID("folder-01"),
+				"role":           "editor",
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := testutils.ServeGet(router, makePath(testutils.GetUUID("org1")), url.Values{
+					"resourceId": []string{testutils.GetUUID("folder-01").String()},
+				})
+				if diff := testutils.CompareJson(output, res["roleBindings"].([]interface{})[0]); diff != "" {
+					t.Errorf("[%s] Reacquired response mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title: "On organization",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    apiKeys:
+				      - id: key-01
+				        key: xb_key1aaaaaaaaaaaa
+				        role: none
+				`)
+			},
+			Path: makePath(testutils.GetUUID("org1")),
+			Body: map[string]interface{}{
+				"apiKeyId": testutils.GetUUID("key-01"),
+				"role":     "viewer",
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"apiKeyId":       testutils.GetUUID("key-01"),
+				"resourceId":     nil,
+				"role":           "viewer",
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+			},
+		},
+		{
+			Title: "Already granted",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    apiKeys:
+				      - id: key-01
+				        key: xb_key1aaaaaaaaaaaa
+				        role: viewer
+				`)
+			},
+			Path: makePath(testutils.GetUUID("org1")),
+			Body: map[string]interface{}{
+				"apiKeyId": testutils.GetUUID("key-01"),
+				"role":     "editor",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Role is already granted to the api key on the resource",
+			},
+		},
+		{
+			Title: "API key of another organization",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				  - id: org2
+				    apiKeys:
+				      - id: key-01
+				        key: xb_key1aaaaaaaaaaaa
+				`)
+			},
+			Path: makePath(testutils.GetUUID("org1")),
+			Body: map[string]interface{}{
+				"apiKeyId": testutils.GetUUID("key-01"),
+				"role":     "editor",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "API key not found",
+			},
+		},
+		{
+			Title: "Resource of another organization",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    apiKeys:
+				      - id: key-01
+				        key: xb_key1aaaaaaaaaaaa
+				  - id: org2
+				    tables:
+				      - id: table-01
+				`)
+			},
+			Path: makePath(testutils.GetUUID("org1")),
+			Body: map[string]interface{}{
+				"apiKeyId":   testutils.GetUUID("key-01"),
+				"resourceId": testutils.GetUUID("table-01"),
+				"role":       "editor",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Resource not found",
+			},
+		},
+		{
+			Title: "Invalid role",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    apiKeys:
+				      - id: key-01
+				        key: xb_key1aaaaaaaaaaaa
+				`)
+			},
+			Path: makePath(testutils.GetUUID("org1")),
+			Body: map[string]interface{}{
+				"apiKeyId": testutils.GetUUID("key-01"),
+				"role":     "admin",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request body`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPost
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestCreateTablePolicy(t *testing.T) {
+	makePath := func(tableID uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/policies", tableID)
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		        columns:
+		          - id: column-01
+		`)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "General case",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"where": makeJSON(`
+				eq:
+				  - {column: {{ .column }} }
+				  - {attribute: customer}
+				`, map[string]interface{}{
+					"column": testutils.GetUUID("column-01"),
+				}),
+				"properties": map[string]interface{}{
+					"name": "own records",
+				},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":      testutils.UUID{},
+				"tableId": testutils.GetUUID("table-01"),
+				"where": map[string]interface{}{
+					"eq": []interface{}{
+						map[string]interface{}{"column": testutils.GetUUID("column-01").String()},
+						map[string]interface{}{"attribute": "customer"},
+					},
+				},
+				"properties": map[string]interface{}{
+					"name": "own records",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := testutils.ServeGet(router, makePath(testutils.GetUUID("table-01")), nil)
+				if diff := testutils.CompareJson(output, res["policies"].([]interface{})[0]); diff != "" {
+					t.Errorf("[%s] Reacquired response mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title:   "Unknown column",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"where": makeJSON(`
+				eq:
+				  - {column: {{ .column }} }
+				  - {value: a}
+				`, map[string]interface{}{
+					"column": uuid.New(),
+				}),
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid where`},
+			},
+		},
+		{
+			Title:   "Invalid expression",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"where": map[string]interface{}{
+					"unknown": 1,
+				},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid where`},
+			},
+		},
+		{
+			Title:   "No where",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request body`},
+			},
+		},
+		{
+			Title:      "Table not found",
+			Prepare:    prepare,
+			Path:       makePath(uuid.New()),
+			Body:       map[string]interface{}{"where": map[string]interface{}{"value": true}},
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Table not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPost
+		testutils.RunTestCase(t, tc)
+	}
+}
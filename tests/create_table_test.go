@@ -179,6 +179,7 @@ func TestCreateTable(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.UUID{},
 						"tableId":    testutils.UUID{},
+						"type":       nil,
 						"index":      float64(0),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -187,6 +188,7 @@ func TestCreateTable(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.UUID{},
 						"tableId":    testutils.UUID{},
+						"type":       nil,
 						"index":      float64(1),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -279,6 +281,7 @@ func TestCreateTable(t *testing.T) {
 					map[string]interface{}{
 						"id":      testutils.UUID{},
 						"tableId": testutils.UUID{},
+						"type":    nil,
 						"index":   float64(0),
 						"properties": map[string]interface{}{
 							"key1": "value1",
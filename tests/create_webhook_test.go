@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/webhooks"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"url":            "https://example.com/hook",
+				"secret":         "my-secret",
+				"events":         []string{"record.*", "table.deleted"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"tableId":        nil,
+				"url":            "https://example.com/hook",
+				"secret":         "my-secret",
+				"events":         []interface{}{"record.*", "table.deleted"},
+				"properties":     map[string]interface{}{},
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				// Reacquire and compare with the previous response except the secret, which is not shown again
+				delete(output, "secret")
+				res := testutils.ServeGet(router, fmt.Sprintf("/webhooks/%s", output["id"]), nil)
+				if diff := testutils.CompareJson(output, res); diff != "" {
+					t.Errorf("[%s] Reacquired response mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title: "Table webhook",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"tableId":        testutils.GetUUID("table-01"),
+				"url":            "https://example.com/hook",
+				"properties": map[string]interface{}{
+					"key1": "value1",
+				},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"tableId":        testutils.GetUUID("table-01"),
+				"url":            "https://example.com/hook",
+				"secret":         testutils.Regexp{Pattern: `^[0-9a-f]{64}$`},
+				"events":         []interface{}{},
+				"properties": map[string]interface{}{
+					"key1": "value1",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+		},
+		{
+			Title: "Invalid url",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"url":            "example",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request body`},
+			},
+		},
+		{
+			Title: "Invalid url scheme",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"url":            "file:///etc/passwd",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Url scheme must be http or https",
+			},
+		},
+		{
+			Title: "Private address",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"url":            "http://169.254.169.254/latest/meta-data",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Url host is not allowed",
+			},
+		},
+		{
+			Title: "Loopback address",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"url":            "http://localhost:8080/hook",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Url host is not allowed",
+			},
+		},
+		{
+			Title: "Private address allowed by config",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Config: &api.Config{
+				Webhook: webhooks.Config{
+					AllowPrivateNetworks: true,
+				},
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"url":            "http://10.0.0.1/hook",
+				"secret":         "my-secret",
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"tableId":        nil,
+				"url":            "http://10.0.0.1/hook",
+				"secret":         "my-secret",
+				"events":         []interface{}{},
+				"properties":     map[string]interface{}{},
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+			},
+		},
+		{
+			Title: "Invalid event type",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"url":            "https://example.com/hook",
+				"events":         []string{"record.created"},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Invalid event type: record.created",
+			},
+		},
+		{
+			Title: "Organization not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org2"),
+				"url":            "https://example.com/hook",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Organization not found",
+			},
+		},
+		{
+			Title: "Table not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"tableId":        testutils.GetUUID("table-01"),
+				"url":            "https://example.com/hook",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Table not found",
+			},
+		},
+		{
+			Title: "Another organization's table",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				  - id: org2
+				    tables:
+				      - id: table-01
+				`)
+			},
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"tableId":        testutils.GetUUID("table-01"),
+				"url":            "https://example.com/hook",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Cannot subscribe to another organization's table",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPost
+		tc.Path = "/webhooks"
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/google/uuid"
+	"github.com/tsujio/x-base/api/models"
 	"github.com/tsujio/x-base/tests/testutils"
 )
 
@@ -43,6 +44,14 @@ func TestDeleteColumn(t *testing.T) {
 				          - id: column-02
 				          - id: column-03
 				          - id: column-04
+				        records:
+				          - data: [a1, b1, c1, d1]
+				          - data: [a2, b2, c2, d2]
+				      - id: table-02
+				        columns:
+				          - id: column-05
+				        records:
+				          - data: [e1]
 				`)
 			},
 			Path:       makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-02")),
@@ -53,6 +62,51 @@ func TestDeleteColumn(t *testing.T) {
 					testutils.GetUUID("column-03"),
 					testutils.GetUUID("column-04"),
 				})
+
+				// Check column data purged
+				var jobID models.UUID
+				err := testutils.GetDB().Raw("SELECT id FROM jobs WHERE type = ?", "purge_column_data").Row().Scan(&jobID)
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				job := waitJob(router, uuid.UUID(jobID))
+				expected := map[string]interface{}{
+					"status": "succeeded",
+					"progress": map[string]interface{}{
+						"purgedRecords": float64(2),
+					},
+					"result": map[string]interface{}{
+						"purgedRecords": float64(2),
+					},
+				}
+				actual := map[string]interface{}{
+					"status":   job["status"],
+					"progress": job["progress"],
+					"result":   job["result"],
+				}
+				if diff := testutils.CompareJson(expected, actual); diff != "" {
+					t.Errorf("[%s] Job mismatch:\n%s", tc.Title, diff)
+				}
+				for _, c := range []struct {
+					column string
+					count  int64
+				}{
+					{"column-01", 2},
+					{"column-02", 0},
+					{"column-05", 1},
+				} {
+					var count int64
+					err := testutils.GetDB().Raw(
+						"SELECT COUNT(*) FROM table_records WHERE data LIKE ?",
+						fmt.Sprintf(`%%"%s"%%`, testutils.GetUUID(c.column)),
+					).Scan(&count).Error
+					if err != nil {
+						t.Fatalf("[%s] %+v", tc.Title, err)
+					}
+					if count != c.count {
+						t.Errorf("[%s] # of records having %s mismatch: expected=%d, actual=%d", tc.Title, c.column, c.count, count)
+					}
+				}
 			},
 		},
 		{
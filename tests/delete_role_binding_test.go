@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestDeleteRoleBinding(t *testing.T) {
+	makePath := func(organizationID, roleBindingID uuid.UUID) string {
+		return fmt.Sprintf("/organizations/%s/role-bindings/%s", organizationID, roleBindingID)
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		    apiKeys:
+		      - id: key-01
+		        key: xb_key1aaaaaaaaaaaa
+		        role: none
+		        roleBindings:
+		          - id: binding-01
+		            resourceId: table-01
+		            role: viewer
+		  - id: org2
+		`)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "General case",
+			Prepare:    prepare,
+			Path:       makePath(testutils.GetUUID("org1"), testutils.GetUUID("binding-01")),
+			StatusCode: http.StatusOK,
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := testutils.ServeGet(router, fmt.Sprintf("/organizations/%s/role-bindings", testutils.GetUUID("org1")), url.Values{
+					"apiKeyId": []string{testutils.GetUUID("key-01").String()},
+				})
+				if res["totalCount"] != float64(0) {
+					t.Errorf("[%s] Role binding is not deleted:\n%v", tc.Title, res)
+				}
+			},
+		},
+		{
+			Title:      "Another organization",
+			Prepare:    prepare,
+			Path:       makePath(testutils.GetUUID("org2"), testutils.GetUUID("binding-01")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodDelete
+		testutils.RunTestCase(t, tc)
+	}
+}
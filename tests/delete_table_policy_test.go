@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestDeleteTablePolicy(t *testing.T) {
+	makePath := func(tableID, policyID uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/policies/%s", tableID, policyID)
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		        policies:
+		          - id: policy-01
+		            where: {value: true}
+		      - id: table-02
+		`)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "General case",
+			Prepare:    prepare,
+			Path:       makePath(testutils.GetUUID("table-01"), testutils.GetUUID("policy-01")),
+			StatusCode: http.StatusOK,
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := testutils.ServeGet(router, fmt.Sprintf("/tables/%s/policies", testutils.GetUUID("table-01")), nil)
+				if len(res["policies"].([]interface{})) != 0 {
+					t.Errorf("[%s] Policy is not deleted:\n%v", tc.Title, res)
+				}
+			},
+		},
+		{
+			Title:      "Policy of another table",
+			Prepare:    prepare,
+			Path:       makePath(testutils.GetUUID("table-02"), testutils.GetUUID("policy-01")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Policy not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodDelete
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestDeleteWebhook(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/webhooks/%s", id)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				        deliveries:
+				          - eventType: record.inserted
+				            status: succeeded
+				      - id: webhook-02
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("webhook-01")),
+			StatusCode: http.StatusOK,
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				// Reacquire
+				res := testutils.ServeGet(router, makePath(testutils.GetUUID("webhook-01")), nil)
+				if res != nil {
+					t.Errorf("[%s] Not deleted", tc.Title)
+				}
+
+				// Did not delete other data
+				res = testutils.ServeGet(router, makePath(testutils.GetUUID("webhook-02")), nil)
+				if res == nil {
+					t.Errorf("[%s] Deleted other data", tc.Title)
+				}
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("webhook-02")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodDelete
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,348 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/webhooks"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests chan webhookRequest
+}
+
+func newWebhookReceiver() *webhookReceiver {
+	recv := &webhookReceiver{
+		requests: make(chan webhookRequest, 10),
+	}
+	recv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		recv.mu.Lock()
+		status := http.StatusOK
+		if len(recv.statuses) > 0 {
+			status = recv.statuses[0]
+			recv.statuses = recv.statuses[1:]
+		}
+		recv.mu.Unlock()
+
+		w.WriteHeader(status)
+		recv.requests <- webhookRequest{header: r.Header, body: body}
+	}))
+	return recv
+}
+
+// reset drains received requests and sets response statuses returned in order.
+func (recv *webhookReceiver) reset(statuses ...int) {
+	recv.mu.Lock()
+	defer recv.mu.Unlock()
+	recv.statuses = statuses
+	for len(recv.requests) > 0 {
+		<-recv.requests
+	}
+}
+
+func (recv *webhookReceiver) receive(timeout time.Duration) *webhookRequest {
+	select {
+	case req := <-recv.requests:
+		return &req
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func waitWebhookDelivery(router http.Handler, webhookID uuid.UUID) map[string]interface{} {
+	for i := 0; i < 100; i++ {
+		res := testutils.ServeGet(router, fmt.Sprintf("/webhooks/%s/deliveries", webhookID), nil)
+		deliveries := res["deliveries"].([]interface{})
+		if len(deliveries) > 0 {
+			d := deliveries[0].(map[string]interface{})
+			if d["status"] != "pending" {
+				return d
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	recv := newWebhookReceiver()
+	defer recv.Close()
+
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/query", id)
+	}
+
+	makeFixture := func(events string) string {
+		return fmt.Sprintf(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		        columns:
+		          - id: column-01
+		        records:
+		          - id: record-01
+		            data: [1]
+		          - id: record-02
+		            data: [2]
+		    webhooks:
+		      - id: webhook-01
+		        tableId: table-01
+		        url: %s
+		        secret: my-secret
+		        events: %s
+		`, recv.URL, events)
+	}
+
+	checkRequest := func(tc *testutils.APITestCase, req *webhookRequest, eventType string) map[string]interface{} {
+		if req == nil {
+			t.Fatalf("[%s] Webhook not received", tc.Title)
+		}
+		if req.header.Get(webhooks.EventHeader) != eventType {
+			t.Errorf("[%s] Event header mismatch: %s", tc.Title, req.header.Get(webhooks.EventHeader))
+		}
+		signature := "sha256=" + webhooks.Sign("my-secret", req.header.Get(webhooks.TimestampHeader), req.body)
+		if req.header.Get(webhooks.SignatureHeader) != signature {
+			t.Errorf("[%s] Signature mismatch: %s", tc.Title, req.header.Get(webhooks.SignatureHeader))
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal(req.body, &payload); err != nil {
+			t.Fatalf("[%s] %+v", tc.Title, err)
+		}
+		return payload
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "Record inserted",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				recv.reset()
+				return testutils.LoadFixture(makeFixture(`[record.*]`))
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: makeJSON(`
+			insert:
+			  columns:
+			    - column: {{ .column01 }}
+			  values:
+			    - - value: 3
+			`, map[string]interface{}{
+				"column01": testutils.GetUUID("column-01"),
+			}),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"recordIds": []interface{}{
+					testutils.UUID{},
+				},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				payload := checkRequest(tc, recv.receive(10*time.Second), "record.inserted")
+				expected := map[string]interface{}{
+					"id":             testutils.UUID{},
+					"type":           "record.inserted",
+					"organizationId": testutils.GetUUID("org1"),
+					"tableId":        testutils.GetUUID("table-01"),
+					"data": map[string]interface{}{
+						"recordIds": output["recordIds"],
+					},
+					"createdAt": testutils.Timestamp{},
+				}
+				if diff := testutils.CompareJson(expected, payload); diff != "" {
+					t.Errorf("[%s] Payload mismatch:\n%s", tc.Title, diff)
+				}
+
+				delivery := waitWebhookDelivery(router, testutils.GetUUID("webhook-01"))
+				if delivery["status"] != "succeeded" || delivery["attempts"] != float64(1) {
+					t.Errorf("[%s] Unexpected delivery: %v", tc.Title, delivery)
+				}
+			},
+		},
+		{
+			Title: "Record deleted",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				recv.reset()
+				return testutils.LoadFixture(makeFixture(`[record.deleted]`))
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: makeJSON(`
+			delete:
+			  where:
+			    eq:
+			      - {column: {{ .column01 }} }
+			      - {value: 2}
+			`, map[string]interface{}{
+				"column01": testutils.GetUUID("column-01"),
+			}),
+			StatusCode: http.StatusOK,
+			Output:     map[string]interface{}{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				payload := checkRequest(tc, recv.receive(10*time.Second), "record.deleted")
+				expected := map[string]interface{}{
+					"recordIds": []interface{}{
+						testutils.GetUUID("record-02"),
+					},
+				}
+				if diff := testutils.CompareJson(expected, payload["data"]); diff != "" {
+					t.Errorf("[%s] Payload mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title: "Unsubscribed event",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				recv.reset()
+				return testutils.LoadFixture(makeFixture(`[table.*]`))
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: makeJSON(`
+			insert:
+			  columns:
+			    - column: {{ .column01 }}
+			  values:
+			    - - value: 3
+			`, map[string]interface{}{
+				"column01": testutils.GetUUID("column-01"),
+			}),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"recordIds": []interface{}{
+					testutils.UUID{},
+				},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				if req := recv.receive(time.Second); req != nil {
+					t.Errorf("[%s] Received unsubscribed event: %s", tc.Title, req.body)
+				}
+			},
+		},
+		{
+			Title: "Retry",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				recv.reset(http.StatusInternalServerError)
+				return testutils.LoadFixture(makeFixture(`[]`))
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: makeJSON(`
+			insert:
+			  columns:
+			    - column: {{ .column01 }}
+			  values:
+			    - - value: 3
+			`, map[string]interface{}{
+				"column01": testutils.GetUUID("column-01"),
+			}),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"recordIds": []interface{}{
+					testutils.UUID{},
+				},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				first := checkRequest(tc, recv.receive(10*time.Second), "record.inserted")
+				second := checkRequest(tc, recv.receive(10*time.Second), "record.inserted")
+				if first["id"] != second["id"] {
+					t.Errorf("[%s] Event id changed on retry: %v, %v", tc.Title, first["id"], second["id"])
+				}
+
+				delivery := waitWebhookDelivery(router, testutils.GetUUID("webhook-01"))
+				expected := map[string]interface{}{
+					"id":             testutils.UUID{},
+					"webhookId":      testutils.GetUUID("webhook-01"),
+					"eventId":        first["id"],
+					"eventType":      "record.inserted",
+					"payload":        testutils.AnyVal{},
+					"status":         "succeeded",
+					"attempts":       float64(2),
+					"responseStatus": float64(http.StatusOK),
+					"error":          nil,
+					"createdAt":      testutils.Timestamp{},
+					"updatedAt":      testutils.Timestamp{},
+					"deliveredAt":    testutils.Timestamp{},
+				}
+				if diff := testutils.CompareJson(expected, delivery); diff != "" {
+					t.Errorf("[%s] Delivery mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title: "Private address is blocked",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				recv.reset()
+				return testutils.LoadFixture(makeFixture(`[]`))
+			},
+			Config: &api.Config{},
+			Path:   makePath(testutils.GetUUID("table-01")),
+			Body: makeJSON(`
+			insert:
+			  columns:
+			    - column: {{ .column01 }}
+			  values:
+			    - - value: 3
+			`, map[string]interface{}{
+				"column01": testutils.GetUUID("column-01"),
+			}),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"recordIds": []interface{}{
+					testutils.UUID{},
+				},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				var delivery map[string]interface{}
+				for i := 0; i < 100 && delivery == nil; i++ {
+					res := testutils.ServeGet(router, fmt.Sprintf("/webhooks/%s/deliveries", testutils.GetUUID("webhook-01")), nil)
+					if deliveries := res["deliveries"].([]interface{}); len(deliveries) > 0 {
+						if d := deliveries[0].(map[string]interface{}); d["attempts"] != float64(0) {
+							delivery = d
+						}
+					}
+					time.Sleep(100 * time.Millisecond)
+				}
+				if delivery == nil {
+					t.Fatalf("[%s] Delivery not attempted", tc.Title)
+				}
+				if msg, _ := delivery["error"].(string); !strings.Contains(msg, "Address is not allowed") {
+					t.Errorf("[%s] Unexpected delivery: %v", tc.Title, delivery)
+				}
+				if req := recv.receive(time.Second); req != nil {
+					t.Errorf("[%s] Received webhook sent to private address: %s", tc.Title, req.body)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPost
+		if tc.Config == nil {
+			// The receiver listens on the loopback address
+			tc.Config = &api.Config{
+				Webhook: webhooks.Config{
+					AllowPrivateNetworks: true,
+				},
+			}
+		}
+		testutils.RunTestCase(t, tc)
+	}
+}
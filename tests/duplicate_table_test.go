@@ -0,0 +1,319 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestDuplicateTable(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/duplicate", id)
+	}
+
+	fixture := `
+	organizations:
+	  - id: org1
+	    tables:
+	      - id: folder-01
+	        children:
+	          - id: table-01
+	            properties:
+	              name: Users
+	              key1: value1
+	            columns:
+	              - id: column-01
+	                properties:
+	                  name: Name
+	              - id: column-02
+	                properties:
+	                  name: Score
+	            records:
+	              - data: [alice, 80]
+	                properties:
+	                  key1: p1
+	              - data: [bob, 92]
+	      - id: folder-02
+	  - id: org2
+	    tables:
+	      - id: folder-03
+	`
+
+	makeColumns := func(tableID interface{}) []interface{} {
+		return []interface{}{
+			map[string]interface{}{
+				"id":      testutils.UUID{},
+				"tableId": tableID,
+				"type":    nil,
+				"index":   float64(0),
+				"properties": map[string]interface{}{
+					"name": "Name",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+			map[string]interface{}{
+				"id":      testutils.UUID{},
+				"tableId": tableID,
+				"type":    nil,
+				"index":   float64(1),
+				"properties": map[string]interface{}{
+					"name": "Score",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+		}
+	}
+
+	selectRecords := func(router http.Handler, output map[string]interface{}) map[string]interface{} {
+		columns := output["columns"].([]interface{})
+		return selectTable(router, uuid.MustParse(output["id"].(string)), makeJSON(`
+		select:
+		  columns:
+		    - column: {{ .column01 }}
+		    - column: {{ .column02 }}
+		    - property: key1
+		  orderBy:
+		    - key:
+		        column: {{ .column01 }}
+		`, map[string]interface{}{
+			"column01": columns[0].(map[string]interface{})["id"],
+			"column02": columns[1].(map[string]interface{})["id"],
+		}))
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "Include records",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"parentFolderId": testutils.GetUUID("folder-02"),
+				"properties": map[string]interface{}{
+					"name": "Users (copy)",
+				},
+				"includeRecords": true,
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "table",
+				"path": []interface{}{
+					map[string]interface{}{
+						"id":         testutils.GetUUID("folder-02"),
+						"type":       "folder",
+						"properties": map[string]interface{}{},
+					},
+				},
+				"columns": makeColumns(testutils.UUID{}),
+				"properties": map[string]interface{}{
+					"name": "Users (copy)",
+					"key1": "value1",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := testutils.ServeGet(router, fmt.Sprintf("/tables/%s", output["id"]), nil)
+				if diff := testutils.CompareJson(output, res); diff != "" {
+					t.Errorf("[%s] Reacquired response mismatch:\n%s", tc.Title, diff)
+				}
+
+				for _, c := range output["columns"].([]interface{}) {
+					id := c.(map[string]interface{})["id"]
+					if id == testutils.GetUUID("column-01").String() || id == testutils.GetUUID("column-02").String() {
+						t.Errorf("[%s] Column id is not renewed: %v", tc.Title, id)
+					}
+				}
+
+				res = selectRecords(router, output)
+				expected := map[string]interface{}{
+					"records": []interface{}{
+						[]interface{}{"alice", float64(80), "p1"},
+						[]interface{}{"bob", float64(92), nil},
+					},
+					"limit": float64(10),
+				}
+				if diff := testutils.CompareJson(expected, res); diff != "" {
+					t.Errorf("[%s] Selected records mismatch:\n%s", tc.Title, diff)
+				}
+
+			},
+		},
+		{
+			Title: "Exclude records",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path:       makePath(testutils.GetUUID("table-01")),
+			Body:       map[string]interface{}{},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "table",
+				"path": []interface{}{
+					map[string]interface{}{
+						"id":         testutils.GetUUID("folder-01"),
+						"type":       "folder",
+						"properties": map[string]interface{}{},
+					},
+				},
+				"columns": makeColumns(testutils.UUID{}),
+				"properties": map[string]interface{}{
+					"name": "Users",
+					"key1": "value1",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := selectRecords(router, output)
+				expected := map[string]interface{}{
+					"records": []interface{}{},
+					"limit":   float64(10),
+				}
+				if diff := testutils.CompareJson(expected, res); diff != "" {
+					t.Errorf("[%s] Selected records mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title: "Records over a batch",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				if err := testutils.LoadFixture(fixture); err != nil {
+					return err
+				}
+				var records []models.TableRecord
+				for i := 0; i < 1200; i++ {
+					records = append(records, models.TableRecord{
+						ID:      models.UUID(uuid.New()),
+						TableID: models.UUID(testutils.GetUUID("table-01")),
+						Data:    models.JSON(fmt.Sprintf(`{"%s": "r%04d"}`, testutils.GetUUID("column-01"), i)),
+					})
+				}
+				return db.CreateInBatches(&records, 100).Error
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"includeRecords": true,
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "table",
+				"path": []interface{}{
+					map[string]interface{}{
+						"id":         testutils.GetUUID("folder-01"),
+						"type":       "folder",
+						"properties": map[string]interface{}{},
+					},
+				},
+				"columns": makeColumns(testutils.UUID{}),
+				"properties": map[string]interface{}{
+					"name": "Users",
+					"key1": "value1",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				var data []string
+				err := testutils.GetDB().Model(&models.TableRecord{}).
+					Where("table_id = ?", models.UUID(uuid.MustParse(output["id"].(string)))).
+					Pluck("data", &data).
+					Error
+				if err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				distinct := make(map[string]bool)
+				for _, d := range data {
+					distinct[d] = true
+				}
+				if len(data) != 1202 || len(distinct) != 1202 {
+					t.Errorf("[%s] Copied records mismatch: count=%d, distinct=%d", tc.Title, len(data), len(distinct))
+				}
+			},
+		},
+		{
+			Title: "Root folder",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"parentFolderId": uuid.Nil,
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "table",
+				"path":           []interface{}{},
+				"columns":        makeColumns(testutils.UUID{}),
+				"properties": map[string]interface{}{
+					"name": "Users",
+					"key1": "value1",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+		},
+		{
+			Title: "Destination folder not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"parentFolderId": testutils.GetUUID("folder-04"),
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Destination folder not found",
+			},
+		},
+		{
+			Title: "Another organization",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"parentFolderId": testutils.GetUUID("folder-03"),
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Cannot duplicate to another organization",
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path:       makePath(testutils.GetUUID("table-02")),
+			Body:       map[string]interface{}{},
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPost
+		testutils.RunTestCase(t, tc)
+	}
+}
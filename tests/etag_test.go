@@ -0,0 +1,351 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestETag(t *testing.T) {
+	tablePath := fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01"))
+	columnPath := fmt.Sprintf("/tables/%s/columns/%s", testutils.GetUUID("table-01"), testutils.GetUUID("column-01"))
+	folderPath := fmt.Sprintf("/folders/%s", testutils.GetUUID("folder-01"))
+	organizationPath := fmt.Sprintf("/organizations/%s", testutils.GetUUID("org1"))
+
+	getETag := func(db *gorm.DB, path string) (string, error) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		api.CreateRouter(db, nil).ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return "", fmt.Errorf("Failed to get %s: status=%d", path, w.Code)
+		}
+		tag := w.Header().Get("ETag")
+		if tag == "" {
+			return "", fmt.Errorf("No etag: path=%s", path)
+		}
+		return tag, nil
+	}
+
+	prepareWith := func(path, header string) func(*testutils.APITestCase, *gorm.DB) error {
+		return func(tc *testutils.APITestCase, db *gorm.DB) error {
+			err := testutils.LoadFixture(`
+			organizations:
+			  - id: org1
+			    tables:
+			      - id: folder-01
+			        type: folder
+			        children:
+			          - id: table-01
+			            columns:
+			              - id: column-01
+			`)
+			if err != nil {
+				return err
+			}
+			tag, err := getETag(db, path)
+			if err != nil {
+				return err
+			}
+			tc.Header = http.Header{header: []string{tag}}
+			return nil
+		}
+	}
+	stale := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		if err := prepareWith(tablePath, "If-Match")(tc, db); err != nil {
+			return err
+		}
+		tc.Header = http.Header{
+			"If-Match":      []string{`"0123456789abcdef0123456789abcdef"`},
+			"If-None-Match": []string{`"0123456789abcdef0123456789abcdef"`},
+		}
+		return nil
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Table not modified",
+			Prepare:    prepareWith(tablePath, "If-None-Match"),
+			Method:     http.MethodGet,
+			Path:       tablePath,
+			StatusCode: http.StatusNotModified,
+		},
+		{
+			Title:      "Table modified",
+			Prepare:    stale,
+			Method:     http.MethodGet,
+			Path:       tablePath,
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+		},
+		{
+			Title:      "Column not modified",
+			Prepare:    prepareWith(columnPath, "If-None-Match"),
+			Method:     http.MethodGet,
+			Path:       columnPath,
+			StatusCode: http.StatusNotModified,
+		},
+		{
+			Title:      "Folder not modified",
+			Prepare:    prepareWith(folderPath, "If-None-Match"),
+			Method:     http.MethodGet,
+			Path:       folderPath,
+			StatusCode: http.StatusNotModified,
+		},
+		{
+			Title:      "Organization not modified",
+			Prepare:    prepareWith(organizationPath, "If-None-Match"),
+			Method:     http.MethodGet,
+			Path:       organizationPath,
+			StatusCode: http.StatusNotModified,
+		},
+		{
+			Title:   "Update table with current etag",
+			Prepare: prepareWith(tablePath, "If-Match"),
+			Method:  http.MethodPatch,
+			Path:    tablePath,
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := testutils.ServeGet(router, tablePath, nil)
+				if res["properties"].(map[string]interface{})["key"] != "value" {
+					t.Errorf("[%s] Table not updated: %v", tc.Title, res)
+				}
+			},
+		},
+		{
+			Title:   "Update table with stale etag",
+			Prepare: stale,
+			Method:  http.MethodPatch,
+			Path:    tablePath,
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusPreconditionFailed,
+			Output: map[string]interface{}{
+				"message": "Precondition failed",
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := testutils.ServeGet(router, tablePath, nil)
+				if _, exists := res["properties"].(map[string]interface{})["key"]; exists {
+					t.Errorf("[%s] Table updated: %v", tc.Title, res)
+				}
+			},
+		},
+		{
+			Title: "Update table with wildcard",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				if err := stale(tc, db); err != nil {
+					return err
+				}
+				tc.Header = http.Header{"If-Match": []string{"*"}}
+				return nil
+			},
+			Method: http.MethodPatch,
+			Path:   tablePath,
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+		},
+		{
+			Title:   "Update column with stale etag",
+			Prepare: stale,
+			Method:  http.MethodPatch,
+			Path:    columnPath,
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusPreconditionFailed,
+			Output: map[string]interface{}{
+				"message": "Precondition failed",
+			},
+		},
+		{
+			Title:   "Update folder with stale etag",
+			Prepare: stale,
+			Method:  http.MethodPatch,
+			Path:    folderPath,
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusPreconditionFailed,
+			Output: map[string]interface{}{
+				"message": "Precondition failed",
+			},
+		},
+		{
+			Title:   "Update organization with current etag",
+			Prepare: prepareWith(organizationPath, "If-Match"),
+			Method:  http.MethodPatch,
+			Path:    organizationPath,
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+		},
+		{
+			Title:   "Update organization with stale etag",
+			Prepare: stale,
+			Method:  http.MethodPatch,
+			Path:    organizationPath,
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusPreconditionFailed,
+			Output: map[string]interface{}{
+				"message": "Precondition failed",
+			},
+		},
+		{
+			Title:      "Delete table with current etag",
+			Prepare:    prepareWith(tablePath, "If-Match"),
+			Method:     http.MethodDelete,
+			Path:       tablePath,
+			StatusCode: http.StatusOK,
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				if res := testutils.ServeGet(router, tablePath, nil); res != nil {
+					t.Errorf("[%s] Table not deleted: %v", tc.Title, res)
+				}
+			},
+		},
+		{
+			Title:      "Delete table with stale etag",
+			Prepare:    stale,
+			Method:     http.MethodDelete,
+			Path:       tablePath,
+			StatusCode: http.StatusPreconditionFailed,
+			Output: map[string]interface{}{
+				"message": "Precondition failed",
+			},
+		},
+		{
+			Title:      "Delete column with stale etag",
+			Prepare:    stale,
+			Method:     http.MethodDelete,
+			Path:       columnPath,
+			StatusCode: http.StatusPreconditionFailed,
+			Output: map[string]interface{}{
+				"message": "Precondition failed",
+			},
+		},
+		{
+			Title:      "Delete folder with stale etag",
+			Prepare:    stale,
+			Method:     http.MethodDelete,
+			Path:       folderPath,
+			StatusCode: http.StatusPreconditionFailed,
+			Output: map[string]interface{}{
+				"message": "Precondition failed",
+			},
+		},
+		{
+			Title:      "Delete organization with stale etag",
+			Prepare:    stale,
+			Method:     http.MethodDelete,
+			Path:       organizationPath,
+			StatusCode: http.StatusPreconditionFailed,
+			Output: map[string]interface{}{
+				"message": "Precondition failed",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
+
+func TestETagConcurrentUpdates(t *testing.T) {
+	const concurrency = 10
+
+	paths := []string{
+		fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+		fmt.Sprintf("/tables/%s/columns/%s", testutils.GetUUID("table-01"), testutils.GetUUID("column-01")),
+		fmt.Sprintf("/folders/%s", testutils.GetUUID("folder-01")),
+		fmt.Sprintf("/organizations/%s", testutils.GetUUID("org1")),
+	}
+
+	// Delay queries so that the requests read the resource at the same time unless it is locked
+	db := testutils.GetDB()
+	err := db.Callback().Query().After("gorm:query").Register("test:delay", func(*gorm.DB) {
+		time.Sleep(10 * time.Millisecond)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Callback().Query().Remove("test:delay")
+
+	for _, path := range paths {
+		testutils.RefreshDB()
+		err := testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: folder-01
+		        type: folder
+		        children:
+		          - id: table-01
+		            columns:
+		              - id: column-01
+		`)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		router := api.CreateRouter(testutils.GetDB(), nil)
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		tag := w.Header().Get("ETag")
+		if w.Code != http.StatusOK || tag == "" {
+			t.Fatalf("[%s] Failed to get etag: status=%d", path, w.Code)
+		}
+
+		// Every request updates the properties with the same etag and only one of them should succeed
+		var wg sync.WaitGroup
+		codes := make(chan int, concurrency)
+		for i := 0; i < concurrency; i++ {
+			body, err := json.Marshal(map[string]interface{}{
+				"properties": map[string]interface{}{"writer": i},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodPatch, path, bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("If-Match", tag)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				codes <- w.Code
+			}()
+		}
+		wg.Wait()
+		close(codes)
+
+		counts := make(map[int]int)
+		for code := range codes {
+			counts[code]++
+		}
+		if counts[http.StatusOK] != 1 || counts[http.StatusPreconditionFailed] != concurrency-1 {
+			t.Errorf("[%s] Unexpected status codes: %v", path, counts)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestExportFolderXLSX(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/folders/%s/xlsx", id)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: folder-01
+				        children:
+				          - id: table-01
+				            properties:
+				              name: Users
+				            createdAt: "2021-10-01T00:00:00Z"
+				            columns:
+				              - id: column-01
+				                properties:
+				                  name: Name
+				            records:
+				              - data: [alice]
+				          - id: table-02
+				            properties:
+				              name: Users
+				            createdAt: "2021-10-02T00:00:00Z"
+				            columns:
+				              - id: column-02
+				                properties:
+				                  name: Price
+				            records:
+				              - data: [100]
+				          - id: folder-02
+				            children:
+				              - id: table-03
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("folder-01")),
+			StatusCode: http.StatusOK,
+			OutputXLSX: map[string][][]interface{}{
+				"Users": {
+					{"Name"},
+					{"alice"},
+				},
+				"Users (2)": {
+					{"Price"},
+					{"100"},
+				},
+			},
+		},
+		{
+			Title: "Root folder",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				        properties:
+				          name: Users
+				      - id: folder-01
+				        children:
+				          - id: table-02
+				  - id: org2
+				    tables:
+				      - id: table-03
+				`)
+			},
+			Path: makePath(uuid.Nil),
+			Query: url.Values{
+				"organizationId": []string{testutils.GetUUID("org1").String()},
+			},
+			StatusCode: http.StatusOK,
+			OutputXLSX: map[string][][]interface{}{
+				"Users": nil,
+			},
+		},
+		{
+			Title: "Empty folder",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: folder-01
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("folder-01")),
+			StatusCode: http.StatusOK,
+			OutputXLSX: map[string][][]interface{}{
+				"Sheet1": nil,
+			},
+		},
+		{
+			Title:      "Root folder without organizationId",
+			Path:       makePath(uuid.Nil),
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Organization id is required for root folder",
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: folder-01
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("folder-02")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestExportTableXLSX(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/xlsx", id)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				        properties:
+				          name: Table 1
+				        columns:
+				          - id: column-01
+				            properties:
+				              name: Name
+				          - id: column-02
+				            properties:
+				              name: Score
+				          - id: column-03
+				        records:
+				          - data: [alice, 80, true]
+				            createdAt: "2021-10-01T00:00:00Z"
+				          - data: [bob, 92.5, false]
+				            createdAt: "2021-10-02T00:00:00Z"
+				          - data: [null, null, null]
+				            createdAt: "2021-10-03T00:00:00Z"
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("table-01")),
+			StatusCode: http.StatusOK,
+			OutputXLSX: map[string][][]interface{}{
+				"Table 1": {
+					{"Name", "Score", testutils.GetUUID("column-03").String()},
+					{"alice", "80", "1"},
+					{"bob", "92.5", "0"},
+				},
+			},
+		},
+		{
+			Title: "No name",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("table-01")),
+			StatusCode: http.StatusOK,
+			OutputXLSX: map[string][][]interface{}{
+				testutils.GetUUID("table-01").String()[:31]: nil,
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("table-02")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
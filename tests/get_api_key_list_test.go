@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestGetAPIKeyList(t *testing.T) {
+	makePath := func(organizationID uuid.UUID) string {
+		return fmt.Sprintf("/organizations/%s/api-keys", organizationID)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    apiKeys:
+				      - id: key-01
+				        key: xb_key1aaaaaaaaaaaa
+				        createdAt: 2021-01-01T00:00:00Z
+				      - id: key-02
+				        key: xb_key2bbbbbbbbbbbb
+				        createdAt: 2021-01-02T00:00:00Z
+				      - id: key-03
+				        key: xb_key3cccccccccccc
+				        revoked: true
+				        createdAt: 2021-01-03T00:00:00Z
+				  - id: org2
+				    apiKeys:
+				      - id: key-04
+				        key: xb_key4dddddddddddd
+				`)
+			},
+			Path: makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"page":     []string{"2"},
+				"pageSize": []string{"2"},
+				"sort":     []string{"createdAt:asc"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"apiKeys": []interface{}{
+					map[string]interface{}{
+						"id":             testutils.GetUUID("key-03"),
+						"organizationId": testutils.GetUUID("org1"),
+						"prefix":         "xb_key3ccccc",
+						"properties":     map[string]interface{}{},
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+						"lastUsedAt":     nil,
+						"revokedAt":      testutils.Timestamp{},
+					},
+				},
+				"totalCount": float64(3),
+			},
+		},
+		{
+			Title: "No keys",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("org1")),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"apiKeys":    []interface{}{},
+				"totalCount": float64(0),
+			},
+		},
+		{
+			Title: "Organization not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("org2")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
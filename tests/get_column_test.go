@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestGetColumn(t *testing.T) {
+	makePath := func(tableID, columnID uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/columns/%s", tableID, columnID)
+	}
+
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		        columns:
+		          - id: column-01
+		          - id: column-02
+		            type: text
+		            properties:
+		              key: value
+		      - id: table-02
+		        columns:
+		          - id: column-03
+		`)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "General case",
+			Prepare:    prepare,
+			Path:       makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-02")),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":      testutils.GetUUID("column-02"),
+				"tableId": testutils.GetUUID("table-01"),
+				"type":    "text",
+				"index":   float64(1),
+				"properties": map[string]interface{}{
+					"key": "value",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+		},
+		{
+			Title:      "Column of another table",
+			Prepare:    prepare,
+			Path:       makePath(testutils.GetUUID("table-01"), testutils.GetUUID("column-03")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Column not found",
+			},
+		},
+		{
+			Title:      "Table not found",
+			Prepare:    prepare,
+			Path:       makePath(testutils.GetUUID("table-03"), testutils.GetUUID("column-01")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Table not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
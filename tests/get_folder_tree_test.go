@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestGetFolderTree(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/folders/%s/tree", id)
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: folder-01
+		        properties:
+		          name: a
+		        children:
+		          - id: table-01
+		            properties:
+		              name: b
+		          - id: folder-02
+		            properties:
+		              name: c
+		            children:
+		              - id: folder-03
+		                properties:
+		                  name: d
+		                children:
+		                  - id: table-02
+		                    properties:
+		                      name: e
+		      - id: table-03
+		        properties:
+		          name: f
+		  - id: org2
+		    tables:
+		      - id: table-04
+		`)
+	}
+	entry := func(id, typ string, children ...interface{}) map[string]interface{} {
+		e := map[string]interface{}{
+			"id":             testutils.GetUUID(id),
+			"organizationId": testutils.GetUUID("org1"),
+			"type":           typ,
+			"properties":     testutils.AnyVal{},
+			"createdAt":      testutils.Timestamp{},
+			"updatedAt":      testutils.Timestamp{},
+		}
+		if children != nil {
+			e["children"] = children
+		}
+		return e
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "Whole tree",
+			Prepare: prepare,
+			Path:    makePath(uuid.Nil),
+			Query: url.Values{
+				"organizationId": []string{testutils.GetUUID("org1").String()},
+				"sort":           []string{"property.name:asc"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"children": []interface{}{
+					entry("folder-01", "folder",
+						entry("table-01", "table"),
+						entry("folder-02", "folder",
+							entry("folder-03", "folder",
+								entry("table-02", "table"),
+							),
+						),
+					),
+					entry("table-03", "table"),
+				},
+			},
+		},
+		{
+			Title:   "Depth",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("folder-01")),
+			Query: url.Values{
+				"depth":      []string{"2"},
+				"sort":       []string{"property.name:desc"},
+				"properties": []string{"name,memo"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"children": []interface{}{
+					map[string]interface{}{
+						"id":             testutils.GetUUID("folder-02"),
+						"organizationId": testutils.GetUUID("org1"),
+						"type":           "folder",
+						"properties":     map[string]interface{}{"name": "c", "memo": nil},
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+						"children": []interface{}{
+							map[string]interface{}{
+								"id":             testutils.GetUUID("folder-03"),
+								"organizationId": testutils.GetUUID("org1"),
+								"type":           "folder",
+								"properties":     map[string]interface{}{"name": "d", "memo": nil},
+								"createdAt":      testutils.Timestamp{},
+								"updatedAt":      testutils.Timestamp{},
+							},
+						},
+					},
+					map[string]interface{}{
+						"id":             testutils.GetUUID("table-01"),
+						"organizationId": testutils.GetUUID("org1"),
+						"type":           "table",
+						"properties":     map[string]interface{}{"name": "b", "memo": nil},
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+					},
+				},
+			},
+		},
+		{
+			Title:   "Folders only",
+			Prepare: prepare,
+			Path:    makePath(uuid.Nil),
+			Query: url.Values{
+				"organizationId": []string{testutils.GetUUID("org1").String()},
+				"type":           []string{"folder"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"children": []interface{}{
+					entry("folder-01", "folder",
+						entry("folder-02", "folder",
+							entry("folder-03", "folder", []interface{}{}...),
+						),
+					),
+				},
+			},
+		},
+		{
+			Title:   "Tables only",
+			Prepare: prepare,
+			Path:    makePath(uuid.Nil),
+			Query: url.Values{
+				"organizationId": []string{testutils.GetUUID("org1").String()},
+				"type":           []string{"table"},
+				"sort":           []string{"property.name:asc"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"children": []interface{}{
+					entry("table-01", "table"),
+					entry("table-02", "table"),
+					entry("table-03", "table"),
+				},
+			},
+		},
+		{
+			Title: "Too many entries",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				if err := prepare(tc, db); err != nil {
+					return err
+				}
+				var entries []models.TableFilesystemEntry
+				for i := 0; i < 10000; i++ {
+					entries = append(entries, models.TableFilesystemEntry{
+						ID:             models.UUID(uuid.New()),
+						OrganizationID: models.UUID(testutils.GetUUID("org1")),
+						Type:           "table",
+					})
+				}
+				return db.CreateInBatches(entries, 500).Error
+			},
+			Path: makePath(uuid.Nil),
+			Query: url.Values{
+				"organizationId": []string{testutils.GetUUID("org1").String()},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Too many entries in tree; specify depth or type",
+			},
+		},
+		{
+			Title:   "Empty folder",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("folder-03")),
+			Query: url.Values{
+				"type": []string{"folder"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"children": []interface{}{},
+			},
+		},
+		{
+			Title:   "Invalid depth",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("folder-01")),
+			Query: url.Values{
+				"depth": []string{"-1"},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Invalid request parameter: Invalid input: Key: 'GetFolderTreeInput.Depth' Error:Field validation for 'Depth' failed on the 'gte' tag",
+			},
+		},
+		{
+			Title:      "Organization id is required for root folder",
+			Prepare:    prepare,
+			Path:       makePath(uuid.Nil),
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Organization id is required for root folder",
+			},
+		},
+		{
+			Title:      "Not found",
+			Prepare:    prepare,
+			Path:       makePath(testutils.GetUUID("folder-99")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestGetJob(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/jobs/%s", id)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "Succeeded",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    jobs:
+				      - id: job-01
+				        type: copy_folder
+				        status: succeeded
+				        result:
+				          folderId: abc
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("job-01")),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.GetUUID("job-01"),
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "copy_folder",
+				"status":         "succeeded",
+				"progress":       nil,
+				"result": map[string]interface{}{
+					"folderId": "abc",
+				},
+				"error":      nil,
+				"createdAt":  testutils.Timestamp{},
+				"updatedAt":  testutils.Timestamp{},
+				"startedAt":  nil,
+				"finishedAt": nil,
+			},
+		},
+		{
+			Title: "Failed",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    jobs:
+				      - id: job-01
+				        type: copy_folder
+				        status: failed
+				        error: Failed to copy folder
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("job-01")),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.GetUUID("job-01"),
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "copy_folder",
+				"status":         "failed",
+				"progress":       nil,
+				"result":         nil,
+				"error":          "Failed to copy folder",
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+				"startedAt":      nil,
+				"finishedAt":     nil,
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    jobs:
+				      - id: job-01
+				        type: copy_folder
+				        status: pending
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("job-02")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
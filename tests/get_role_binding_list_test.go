@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestGetRoleBindingList(t *testing.T) {
+	makePath := func(organizationID uuid.UUID) string {
+		return fmt.Sprintf("/organizations/%s/role-bindings", organizationID)
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: folder-01
+		      - id: table-01
+		    apiKeys:
+		      - id: key-01
+		        key: xb_key1aaaaaaaaaaaa
+		        role: none
+		        roleBindings:
+		          - id: binding-01
+		            resourceId: folder-01
+		            role: editor
+		          - id: binding-02
+		            resourceId: table-01
+		            role: viewer
+		      - id: key-02
+		        key: xb_key2bbbbbbbbbbbb
+		        role: none
+		        roleBindings:
+		          - id: binding-03
+		            resourceId: folder-01
+		            role: commenter
+		  - id: org2
+		    apiKeys:
+		      - id: key-03
+		        key: xb_key3cccccccccccc
+		`)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "Filter by api key",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"apiKeyId": []string{testutils.GetUUID("key-01").String()},
+				"sort":     []string{"role:asc"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"roleBindings": []interface{}{
+					map[string]interface{}{
+						"id":             testutils.GetUUID("binding-01"),
+						"organizationId": testutils.GetUUID("org1"),
+						"apiKeyId":       testutils.GetUUID("key-01"),
+						"resourceId":     testutils.GetUUID("folder-01"),
+						"role":           "editor",
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+					},
+					map[string]interface{}{
+						"id":             testutils.GetUUID("binding-02"),
+						"organizationId": testutils.GetUUID("org1"),
+						"apiKeyId":       testutils.GetUUID("key-01"),
+						"resourceId":     testutils.GetUUID("table-01"),
+						"role":           "viewer",
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+					},
+				},
+				"totalCount": float64(2),
+			},
+		},
+		{
+			Title:   "Filter by resource",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"resourceId": []string{testutils.GetUUID("folder-01").String()},
+				"pageSize":   []string{"1"},
+				"sort":       []string{"role:desc"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"roleBindings": []interface{}{
+					map[string]interface{}{
+						"id":             testutils.GetUUID("binding-01"),
+						"organizationId": testutils.GetUUID("org1"),
+						"apiKeyId":       testutils.GetUUID("key-01"),
+						"resourceId":     testutils.GetUUID("folder-01"),
+						"role":           "editor",
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+					},
+				},
+				"totalCount": float64(2),
+			},
+		},
+		{
+			Title:   "Organization level",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org2")),
+			Query: url.Values{
+				"resourceId": []string{uuid.Nil.String()},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"roleBindings": []interface{}{
+					map[string]interface{}{
+						"id":             testutils.UUID{},
+						"organizationId": testutils.GetUUID("org2"),
+						"apiKeyId":       testutils.GetUUID("key-03"),
+						"resourceId":     nil,
+						"role":           "owner",
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+					},
+				},
+				"totalCount": float64(1),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
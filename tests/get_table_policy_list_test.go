@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestGetTablePolicyList(t *testing.T) {
+	makePath := func(tableID uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/policies", tableID)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				        policies:
+				          - id: policy-01
+				            where: {value: true}
+				            properties: {name: policy1}
+				      - id: table-02
+				        policies:
+				          - id: policy-02
+				            where: {value: false}
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("table-01")),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"policies": []interface{}{
+					map[string]interface{}{
+						"id":         testutils.GetUUID("policy-01"),
+						"tableId":    testutils.GetUUID("table-01"),
+						"where":      map[string]interface{}{"value": true},
+						"properties": map[string]interface{}{"name": "policy1"},
+						"createdAt":  testutils.Timestamp{},
+						"updatedAt":  testutils.Timestamp{},
+					},
+				},
+			},
+		},
+		{
+			Title: "Empty",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("table-01")),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"policies": []interface{}{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -48,6 +48,7 @@ func TestGetTable(t *testing.T) {
 					map[string]interface{}{
 						"id":      testutils.GetUUID("column-01"),
 						"tableId": testutils.GetUUID("table-01"),
+						"type":    nil,
 						"index":   float64(0),
 						"properties": map[string]interface{}{
 							"key": "value",
@@ -125,6 +126,7 @@ func TestGetTable(t *testing.T) {
 					map[string]interface{}{
 						"id":      testutils.GetUUID("column-01"),
 						"tableId": testutils.GetUUID("table-01"),
+						"type":    nil,
 						"index":   float64(0),
 						"properties": map[string]interface{}{
 							"key2": "c2",
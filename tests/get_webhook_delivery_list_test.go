@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestGetWebhookDeliveryList(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/webhooks/%s/deliveries", id)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				        deliveries:
+				          - id: delivery-01
+				            eventType: record.inserted
+				            status: succeeded
+				            attempts: 1
+				            createdAt: 2021-01-01T00:00:00Z
+				          - id: delivery-02
+				            eventType: table.updated
+				            status: failed
+				            attempts: 5
+				            payload:
+				              type: table.updated
+				            createdAt: 2021-01-02T00:00:00Z
+				      - id: webhook-02
+				        deliveries:
+				          - id: delivery-03
+				            eventType: record.inserted
+				            status: succeeded
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("webhook-01")),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"deliveries": []interface{}{
+					map[string]interface{}{
+						"id":        testutils.GetUUID("delivery-02"),
+						"webhookId": testutils.GetUUID("webhook-01"),
+						"eventId":   testutils.UUID{},
+						"eventType": "table.updated",
+						"payload": map[string]interface{}{
+							"type": "table.updated",
+						},
+						"status":         "failed",
+						"attempts":       float64(5),
+						"responseStatus": nil,
+						"error":          nil,
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+						"deliveredAt":    nil,
+					},
+					map[string]interface{}{
+						"id":             testutils.GetUUID("delivery-01"),
+						"webhookId":      testutils.GetUUID("webhook-01"),
+						"eventId":        testutils.UUID{},
+						"eventType":      "record.inserted",
+						"payload":        map[string]interface{}{},
+						"status":         "succeeded",
+						"attempts":       float64(1),
+						"responseStatus": nil,
+						"error":          nil,
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+						"deliveredAt":    nil,
+					},
+				},
+				"totalCount": float64(2),
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("webhook-02")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestGetWebhookList(t *testing.T) {
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				        url: https://example.com/hook1
+				        createdAt: 2021-01-01T00:00:00Z
+				      - id: webhook-02
+				        url: https://example.com/hook2
+				        createdAt: 2021-01-02T00:00:00Z
+				      - id: webhook-03
+				        url: https://example.com/hook3
+				        createdAt: 2021-01-03T00:00:00Z
+				  - id: org2
+				    webhooks:
+				      - id: webhook-04
+				`)
+			},
+			Query: url.Values{
+				"organizationId": []string{testutils.GetUUID("org1").String()},
+				"page":           []string{"2"},
+				"pageSize":       []string{"2"},
+				"sort":           []string{"createdAt:asc"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"webhooks": []interface{}{
+					map[string]interface{}{
+						"id":             testutils.GetUUID("webhook-03"),
+						"organizationId": testutils.GetUUID("org1"),
+						"tableId":        nil,
+						"url":            "https://example.com/hook3",
+						"events":         []interface{}{},
+						"properties":     map[string]interface{}{},
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+					},
+				},
+				"totalCount": float64(3),
+			},
+		},
+		{
+			Title: "Filter by table",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				      - id: table-02
+				    webhooks:
+				      - id: webhook-01
+				        tableId: table-01
+				      - id: webhook-02
+				        tableId: table-02
+				      - id: webhook-03
+				`)
+			},
+			Query: url.Values{
+				"organizationId": []string{testutils.GetUUID("org1").String()},
+				"tableId":        []string{testutils.GetUUID("table-01").String()},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"webhooks": []interface{}{
+					map[string]interface{}{
+						"id":             testutils.GetUUID("webhook-01"),
+						"organizationId": testutils.GetUUID("org1"),
+						"tableId":        testutils.GetUUID("table-01"),
+						"url":            "http://localhost/webhook",
+						"events":         []interface{}{},
+						"properties":     map[string]interface{}{},
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+					},
+				},
+				"totalCount": float64(1),
+			},
+		},
+		{
+			Title: "No organization id",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request parameter`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		tc.Path = "/webhooks"
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestGetWebhook(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/webhooks/%s", id)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    tables:
+				      - id: table-01
+				    webhooks:
+				      - id: webhook-01
+				        tableId: table-01
+				        url: https://example.com/hook
+				        secret: my-secret
+				        events:
+				          - record.inserted
+				        properties:
+				          key1: value1
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("webhook-01")),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.GetUUID("webhook-01"),
+				"organizationId": testutils.GetUUID("org1"),
+				"tableId":        testutils.GetUUID("table-01"),
+				"url":            "https://example.com/hook",
+				"events":         []interface{}{"record.inserted"},
+				"properties": map[string]interface{}{
+					"key1": "value1",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("webhook-02")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
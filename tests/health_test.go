@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/health"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestHealth(t *testing.T) {
+	authConfig := auth.Config{
+		Mode:        auth.ModeAPIKey,
+		AdminAPIKey: "admin-secret",
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Liveness without authentication",
+			Method:     http.MethodGet,
+			Path:       "/healthz",
+			Config:     &api.Config{Auth: authConfig},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"status": "ok",
+			},
+		},
+		{
+			Title:      "Readiness",
+			Method:     http.MethodGet,
+			Path:       "/readyz",
+			Config:     &api.Config{Auth: authConfig},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"status": "ok",
+				"checks": map[string]interface{}{
+					"database":  "ok",
+					"migration": "ok",
+				},
+			},
+		},
+		{
+			Title:  "Unexpected migration version",
+			Method: http.MethodGet,
+			Path:   "/readyz",
+			Config: &api.Config{
+				Auth: authConfig,
+				Health: health.Config{
+					MigrationVersion: 1,
+				},
+			},
+			StatusCode: http.StatusServiceUnavailable,
+			Output: map[string]interface{}{
+				"status": "unavailable",
+				"checks": map[string]interface{}{
+					"database":  "ok",
+					"migration": testutils.Regexp{Pattern: `^version \d+, expected 1$`},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,277 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/idempotency"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		        columns:
+		          - id: column-01
+		`)
+	}
+	queryPath := fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01"))
+	insertQuery := func(value string) map[string]interface{} {
+		return makeJSON(`
+		insert:
+		  columns:
+		    - column: {{ .column }}
+		  values:
+		    - - value: {{ .value }}
+		`, map[string]interface{}{
+			"column": testutils.GetUUID("column-01"),
+			"value":  value,
+		})
+	}
+	header := func(key string) http.Header {
+		return http.Header{"Idempotency-Key": []string{key}}
+	}
+	post := func(router http.Handler, path, key string, body map[string]interface{}) *httptest.ResponseRecorder {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(b))
+		req.Header = header(key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	countTables := func(tc *testutils.APITestCase, expected int64) {
+		count, err := models.CountTables(testutils.GetDB(), models.UUID(testutils.GetUUID("org1")))
+		if err != nil {
+			t.Fatalf("[%s] %+v", tc.Title, err)
+		}
+		if count != expected {
+			t.Errorf("[%s] # of tables mismatch: expected=%d, actual=%d", tc.Title, expected, count)
+		}
+	}
+	countRecords := func(tc *testutils.APITestCase, expected int64) {
+		count, err := models.CountRecords(testutils.GetDB(), models.UUID(testutils.GetUUID("table-01")))
+		if err != nil {
+			t.Fatalf("[%s] %+v", tc.Title, err)
+		}
+		if count != expected {
+			t.Errorf("[%s] # of records mismatch: expected=%d, actual=%d", tc.Title, expected, count)
+		}
+	}
+	// setInProgress makes the stored response look like the one of the request in progress since createdAt
+	setInProgress := func(t *testing.T, createdAt time.Time) {
+		err := testutils.GetDB().Exec("UPDATE idempotency_keys SET status_code = NULL, created_at = ?", createdAt).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	createTableBody := map[string]interface{}{
+		"organizationId": testutils.GetUUID("org1"),
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Replay create table",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       "/tables",
+			Header:     header("key-01"),
+			Body:       createTableBody,
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w := post(router, "/tables", "key-01", createTableBody)
+				if w.Code != http.StatusOK {
+					t.Errorf("[%s] Status code mismatch: %d", tc.Title, w.Code)
+				}
+				if w.Header().Get("Idempotent-Replayed") != "true" {
+					t.Errorf("[%s] Not replayed", tc.Title)
+				}
+				var replayed map[string]interface{}
+				if err := json.Unmarshal(w.Body.Bytes(), &replayed); err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if diff := testutils.CompareJson(output, replayed); diff != "" {
+					t.Errorf("[%s] Replayed response mismatch:\n%s", tc.Title, diff)
+				}
+				countTables(tc, 2)
+			},
+		},
+		{
+			Title:      "Another key",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       "/tables",
+			Header:     header("key-01"),
+			Body:       createTableBody,
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w := post(router, "/tables", "key-02", createTableBody)
+				if w.Code != http.StatusOK || w.Header().Get("Idempotent-Replayed") != "" {
+					t.Errorf("[%s] Unexpected response: %d %s", tc.Title, w.Code, w.Body.String())
+				}
+				countTables(tc, 3)
+			},
+		},
+		{
+			Title:      "Replay insert query",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       queryPath,
+			Header:     header("key-01"),
+			Body:       insertQuery("a"),
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w := post(router, queryPath, "key-01", insertQuery("a"))
+				if w.Code != http.StatusOK || w.Header().Get("Idempotent-Replayed") != "true" {
+					t.Errorf("[%s] Unexpected response: %d %s", tc.Title, w.Code, w.Body.String())
+				}
+				countRecords(tc, 1)
+			},
+		},
+		{
+			Title:      "Mismatched body",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       queryPath,
+			Header:     header("key-01"),
+			Body:       insertQuery("a"),
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w := post(router, queryPath, "key-01", insertQuery("b"))
+				if w.Code != http.StatusUnprocessableEntity {
+					t.Errorf("[%s] Status code mismatch: %d", tc.Title, w.Code)
+				}
+				if !strings.Contains(w.Body.String(), "Idempotency key is already used for another request") {
+					t.Errorf("[%s] Unexpected response: %s", tc.Title, w.Body.String())
+				}
+				countRecords(tc, 1)
+			},
+		},
+		{
+			Title:      "Mismatched path",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       "/tables",
+			Header:     header("key-01"),
+			Body:       createTableBody,
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w := post(router, "/folders", "key-01", createTableBody)
+				if w.Code != http.StatusUnprocessableEntity {
+					t.Errorf("[%s] Status code mismatch: %d", tc.Title, w.Code)
+				}
+			},
+		},
+		{
+			Title:      "Error responses are replayed",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       "/tables",
+			Header:     header("key-01"),
+			Body:       map[string]interface{}{},
+			StatusCode: http.StatusBadRequest,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w := post(router, "/tables", "key-01", map[string]interface{}{})
+				if w.Code != http.StatusBadRequest || w.Header().Get("Idempotent-Replayed") != "true" {
+					t.Errorf("[%s] Unexpected response: %d %s", tc.Title, w.Code, w.Body.String())
+				}
+			},
+		},
+		{
+			Title:      "Too long key",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       "/tables",
+			Header:     header(strings.Repeat("a", 256)),
+			Body:       createTableBody,
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Invalid idempotency key",
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				countTables(tc, 1)
+			},
+		},
+		{
+			Title:      "In progress",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       "/tables",
+			Header:     header("key-01"),
+			Body:       createTableBody,
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				setInProgress(t, time.Now().UTC())
+				w := post(router, "/tables", "key-01", createTableBody)
+				if w.Code != http.StatusConflict {
+					t.Errorf("[%s] Unexpected response: %d %s", tc.Title, w.Code, w.Body.String())
+				}
+				countTables(tc, 2)
+			},
+		},
+		{
+			Title:      "In progress with expired lease",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       "/tables",
+			Header:     header("key-01"),
+			Body:       createTableBody,
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				setInProgress(t, time.Now().UTC().Add(-idempotency.DefaultLease-time.Minute))
+				w := post(router, "/tables", "key-01", createTableBody)
+				if w.Code != http.StatusOK || w.Header().Get("Idempotent-Replayed") != "" {
+					t.Errorf("[%s] Unexpected response: %d %s", tc.Title, w.Code, w.Body.String())
+				}
+				countTables(tc, 3)
+			},
+		},
+		{
+			Title:      "Too large body",
+			Prepare:    prepare,
+			Method:     http.MethodPost,
+			Path:       "/tables",
+			Body:       createTableBody,
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				body := `{"organizationId":"` + testutils.GetUUID("org1").String() + `","properties":{"k":"` + strings.Repeat("a", 32<<20) + `"}}`
+				req := httptest.NewRequest(http.MethodPost, "/tables", strings.NewReader(body))
+				req.Header = header("key-01")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				if w.Code != http.StatusRequestEntityTooLarge {
+					t.Errorf("[%s] Unexpected response: %d %s", tc.Title, w.Code, w.Body.String())
+				}
+				countTables(tc, 2)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestImportTableXLSX(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/xlsx", id)
+	}
+
+	fixture := `
+	organizations:
+	  - id: org1
+	    tables:
+	      - id: table-01
+	        columns:
+	          - id: column-01
+	            properties:
+	              name: Name
+	          - id: column-02
+	            properties:
+	              name: Score
+	          - id: column-03
+	`
+
+	// largeMultipartBody is a multipart body with a file beyond the limit of the size
+	var largeMultipartBody bytes.Buffer
+	mw := multipart.NewWriter(&largeMultipartBody)
+	fw, err := mw.CreateFormFile("file", "large.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write(make([]byte, 32<<20)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			RawBody: testutils.MakeXLSX("Sheet1", [][]interface{}{
+				{"Score", "Name", testutils.GetUUID("column-03").String(), "property.key1"},
+				{80, "alice", true, "p1"},
+				{92.5, "bob", false, nil},
+				{nil, "123", nil, nil},
+				{testutils.XLSXUntypedCell("00123"), "carol", testutils.XLSXUntypedCell("1e3"), testutils.XLSXUntypedCell("12345678901234567890")},
+			}),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"recordIds": []interface{}{
+					testutils.UUID{},
+					testutils.UUID{},
+					testutils.UUID{},
+					testutils.UUID{},
+				},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := selectTable(router, testutils.GetUUID("table-01"), makeJSON(`
+				select:
+				  columns:
+				    - column: {{ .column01 }}
+				    - column: {{ .column02 }}
+				    - column: {{ .column03 }}
+				    - property: key1
+				  orderBy:
+				    - key:
+				        column: {{ .column01 }}
+				`, map[string]interface{}{
+					"column01": testutils.GetUUID("column-01"),
+					"column02": testutils.GetUUID("column-02"),
+					"column03": testutils.GetUUID("column-03"),
+				}))
+				expected := map[string]interface{}{
+					"records": []interface{}{
+						[]interface{}{"123", nil, nil, nil},
+						[]interface{}{"alice", float64(80), true, "p1"},
+						[]interface{}{"bob", float64(92.5), false, nil},
+						[]interface{}{"carol", "00123", "1e3", "12345678901234567890"},
+					},
+					"limit": float64(10),
+				}
+				if diff := testutils.CompareJson(expected, res); diff != "" {
+					t.Errorf("[%s] Selected records mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title: "Specify sheet",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Query: url.Values{
+				"sheet": []string{"Sheet2"},
+			},
+			RawBody: testutils.MakeXLSX("Sheet1", [][]interface{}{
+				{"Name"},
+				{"alice"},
+			}),
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid sheet: Sheet not found: Sheet2`},
+			},
+		},
+		{
+			Title: "Column not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			RawBody: testutils.MakeXLSX("Sheet1", [][]interface{}{
+				{"Name", "Unknown"},
+				{"alice", 1},
+			}),
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Invalid header: Column not found: Unknown",
+			},
+		},
+		{
+			Title: "Invalid file",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path:       makePath(testutils.GetUUID("table-01")),
+			RawBody:    []byte("name,score\nalice,80\n"),
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid xlsx file`},
+			},
+		},
+		{
+			Title: "Table not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-02")),
+			RawBody: testutils.MakeXLSX("Sheet1", [][]interface{}{
+				{"Name"},
+				{"alice"},
+			}),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Table not found",
+			},
+		},
+		{
+			Title: "Table not found before reading file",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path:       makePath(testutils.GetUUID("table-02")),
+			RawBody:    []byte("name,score\nalice,80\n"),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Table not found",
+			},
+		},
+		{
+			Title: "Too large multipart file",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: makePath(testutils.GetUUID("table-01")),
+			Header: http.Header{
+				"Content-Type": []string{mw.FormDataContentType()},
+			},
+			RawBody:    largeMultipartBody.Bytes(),
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request body: .*too large`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPost
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func generateJWKS(t *testing.T, kid string) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []interface{}{
+			map[string]interface{}{
+				"kid": kid,
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := ioutil.WriteFile(path, jwks, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return key, path
+}
+
+func TestJWTAuthentication(t *testing.T) {
+	key, jwksPath := generateJWKS(t, "key-1")
+	otherKey, _ := generateJWKS(t, "key-1")
+	config := &api.Config{
+		Auth: auth.Config{
+			Mode:        auth.ModeJWT,
+			AdminAPIKey: "admin-secret",
+			JWT: auth.JWTConfig{
+				JWKS:     jwksPath,
+				Issuer:   "https://sso.example.com",
+				Audience: "x-base",
+			},
+		},
+	}
+	sign := func(key *rsa.PrivateKey, modify func(jwt.MapClaims)) http.Header {
+		claims := jwt.MapClaims{
+			"iss":    "https://sso.example.com",
+			"aud":    "x-base",
+			"sub":    "user-1",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"org_id": testutils.GetUUID("org1").String(),
+			"role":   "editor",
+		}
+		if modify != nil {
+			modify(claims)
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key-1"
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return http.Header{"Authorization": []string{"Bearer " + s}}
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    apiKeys:
+		      - key: xb_key1aaaaaaaaaaaa
+		    tables:
+		      - id: table-01
+		  - id: org2
+		    tables:
+		      - id: table-02
+		`)
+	}
+	invalidToken := map[string]interface{}{
+		"message": "Invalid token",
+	}
+
+	table01 := map[string]interface{}{
+		"id":             testutils.GetUUID("table-01"),
+		"organizationId": testutils.GetUUID("org1"),
+		"type":           "table",
+		"path":           []interface{}{},
+		"columns":        []interface{}{},
+		"properties":     map[string]interface{}{},
+		"createdAt":      testutils.Timestamp{},
+		"updatedAt":      testutils.Timestamp{},
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Valid token",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:     sign(key, nil),
+			StatusCode: http.StatusOK,
+			Output:     table01,
+		},
+		{
+			Title:   "Role is mapped from claims",
+			Prepare: prepare,
+			Method:  http.MethodPatch,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header: sign(key, func(c jwt.MapClaims) {
+				c["role"] = "viewer"
+			}),
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{"key": "value"},
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "Another organization",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-02")),
+			Header:     sign(key, nil),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+		{
+			Title:      "Signed by another key",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:     sign(otherKey, nil),
+			StatusCode: http.StatusUnauthorized,
+			Output:     invalidToken,
+		},
+		{
+			Title:   "Expired",
+			Prepare: prepare,
+			Method:  http.MethodGet,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header: sign(key, func(c jwt.MapClaims) {
+				c["exp"] = time.Now().Add(-time.Minute).Unix()
+			}),
+			StatusCode: http.StatusUnauthorized,
+			Output:     invalidToken,
+		},
+		{
+			Title:   "No expiry",
+			Prepare: prepare,
+			Method:  http.MethodGet,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header: sign(key, func(c jwt.MapClaims) {
+				delete(c, "exp")
+			}),
+			StatusCode: http.StatusUnauthorized,
+			Output:     invalidToken,
+		},
+		{
+			Title:   "Wrong issuer",
+			Prepare: prepare,
+			Method:  http.MethodGet,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header: sign(key, func(c jwt.MapClaims) {
+				c["iss"] = "https://evil.example.com"
+			}),
+			StatusCode: http.StatusUnauthorized,
+			Output:     invalidToken,
+		},
+		{
+			Title:   "Wrong audience",
+			Prepare: prepare,
+			Method:  http.MethodGet,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header: sign(key, func(c jwt.MapClaims) {
+				c["aud"] = []string{"another"}
+			}),
+			StatusCode: http.StatusUnauthorized,
+			Output:     invalidToken,
+		},
+		{
+			Title:   "Invalid role",
+			Prepare: prepare,
+			Method:  http.MethodGet,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header: sign(key, func(c jwt.MapClaims) {
+				c["role"] = "superuser"
+			}),
+			StatusCode: http.StatusUnauthorized,
+			Output:     invalidToken,
+		},
+		{
+			Title:   "No organization",
+			Prepare: prepare,
+			Method:  http.MethodGet,
+			Path:    fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header: sign(key, func(c jwt.MapClaims) {
+				delete(c, "org_id")
+			}),
+			StatusCode: http.StatusUnauthorized,
+			Output:     invalidToken,
+		},
+		{
+			Title:      "API key is still accepted",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:     http.Header{"Authorization": []string{"Bearer xb_key1aaaaaaaaaaaa"}},
+			StatusCode: http.StatusOK,
+			Output:     table01,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Config = config
+		testutils.RunTestCase(t, tc)
+	}
+}
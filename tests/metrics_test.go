@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestMetrics(t *testing.T) {
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    apiKeys:
+		      - key: xb_key1aaaaaaaaaaaa
+		    tables:
+		      - id: table-01
+		`)
+	}
+	getMetrics := func(router http.Handler, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	config := &api.Config{
+		Auth: auth.Config{
+			Mode:        auth.ModeAPIKey,
+			AdminAPIKey: "admin-secret",
+		},
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "General case",
+			Prepare: prepare,
+			Method:  http.MethodPost,
+			Path:    fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Header:  http.Header{"Authorization": []string{"Bearer xb_key1aaaaaaaaaaaa"}},
+			Body: makeJSON(`
+			select:
+			  columns:
+			    - metadata: id
+			`, nil),
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w := getMetrics(router, "admin-secret")
+				if w.Code != http.StatusOK {
+					t.Fatalf("[%s] Status code mismatch: %d", tc.Title, w.Code)
+				}
+				body := w.Body.String()
+				for _, line := range []string{
+					`xbase_http_requests_total{method="POST",route="/tables/{tableID}/query",status="200"} 1`,
+					`xbase_http_request_duration_seconds_count{method="POST",route="/tables/{tableID}/query"} 1`,
+					`xbase_table_queries_total{kind="select"} 1`,
+					`go_sql_open_connections{db_name="main"}`,
+					`xbase_migration_version `,
+					`xbase_migration_dirty 0`,
+				} {
+					if !strings.Contains(body, line) {
+						t.Errorf("[%s] Metrics do not have %s:\n%s", tc.Title, line, body)
+					}
+				}
+			},
+		},
+		{
+			Title:      "Metrics need administrator",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:     http.Header{"Authorization": []string{"Bearer xb_key1aaaaaaaaaaaa"}},
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w := getMetrics(router, "xb_key1aaaaaaaaaaaa")
+				if w.Code != http.StatusForbidden {
+					t.Errorf("[%s] Status code mismatch: %d", tc.Title, w.Code)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Config = config
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,339 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/quotas"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestQuota(t *testing.T) {
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: folder-01
+		        children:
+		          - id: table-01
+		            columns:
+		              - id: column-01
+		            records:
+		              - data: [a]
+		  - id: org2
+		`)
+	}
+	insertQuery := makeJSON(`
+	insert:
+	  columns:
+	    - column: {{ .column }}
+	  values:
+	    - - value: b
+	    - - value: c
+	`, map[string]interface{}{
+		"column": testutils.GetUUID("column-01"),
+	})
+	updateQuery := makeJSON(`
+	update:
+	  set:
+	    - to: {column: {{ .column }} }
+	      value: {value: {{ .value }} }
+	  where: {value: true}
+	`, map[string]interface{}{
+		"column": testutils.GetUUID("column-01"),
+		"value":  strings.Repeat("x", 100),
+	})
+	deleteQuery := map[string]interface{}{
+		"delete": map[string]interface{}{
+			"where": map[string]interface{}{"value": true},
+		},
+	}
+	// prepareLarge loads a record of about 150 bytes
+	prepareLarge := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(fmt.Sprintf(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: folder-01
+		        children:
+		          - id: table-01
+		            columns:
+		              - id: column-01
+		            records:
+		              - data: [%s]
+		`, strings.Repeat("x", 100)))
+	}
+	post := func(router http.Handler, path string, body map[string]interface{}) int {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	postQuery := func(router http.Handler, body map[string]interface{}) int {
+		return post(router, fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")), body)
+	}
+	countRecords := func() int64 {
+		var count int64
+		if err := testutils.GetDB().Model(&models.TableRecord{}).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "Tables",
+			Prepare: prepare,
+			Config:  &api.Config{Quota: quotas.Config{MaxTables: 1}},
+			Method:  http.MethodPost,
+			Path:    "/tables",
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Quota exceeded: tables (limit=1)",
+			},
+		},
+		{
+			Title:   "Tables of another organization",
+			Prepare: prepare,
+			Config:  &api.Config{Quota: quotas.Config{MaxTables: 1}},
+			Method:  http.MethodPost,
+			Path:    "/tables",
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org2"),
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org2"),
+				"type":           "table",
+				"path":           []interface{}{},
+				"columns":        []interface{}{},
+				"properties":     map[string]interface{}{},
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+			},
+		},
+		{
+			Title:      "Copying folder",
+			Prepare:    prepare,
+			Config:     &api.Config{Quota: quotas.Config{MaxTables: 1}},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/folders/%s/copy", testutils.GetUUID("folder-01")),
+			Body:       map[string]interface{}{},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Quota exceeded: tables (limit=1)",
+			},
+		},
+		{
+			Title:   "Copying folder asynchronously",
+			Prepare: prepare,
+			Config:  &api.Config{Quota: quotas.Config{MaxTables: 1}},
+			Method:  http.MethodPost,
+			Path:    fmt.Sprintf("/folders/%s/copy", testutils.GetUUID("folder-01")),
+			Body: map[string]interface{}{
+				"async": true,
+			},
+			StatusCode: http.StatusAccepted,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "copy_folder",
+				"status":         testutils.AnyVal{},
+				"progress":       nil,
+				"result":         nil,
+				"error":          nil,
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+				"startedAt":      testutils.AnyVal{},
+				"finishedAt":     nil,
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				var job map[string]interface{}
+				for i := 0; i < 100; i++ {
+					job = testutils.ServeGet(router, fmt.Sprintf("/jobs/%s", output["id"]), nil)
+					if job["status"] != "pending" && job["status"] != "running" {
+						break
+					}
+					time.Sleep(100 * time.Millisecond)
+				}
+				if job["status"] != "failed" || job["error"] != "Quota exceeded: tables (limit=1)" {
+					t.Errorf("[%s] Unexpected job: %v", tc.Title, job)
+				}
+				var count int64
+				if err := testutils.GetDB().Model(&models.TableFilesystemEntry{}).Where("type = ?", "table").Count(&count).Error; err != nil {
+					t.Fatal(err)
+				}
+				if count != 1 {
+					t.Errorf("[%s] Copied tables are not rolled back: %d", tc.Title, count)
+				}
+			},
+		},
+		{
+			Title:      "Storage bytes counted after duplicating table",
+			Prepare:    prepareLarge,
+			Config:     &api.Config{Quota: quotas.Config{MaxStorageBytes: 400}},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/duplicate", testutils.GetUUID("table-01")),
+			Body:       map[string]interface{}{"includeRecords": true},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.UUID{},
+				"organizationId": testutils.GetUUID("org1"),
+				"type":           "table",
+				"path":           testutils.AnyVal{},
+				"columns":        testutils.AnyVal{},
+				"properties":     map[string]interface{}{},
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				path := fmt.Sprintf("/tables/%s/duplicate", testutils.GetUUID("table-01"))
+				if code := post(router, path, map[string]interface{}{"includeRecords": true}); code != http.StatusForbidden {
+					t.Errorf("[%s] Second duplicate is not rejected: %d", tc.Title, code)
+				}
+			},
+		},
+		{
+			Title:   "Columns per table",
+			Prepare: prepare,
+			Config:  &api.Config{Quota: quotas.Config{MaxColumnsPerTable: 1}},
+			Method:  http.MethodPost,
+			Path:    fmt.Sprintf("/tables/%s/columns", testutils.GetUUID("table-01")),
+			Body: map[string]interface{}{
+				"type": "text",
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Quota exceeded: columns per table (limit=1)",
+			},
+		},
+		{
+			Title:   "Columns on table creation",
+			Prepare: prepare,
+			Config:  &api.Config{Quota: quotas.Config{MaxColumnsPerTable: 1}},
+			Method:  http.MethodPost,
+			Path:    "/tables",
+			Body: map[string]interface{}{
+				"organizationId": testutils.GetUUID("org1"),
+				"columns": []interface{}{
+					map[string]interface{}{"type": "text"},
+					map[string]interface{}{"type": "text"},
+				},
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Quota exceeded: columns per table (limit=1)",
+			},
+		},
+		{
+			Title:      "Records per table",
+			Prepare:    prepare,
+			Config:     &api.Config{Quota: quotas.Config{MaxRecordsPerTable: 2}},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Body:       insertQuery,
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Quota exceeded: records per table (limit=2)",
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				if count := countRecords(); count != 1 {
+					t.Errorf("[%s] Inserted records are not rolled back: %d", tc.Title, count)
+				}
+			},
+		},
+		{
+			Title:      "Records within quota",
+			Prepare:    prepare,
+			Config:     &api.Config{Quota: quotas.Config{MaxRecordsPerTable: 3}},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Body:       insertQuery,
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"recordIds": []interface{}{testutils.UUID{}, testutils.UUID{}},
+			},
+		},
+		{
+			Title:      "Storage bytes",
+			Prepare:    prepare,
+			Config:     &api.Config{Quota: quotas.Config{MaxStorageBytes: 100}},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Body:       insertQuery,
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Quota exceeded: storage bytes (limit=100)",
+			},
+		},
+		{
+			Title:      "Storage bytes counted after insert",
+			Prepare:    prepare,
+			Config:     &api.Config{Quota: quotas.Config{MaxStorageBytes: 200}},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Body:       insertQuery,
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"recordIds": []interface{}{testutils.UUID{}, testutils.UUID{}},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				if code := postQuery(router, insertQuery); code != http.StatusForbidden {
+					t.Errorf("[%s] Second insert is not rejected: %d", tc.Title, code)
+				}
+				if code := postQuery(router, deleteQuery); code != http.StatusOK {
+					t.Fatalf("[%s] Failed to delete records: %d", tc.Title, code)
+				}
+				if code := postQuery(router, insertQuery); code != http.StatusOK {
+					t.Errorf("[%s] Insert after delete is rejected: %d", tc.Title, code)
+				}
+			},
+		},
+		{
+			Title:      "Storage bytes on update",
+			Prepare:    prepare,
+			Config:     &api.Config{Quota: quotas.Config{MaxStorageBytes: 100}},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Body:       updateQuery,
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Quota exceeded: storage bytes (limit=100)",
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				var size int64
+				err := testutils.GetDB().Raw("SELECT LENGTH(data) FROM table_records").Scan(&size).Error
+				if err != nil {
+					t.Fatal(err)
+				}
+				if size > 100 {
+					t.Errorf("[%s] Updated record is not rolled back: %d", tc.Title, size)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/ratelimit"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestRateLimit(t *testing.T) {
+	bearer := func(key string) http.Header {
+		return http.Header{"Authorization": []string{"Bearer " + key}}
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    apiKeys:
+		      - key: xb_key1aaaaaaaaaaaa
+		      - key: xb_key2bbbbbbbbbbbb
+		    tables:
+		      - id: table-01
+		`)
+	}
+	path := fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01"))
+	serve := func(router http.Handler, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header = bearer(key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	output := map[string]interface{}{
+		"id":             testutils.GetUUID("table-01"),
+		"organizationId": testutils.GetUUID("org1"),
+		"type":           "table",
+		"path":           []interface{}{},
+		"columns":        []interface{}{},
+		"properties":     map[string]interface{}{},
+		"createdAt":      testutils.Timestamp{},
+		"updatedAt":      testutils.Timestamp{},
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "Per key",
+			Prepare: prepare,
+			Config: &api.Config{
+				Auth: auth.Config{
+					Mode:        auth.ModeAPIKey,
+					AdminAPIKey: "admin-secret",
+				},
+				RateLimit: ratelimit.Config{
+					KeyRate:  0.001,
+					KeyBurst: 1,
+				},
+			},
+			Path:       path,
+			Header:     bearer("xb_key1aaaaaaaaaaaa"),
+			StatusCode: http.StatusOK,
+			Output:     output,
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w := serve(router, "xb_key1aaaaaaaaaaaa")
+				if w.Code != http.StatusTooManyRequests {
+					t.Errorf("[%s] Status code mismatch: %d", tc.Title, w.Code)
+				}
+				if w.Header().Get("Retry-After") == "" {
+					t.Errorf("[%s] No Retry-After header", tc.Title)
+				}
+
+				// Another key is not limited
+				if w := serve(router, "xb_key2bbbbbbbbbbbb"); w.Code != http.StatusOK {
+					t.Errorf("[%s] Another key is limited: %d", tc.Title, w.Code)
+				}
+
+				// Administrator is not limited
+				if w := serve(router, "admin-secret"); w.Code != http.StatusOK {
+					t.Errorf("[%s] Administrator is limited: %d", tc.Title, w.Code)
+				}
+			},
+		},
+		{
+			Title:   "Per organization",
+			Prepare: prepare,
+			Config: &api.Config{
+				Auth: auth.Config{
+					Mode: auth.ModeAPIKey,
+				},
+				RateLimit: ratelimit.Config{
+					OrganizationRate:  0.001,
+					OrganizationBurst: 2,
+				},
+			},
+			Path:       path,
+			Header:     bearer("xb_key1aaaaaaaaaaaa"),
+			StatusCode: http.StatusOK,
+			Output:     output,
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				if w := serve(router, "xb_key2bbbbbbbbbbbb"); w.Code != http.StatusOK {
+					t.Errorf("[%s] Status code mismatch: %d", tc.Title, w.Code)
+				}
+				if w := serve(router, "xb_key2bbbbbbbbbbbb"); w.Code != http.StatusTooManyRequests {
+					t.Errorf("[%s] Status code mismatch: %d", tc.Title, w.Code)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
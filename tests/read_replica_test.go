@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api/replicas"
+	"github.com/tsujio/x-base/databases"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestReadReplica(t *testing.T) {
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		        columns:
+		          - id: column-01
+		        records:
+		          - data: ["v1"]
+		`)
+	}
+	selectQuery := makeJSON(`
+	select:
+	  columns:
+	    - column: {{ .column01 }}
+	`, map[string]interface{}{
+		"column01": testutils.GetUUID("column-01"),
+	})
+	tableOutput := map[string]interface{}{
+		"id":             testutils.GetUUID("table-01"),
+		"organizationId": testutils.GetUUID("org1"),
+		"type":           "table",
+		"path":           []interface{}{},
+		"columns":        testutils.AnyVal{},
+		"properties":     map[string]interface{}{},
+		"createdAt":      testutils.Timestamp{},
+		"updatedAt":      testutils.Timestamp{},
+	}
+
+	// Unreachable server, or database file in a missing directory for SQLite
+	brokenReplica := databases.DBConfig{
+		Host:   "127.0.0.1",
+		Port:   1,
+		DBName: filepath.Join(t.TempDir(), "missing", "replica_test.db"),
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Fall back to the primary",
+			Prepare:    prepare,
+			Replicas:   []databases.DBConfig{brokenReplica},
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			StatusCode: http.StatusOK,
+			Output:     tableOutput,
+		},
+		{
+			Title:      "Select query falls back to the primary",
+			Prepare:    prepare,
+			Replicas:   []databases.DBConfig{brokenReplica},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Body:       selectQuery,
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"records": []interface{}{
+					[]interface{}{"v1"},
+				},
+				"limit": float64(10),
+			},
+		},
+	}
+
+	// A replica which has not received the data yet, only for SQLite where a database is made easily
+	if os.Getenv("DB_TYPE") == databases.DBTypeSQLite {
+		staleReplica := databases.DBConfig{
+			DBType: databases.DBTypeSQLite,
+			DBName: filepath.Join(t.TempDir(), "stale_replica_test.db"),
+		}
+		if err := databases.Setup(&staleReplica, os.Getenv("MIGRATIONS_DIR")); err != nil {
+			t.Fatal(err)
+		}
+
+		testCases = append(testCases, []testutils.APITestCase{
+			{
+				Title:      "Read from the replica",
+				Prepare:    prepare,
+				Replicas:   []databases.DBConfig{staleReplica},
+				Method:     http.MethodGet,
+				Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+				StatusCode: http.StatusNotFound,
+				Output: map[string]interface{}{
+					"message": "Not found",
+				},
+			},
+			{
+				Title:    "Read your writes",
+				Prepare:  prepare,
+				Replicas: []databases.DBConfig{staleReplica},
+				Method:   http.MethodGet,
+				Path:     fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+				Header: http.Header{
+					replicas.HeaderReadYourWrites: []string{"true"},
+				},
+				StatusCode: http.StatusOK,
+				Output:     tableOutput,
+			},
+			{
+				Title:      "Select query from the replica",
+				Prepare:    prepare,
+				Replicas:   []databases.DBConfig{staleReplica},
+				Method:     http.MethodPost,
+				Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+				Body:       selectQuery,
+				StatusCode: http.StatusOK,
+				Output: map[string]interface{}{
+					"records": []interface{}{},
+					"limit":   float64(10),
+				},
+			},
+			{
+				Title:      "Writes go to the primary",
+				Prepare:    prepare,
+				Replicas:   []databases.DBConfig{staleReplica},
+				Method:     http.MethodPatch,
+				Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+				Body:       map[string]interface{}{"properties": map[string]interface{}{"key": "value"}},
+				StatusCode: http.StatusOK,
+				Output:     testutils.AnyVal{},
+			},
+		}...)
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -62,6 +62,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-03"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(0),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -70,6 +71,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-02"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(1),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -78,6 +80,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-01"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(2),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -125,6 +128,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-02"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(0),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -133,6 +137,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-01"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(1),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -175,6 +180,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-03"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(0),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -183,6 +189,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-01"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(1),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -191,6 +198,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-02"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(2),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -238,6 +246,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-02"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(0),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -246,6 +255,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-01"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(1),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -317,6 +327,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-15"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(0),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -325,6 +336,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-14"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(1),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -333,6 +345,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-13"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(2),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -341,6 +354,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-12"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(3),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -349,6 +363,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-11"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(4),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -357,6 +372,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-10"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(5),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -365,6 +381,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-09"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(6),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -373,6 +390,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-08"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(7),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -381,6 +399,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-07"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(8),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -389,6 +408,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-06"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(9),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -397,6 +417,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-05"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(10),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -405,6 +426,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-04"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(11),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -413,6 +435,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-03"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(12),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -421,6 +444,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-02"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(13),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -429,6 +453,7 @@ func TestReorderColumn(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-01"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(14),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/logging"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestRequestLog(t *testing.T) {
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    apiKeys:
+		      - key: xb_key1aaaaaaaaaaaa
+		    tables:
+		      - id: table-01
+		`)
+	}
+	config := &api.Config{
+		Auth: auth.Config{
+			Mode:        auth.ModeAPIKey,
+			AdminAPIKey: "admin-secret",
+		},
+	}
+	bearer := http.Header{"Authorization": []string{"Bearer xb_key1aaaaaaaaaaaa"}}
+
+	// serve sends the request through the logging middleware and returns the response and the log lines
+	serve := func(router http.Handler, path string, header http.Header) (*httptest.ResponseRecorder, []map[string]interface{}) {
+		var buf bytes.Buffer
+		logging.SetLogger(logging.NewJSONLogger(&buf, logging.LevelDebug))
+		defer logging.SetLogger(&logging.DefaultLogger{})
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		logging.Middleware(router).ServeHTTP(w, req)
+
+		var lines []map[string]interface{}
+		for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var line map[string]interface{}
+			if err := json.Unmarshal([]byte(l), &line); err != nil {
+				t.Fatalf("Invalid log line: %s", l)
+			}
+			lines = append(lines, line)
+		}
+		return w, lines
+	}
+	accessLog := func(tc *testutils.APITestCase, lines []map[string]interface{}) map[string]interface{} {
+		for _, l := range lines {
+			if l["message"] == "access" {
+				return l
+			}
+		}
+		t.Fatalf("[%s] No access log: %v", tc.Title, lines)
+		return nil
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Generated request id",
+			Prepare:    prepare,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			Header:     bearer,
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				w, lines := serve(router, fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")), bearer)
+				id := w.Header().Get("X-Request-ID")
+				if id == "" {
+					t.Fatalf("[%s] No request id", tc.Title)
+				}
+				expected := map[string]interface{}{
+					"time":           testutils.Timestamp{},
+					"level":          "info",
+					"message":        "access",
+					"requestId":      id,
+					"method":         "GET",
+					"path":           fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+					"route":          "/tables/{tableID}",
+					"status":         float64(http.StatusOK),
+					"latencyMs":      testutils.AnyVal{},
+					"organizationId": testutils.GetUUID("org1").String(),
+				}
+				if diff := testutils.CompareJson(expected, accessLog(tc, lines)); diff != "" {
+					t.Errorf("[%s] Access log mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title:      "Propagated request id in error response",
+			Prepare:    prepare,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-02")),
+			Header:     bearer,
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				header := http.Header{
+					"Authorization": bearer["Authorization"],
+					"X-Request-Id":  []string{"request-01"},
+				}
+				w, lines := serve(router, fmt.Sprintf("/tables/%s", testutils.GetUUID("table-02")), header)
+				if w.Header().Get("X-Request-ID") != "request-01" {
+					t.Errorf("[%s] Request id mismatch: %s", tc.Title, w.Header().Get("X-Request-ID"))
+				}
+				var res map[string]interface{}
+				if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+					t.Fatalf("[%s] %+v", tc.Title, err)
+				}
+				if diff := testutils.CompareJson(map[string]interface{}{
+					"message":   "Not found",
+					"requestId": "request-01",
+				}, res); diff != "" {
+					t.Errorf("[%s] Response mismatch:\n%s", tc.Title, diff)
+				}
+				if l := accessLog(tc, lines); l["requestId"] != "request-01" || l["status"] != float64(http.StatusNotFound) {
+					t.Errorf("[%s] Access log mismatch: %v", tc.Title, l)
+				}
+			},
+		},
+		{
+			Title:      "Invalid request id is replaced",
+			Prepare:    prepare,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			StatusCode: http.StatusUnauthorized,
+			Output: map[string]interface{}{
+				"message": "Authentication required",
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				header := http.Header{"X-Request-Id": []string{strings.Repeat("a", 129)}}
+				w, lines := serve(router, fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")), header)
+				id := w.Header().Get("X-Request-ID")
+				if id == "" || id == header.Get("X-Request-ID") {
+					t.Errorf("[%s] Request id not replaced: %s", tc.Title, id)
+				}
+				if l := accessLog(tc, lines); l["requestId"] != id || l["status"] != float64(http.StatusUnauthorized) {
+					t.Errorf("[%s] Access log mismatch: %v", tc.Title, l)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Config = config
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/api/jobs"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestResumeJobs(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/jobs/%s", id)
+	}
+
+	// prepareWith loads a job of another runner holding the lease until leaseExpiresAt, then resumes jobs
+	// with a new runner, which is stopped beforehand if stopped
+	prepareWith := func(status string, leaseExpiresAt time.Time, stopped bool) func(*testutils.APITestCase, *gorm.DB) error {
+		return func(tc *testutils.APITestCase, db *gorm.DB) error {
+			err := testutils.LoadFixture(fmt.Sprintf(`
+			organizations:
+			  - id: org1
+			    jobs:
+			      - id: job-01
+			        type: copy_folder
+			        status: %s
+			`, status))
+			if err != nil {
+				return err
+			}
+			err = db.Exec("UPDATE jobs SET owner = ?, lease_expires_at = ? WHERE id = ?",
+				"other-runner", leaseExpiresAt, models.UUID(testutils.GetUUID("job-01"))).Error
+			if err != nil {
+				return err
+			}
+
+			runner := jobs.NewRunner(db)
+			if stopped {
+				if err := runner.Stop(context.Background()); err != nil {
+					return err
+				}
+			}
+			if err := runner.Resume(); err != nil {
+				return err
+			}
+			runner.Wait()
+			return nil
+		}
+	}
+
+	prepare := func(status string, leaseExpiresAt time.Time) func(*testutils.APITestCase, *gorm.DB) error {
+		return prepareWith(status, leaseExpiresAt, false)
+	}
+
+	makeOutput := func(status string, finished bool) map[string]interface{} {
+		output := map[string]interface{}{
+			"id":             testutils.GetUUID("job-01"),
+			"organizationId": testutils.GetUUID("org1"),
+			"type":           "copy_folder",
+			"status":         status,
+			"progress":       nil,
+			"result":         nil,
+			"error":          nil,
+			"createdAt":      testutils.Timestamp{},
+			"updatedAt":      testutils.Timestamp{},
+			"startedAt":      nil,
+			"finishedAt":     nil,
+		}
+		if finished {
+			// The job fails since it has no params
+			output["error"] = testutils.AnyVal{}
+			output["startedAt"] = testutils.Timestamp{}
+			output["finishedAt"] = testutils.Timestamp{}
+		}
+		return output
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Pending",
+			Prepare:    prepare("pending", time.Time{}),
+			Path:       makePath(testutils.GetUUID("job-01")),
+			StatusCode: http.StatusOK,
+			Output:     makeOutput("failed", true),
+		},
+		{
+			Title:      "Lease expired",
+			Prepare:    prepare("running", time.Now().UTC().Add(-time.Minute)),
+			Path:       makePath(testutils.GetUUID("job-01")),
+			StatusCode: http.StatusOK,
+			Output:     makeOutput("failed", true),
+		},
+		{
+			Title:      "Lease held by another runner",
+			Prepare:    prepare("running", time.Now().UTC().Add(time.Hour)),
+			Path:       makePath(testutils.GetUUID("job-01")),
+			StatusCode: http.StatusOK,
+			Output:     makeOutput("running", false),
+		},
+		{
+			Title:      "Stopped runner",
+			Prepare:    prepareWith("pending", time.Time{}, true),
+			Path:       makePath(testutils.GetUUID("job-01")),
+			StatusCode: http.StatusOK,
+			Output:     makeOutput("pending", false),
+		},
+		{
+			Title:      "Completed",
+			Prepare:    prepare("succeeded", time.Time{}),
+			Path:       makePath(testutils.GetUUID("job-01")),
+			StatusCode: http.StatusOK,
+			Output:     makeOutput("succeeded", false),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
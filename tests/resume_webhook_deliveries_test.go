@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/api/webhooks"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestResumeWebhookDeliveries(t *testing.T) {
+	recv := newWebhookReceiver()
+	defer recv.Close()
+
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/webhooks/%s/deliveries", id)
+	}
+
+	// prepare loads a delivery of another dispatcher holding the lease until leaseExpiresAt,
+	// then resumes deliveries with a new dispatcher
+	prepare := func(status string, leaseExpiresAt *time.Time) func(*testutils.APITestCase, *gorm.DB) error {
+		return func(tc *testutils.APITestCase, db *gorm.DB) error {
+			recv.reset()
+			err := testutils.LoadFixture(fmt.Sprintf(`
+			organizations:
+			  - id: org1
+			    webhooks:
+			      - id: webhook-01
+			        url: %s
+			        deliveries:
+			          - id: delivery-01
+			            eventType: record.inserted
+			            status: %s
+			`, recv.URL, status))
+			if err != nil {
+				return err
+			}
+			err = db.Exec("UPDATE webhook_deliveries SET owner = ?, lease_expires_at = ? WHERE id = ?",
+				"other-dispatcher", leaseExpiresAt, models.UUID(testutils.GetUUID("delivery-01"))).Error
+			if err != nil {
+				return err
+			}
+
+			// The receiver listens on the loopback address
+			dispatcher := webhooks.NewDispatcher(db, &webhooks.Config{AllowPrivateNetworks: true})
+			if err := dispatcher.Resume(); err != nil {
+				return err
+			}
+			dispatcher.Wait()
+			return nil
+		}
+	}
+
+	expired := time.Now().UTC().Add(-time.Minute)
+	held := time.Now().UTC().Add(time.Hour)
+
+	makeOutput := func(status string, delivered bool) map[string]interface{} {
+		delivery := map[string]interface{}{
+			"id":             testutils.GetUUID("delivery-01"),
+			"webhookId":      testutils.GetUUID("webhook-01"),
+			"eventId":        testutils.UUID{},
+			"eventType":      "record.inserted",
+			"payload":        map[string]interface{}{},
+			"status":         status,
+			"attempts":       float64(0),
+			"responseStatus": nil,
+			"error":          nil,
+			"createdAt":      testutils.Timestamp{},
+			"updatedAt":      testutils.Timestamp{},
+			"deliveredAt":    nil,
+		}
+		if delivered {
+			delivery["attempts"] = float64(1)
+			delivery["responseStatus"] = float64(http.StatusOK)
+			delivery["deliveredAt"] = testutils.Timestamp{}
+		}
+		return map[string]interface{}{
+			"deliveries": []interface{}{delivery},
+			"totalCount": float64(1),
+		}
+	}
+
+	checkReceived := func(expected bool) func(*testutils.APITestCase, http.Handler, map[string]interface{}) {
+		return func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+			req := recv.receive(100 * time.Millisecond)
+			if expected && req == nil {
+				t.Errorf("[%s] Webhook not received", tc.Title)
+			}
+			if !expected && req != nil {
+				t.Errorf("[%s] Received webhook: %s", tc.Title, req.body)
+			}
+		}
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Pending",
+			Prepare:    prepare("pending", nil),
+			Path:       makePath(testutils.GetUUID("webhook-01")),
+			StatusCode: http.StatusOK,
+			Output:     makeOutput("succeeded", true),
+			PostCheck:  checkReceived(true),
+		},
+		{
+			Title:      "Lease expired",
+			Prepare:    prepare("pending", &expired),
+			Path:       makePath(testutils.GetUUID("webhook-01")),
+			StatusCode: http.StatusOK,
+			Output:     makeOutput("succeeded", true),
+			PostCheck:  checkReceived(true),
+		},
+		{
+			Title:      "Lease held by another dispatcher",
+			Prepare:    prepare("pending", &held),
+			Path:       makePath(testutils.GetUUID("webhook-01")),
+			StatusCode: http.StatusOK,
+			Output:     makeOutput("pending", false),
+			PostCheck:  checkReceived(false),
+		},
+		{
+			Title:      "Completed",
+			Prepare:    prepare("failed", nil),
+			Path:       makePath(testutils.GetUUID("webhook-01")),
+			StatusCode: http.StatusOK,
+			Output:     makeOutput("failed", false),
+			PostCheck:  checkReceived(false),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestRevokeAPIKey(t *testing.T) {
+	makePath := func(organizationID, apiKeyID uuid.UUID) string {
+		return fmt.Sprintf("/organizations/%s/api-keys/%s", organizationID, apiKeyID)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    apiKeys:
+				      - id: key-01
+				        key: xb_key1aaaaaaaaaaaa
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("org1"), testutils.GetUUID("key-01")),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.GetUUID("key-01"),
+				"organizationId": testutils.GetUUID("org1"),
+				"prefix":         "xb_key1aaaaa",
+				"properties":     map[string]interface{}{},
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+				"lastUsedAt":     nil,
+				"revokedAt":      testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				res := testutils.ServeGet(router, fmt.Sprintf("/organizations/%s/api-keys", testutils.GetUUID("org1")), nil)
+				if diff := testutils.CompareJson(output, res["apiKeys"].([]interface{})[0]); diff != "" {
+					t.Errorf("[%s] Reacquired response mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title: "Key of another organization",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				  - id: org2
+				    apiKeys:
+				      - id: key-01
+				        key: xb_key1aaaaaaaaaaaa
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("org1"), testutils.GetUUID("key-01")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("org1"), testutils.GetUUID("key-01")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodDelete
+		testutils.RunTestCase(t, tc)
+	}
+}
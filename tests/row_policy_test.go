@@ -0,0 +1,258 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/api/models"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestRowPolicy(t *testing.T) {
+	config := &api.Config{
+		Auth: auth.Config{
+			Mode: auth.ModeAPIKey,
+		},
+	}
+	bearer := func(key string) http.Header {
+		return http.Header{"Authorization": []string{"Bearer " + key}}
+	}
+
+	// customer key is an editor limited to the records of customer c1 by the policy
+	customer := "xb_customerxxxxxxxx"
+	// anonymous key is an editor with no customer attribute
+	anonymous := "xb_anonymousxxxxxxx"
+	// owner key bypasses policies
+	owner := "xb_ownerxxxxxxxxxxx"
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(fmt.Sprintf(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: folder-01
+		        children:
+		          - id: table-01
+		            columns:
+		              - id: column-01
+		              - id: column-02
+		            records:
+		              - id: record-01
+		                data: [c1, x]
+		              - id: record-02
+		                data: [c2, "y"]
+		            policies:
+		              - where:
+		                  eq:
+		                    - {column: %s}
+		                    - {attribute: customer}
+		    apiKeys:
+		      - key: %s
+		        role: editor
+		        properties: {customer: c1}
+		      - key: %s
+		        role: editor
+		      - key: %s
+		        role: owner
+		`, testutils.GetUUID("column-01"), customer, anonymous, owner))
+	}
+	queryPath := fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01"))
+	selectQuery := makeJSON(`
+	select:
+	  columns:
+	    - column: {{ .column }}
+	`, map[string]interface{}{
+		"column": testutils.GetUUID("column-02"),
+	})
+	insertQuery := func(customer string) map[string]interface{} {
+		return makeJSON(`
+		insert:
+		  columns:
+		    - column: {{ .column }}
+		  values:
+		    - - value: {{ .customer }}
+		`, map[string]interface{}{
+			"column":   testutils.GetUUID("column-01"),
+			"customer": customer,
+		})
+	}
+	countRecords := func() int64 {
+		var count int64
+		if err := testutils.GetDB().Model(&models.TableRecord{}).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:      "Select is filtered",
+			Prepare:    prepare,
+			Path:       queryPath,
+			Header:     bearer(customer),
+			Body:       selectQuery,
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"records": []interface{}{
+					[]interface{}{"x"},
+				},
+				"limit": float64(10),
+			},
+		},
+		{
+			Title:      "Missing attribute matches nothing",
+			Prepare:    prepare,
+			Path:       queryPath,
+			Header:     bearer(anonymous),
+			Body:       selectQuery,
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"records": []interface{}{},
+				"limit":   float64(10),
+			},
+		},
+		{
+			Title:   "Owner bypasses policies",
+			Prepare: prepare,
+			Path:    queryPath,
+			Header:  bearer(owner),
+			Body: makeJSON(`
+			select:
+			  columns:
+			    - column: {{ .column }}
+			  orderBy: [{key: {column: {{ .column }} }}]
+			`, map[string]interface{}{
+				"column": testutils.GetUUID("column-02"),
+			}),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"records": []interface{}{
+					[]interface{}{"x"},
+					[]interface{}{"y"},
+				},
+				"limit": float64(10),
+			},
+		},
+		{
+			Title:      "Insert satisfying policies",
+			Prepare:    prepare,
+			Path:       queryPath,
+			Header:     bearer(customer),
+			Body:       insertQuery("c1"),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"recordIds": []interface{}{testutils.UUID{}},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				if count := countRecords(); count != 3 {
+					t.Errorf("[%s] Record count mismatch: %d", tc.Title, count)
+				}
+			},
+		},
+		{
+			Title:      "Insert violating policies",
+			Prepare:    prepare,
+			Path:       queryPath,
+			Header:     bearer(customer),
+			Body:       insertQuery("c2"),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Records do not satisfy table policies",
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				if count := countRecords(); count != 2 {
+					t.Errorf("[%s] Inserted record is not rolled back: %d", tc.Title, count)
+				}
+			},
+		},
+		{
+			Title:   "Update violating policies",
+			Prepare: prepare,
+			Path:    queryPath,
+			Header:  bearer(customer),
+			Body: makeJSON(`
+			update:
+			  set:
+			    - to: {column: {{ .column }} }
+			      value: {value: c2}
+			  where: {value: true}
+			`, map[string]interface{}{
+				"column": testutils.GetUUID("column-01"),
+			}),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Records do not satisfy table policies",
+			},
+		},
+		{
+			Title:   "Delete is filtered",
+			Prepare: prepare,
+			Path:    queryPath,
+			Header:  bearer(customer),
+			Body: makeJSON(`
+			delete:
+			  where: {value: true}
+			`, nil),
+			StatusCode: http.StatusOK,
+			Output:     map[string]interface{}{},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				var record models.TableRecord
+				if err := testutils.GetDB().Where("id = ?", models.UUID(testutils.GetUUID("record-02"))).First(&record).Error; err != nil {
+					t.Errorf("[%s] Record of another customer is deleted: %v", tc.Title, err)
+				}
+				if count := countRecords(); count != 1 {
+					t.Errorf("[%s] Record count mismatch: %d", tc.Title, count)
+				}
+			},
+		},
+		{
+			Title:      "Folder export is forbidden",
+			Prepare:    prepare,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/folders/%s/xlsx", testutils.GetUUID("folder-01")),
+			Header:     bearer(customer),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Cannot export records of tables with policies",
+			},
+		},
+		{
+			Title:   "Duplicate with records is forbidden",
+			Prepare: prepare,
+			Path:    fmt.Sprintf("/tables/%s/duplicate", testutils.GetUUID("table-01")),
+			Header:  bearer(customer),
+			Body: map[string]interface{}{
+				"includeRecords": true,
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Cannot copy records of tables with policies",
+			},
+		},
+		{
+			Title:   "Editor cannot manage policies",
+			Prepare: prepare,
+			Path:    fmt.Sprintf("/tables/%s/policies", testutils.GetUUID("table-01")),
+			Header:  bearer(customer),
+			Body: map[string]interface{}{
+				"where": map[string]interface{}{"value": true},
+			},
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.Method == "" {
+			tc.Method = http.MethodPost
+		}
+		tc.Config = config
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,324 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestSearchTableFilesystemEntries(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/organizations/%s/search", id)
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: folder-01
+		        properties:
+		          name: Sales
+		        children:
+		          - id: table-01
+		            properties:
+		              name: Sales 2021
+		              memo: a
+		          - id: folder-02
+		            properties:
+		              name: Archive
+		            children:
+		              - id: table-02
+		                properties:
+		                  name: Old sales
+		      - id: table-03
+		        properties:
+		          name: 100%_done
+		  - id: org2
+		    tables:
+		      - id: table-04
+		        properties:
+		          name: Sales
+		`)
+	}
+	entry := func(id, typ string, props map[string]interface{}, path ...interface{}) map[string]interface{} {
+		if path == nil {
+			path = []interface{}{}
+		}
+		return map[string]interface{}{
+			"id":             testutils.GetUUID(id),
+			"organizationId": testutils.GetUUID("org1"),
+			"type":           typ,
+			"path":           path,
+			"properties":     props,
+			"createdAt":      testutils.Timestamp{},
+			"updatedAt":      testutils.Timestamp{},
+		}
+	}
+	pathEntry := func(id string, props map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"id":         testutils.GetUUID(id),
+			"type":       "folder",
+			"properties": props,
+		}
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "Prefix",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"prefix":     []string{"sales"},
+				"properties": []string{"name"},
+				"sort":       []string{"property.name:asc"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"entries": []interface{}{
+					entry("folder-01", "folder", map[string]interface{}{"name": "Sales"}),
+					entry("table-01", "table", map[string]interface{}{"name": "Sales 2021"},
+						pathEntry("folder-01", map[string]interface{}{"name": "Sales"})),
+				},
+				"totalCount": float64(2),
+			},
+		},
+		{
+			Title:   "Contains",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"contains":   []string{"SALES"},
+				"properties": []string{"name"},
+				"sort":       []string{"property.name:asc"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"entries": []interface{}{
+					entry("table-02", "table", map[string]interface{}{"name": "Old sales"},
+						pathEntry("folder-01", map[string]interface{}{"name": "Sales"}),
+						pathEntry("folder-02", map[string]interface{}{"name": "Archive"})),
+					entry("folder-01", "folder", map[string]interface{}{"name": "Sales"}),
+					entry("table-01", "table", map[string]interface{}{"name": "Sales 2021"},
+						pathEntry("folder-01", map[string]interface{}{"name": "Sales"})),
+				},
+				"totalCount": float64(3),
+			},
+		},
+		{
+			Title:   "Type and pagination",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"contains":   []string{"sales"},
+				"type":       []string{"table"},
+				"properties": []string{"name"},
+				"sort":       []string{"property.name:asc"},
+				"page":       []string{"2"},
+				"pageSize":   []string{"1"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"entries": []interface{}{
+					entry("table-01", "table", map[string]interface{}{"name": "Sales 2021"},
+						pathEntry("folder-01", map[string]interface{}{"name": "Sales"})),
+				},
+				"totalCount": float64(2),
+			},
+		},
+		{
+			Title:   "Other property",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"property": []string{"memo"},
+				"prefix":   []string{"a"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"entries": []interface{}{
+					entry("table-01", "table", map[string]interface{}{"name": "Sales 2021", "memo": "a"},
+						pathEntry("folder-01", map[string]interface{}{"name": "Sales"})),
+				},
+				"totalCount": float64(1),
+			},
+		},
+		{
+			Title:   "Wildcards are escaped",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"contains": []string{"%_"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"entries": []interface{}{
+					entry("table-03", "table", map[string]interface{}{"name": "100%_done"}),
+				},
+				"totalCount": float64(1),
+			},
+		},
+		{
+			Title:   "No match",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"prefix": []string{"xxx"},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"entries":    []interface{}{},
+				"totalCount": float64(0),
+			},
+		},
+		{
+			Title:   "Invalid type",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"type": []string{"record"},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Invalid request parameter: Invalid input: Key: 'SearchTableFilesystemEntriesInput.Type' Error:Field validation for 'Type' failed on the 'oneof' tag",
+			},
+		},
+		{
+			Title:   "Invalid property",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1")),
+			Query: url.Values{
+				"property": []string{"a'b"},
+				"prefix":   []string{"a"},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Invalid property key",
+			},
+		},
+		{
+			Title:      "Not found",
+			Prepare:    prepare,
+			Path:       makePath(testutils.GetUUID("org3")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
+
+func TestSearchTableFilesystemEntriesAccessControl(t *testing.T) {
+	config := &api.Config{
+		Auth: auth.Config{
+			Mode: auth.ModeAPIKey,
+		},
+	}
+	bearer := func(key string) http.Header {
+		return http.Header{"Authorization": []string{"Bearer " + key}}
+	}
+
+	// restricted key views only folder-02 and its descendants, whose paths start at folder-02
+	restricted := "xb_restrictedxxxxxx"
+	// none key has no role
+	none := "xb_nonexxxxxxxxxxxx"
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(fmt.Sprintf(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: folder-01
+		        properties:
+		          name: a1
+		        children:
+		          - id: table-01
+		            properties:
+		              name: a2
+		          - id: folder-02
+		            properties:
+		              name: a3
+		            children:
+		              - id: table-02
+		                properties:
+		                  name: a4
+		    apiKeys:
+		      - key: %s
+		        role: none
+		        roleBindings:
+		          - resourceId: folder-02
+		            role: viewer
+		      - key: %s
+		        role: none
+		`, restricted, none))
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "Only readable entries",
+			Prepare: prepare,
+			Path:    fmt.Sprintf("/organizations/%s/search", testutils.GetUUID("org1")),
+			Query: url.Values{
+				"prefix":     []string{"a"},
+				"properties": []string{"name"},
+				"sort":       []string{"property.name:asc"},
+			},
+			Header:     bearer(restricted),
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"entries": []interface{}{
+					map[string]interface{}{
+						"id":             testutils.GetUUID("folder-02"),
+						"organizationId": testutils.GetUUID("org1"),
+						"type":           "folder",
+						"path":           []interface{}{},
+						"properties":     map[string]interface{}{"name": "a3"},
+						"createdAt":      testutils.Timestamp{},
+						"updatedAt":      testutils.Timestamp{},
+					},
+					map[string]interface{}{
+						"id":             testutils.GetUUID("table-02"),
+						"organizationId": testutils.GetUUID("org1"),
+						"type":           "table",
+						"path": []interface{}{
+							map[string]interface{}{
+								"id":         testutils.GetUUID("folder-02"),
+								"type":       "folder",
+								"properties": map[string]interface{}{"name": "a3"},
+							},
+						},
+						"properties": map[string]interface{}{"name": "a4"},
+						"createdAt":  testutils.Timestamp{},
+						"updatedAt":  testutils.Timestamp{},
+					},
+				},
+				"totalCount": float64(2),
+			},
+		},
+		{
+			Title:      "No role",
+			Prepare:    prepare,
+			Path:       fmt.Sprintf("/organizations/%s/search", testutils.GetUUID("org1")),
+			Header:     bearer(none),
+			StatusCode: http.StatusForbidden,
+			Output: map[string]interface{}{
+				"message": "Forbidden",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Config = config
+		testutils.RunTestCase(t, tc)
+	}
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestStreamFolderEvents(t *testing.T) {
+	fixture := `
+	organizations:
+	  - id: org1
+	    tables:
+	      - id: folder-01
+	        children:
+	          - id: folder-02
+	            children:
+	              - id: table-01
+	                columns:
+	                  - id: column-01
+	      - id: table-02
+	        columns:
+	          - id: column-02
+	`
+
+	t.Run("Events in subtree", func(t *testing.T) {
+		server := startStreamServer(t, fixture)
+		defer server.Close()
+
+		stream := openEventStream(t, server, fmt.Sprintf("/folders/%s/events", testutils.GetUUID("folder-01")), nil)
+		defer stream.close()
+
+		insertRecord(t, server, testutils.GetUUID("table-02"), testutils.GetUUID("column-02"))
+		insertRecord(t, server, testutils.GetUUID("table-01"), testutils.GetUUID("column-01"))
+		postJSON(t, server, "/folders", map[string]interface{}{
+			"organizationId": testutils.GetUUID("org1"),
+			"parentFolderId": testutils.GetUUID("folder-02"),
+		})
+
+		e := stream.next(10 * time.Second)
+		if e == nil || e.Event != "record.inserted" || e.Data["tableId"] != testutils.GetUUID("table-01").String() {
+			t.Fatalf("Unexpected event: %+v", e)
+		}
+		e = stream.next(10 * time.Second)
+		if e == nil || e.Event != "folder.created" {
+			t.Fatalf("Unexpected event: %+v", e)
+		}
+	})
+
+	t.Run("Root folder", func(t *testing.T) {
+		server := startStreamServer(t, fixture)
+		defer server.Close()
+
+		stream := openEventStream(t, server, fmt.Sprintf("/folders/%s/events?organizationId=%s", uuid.Nil, testutils.GetUUID("org1")), nil)
+		defer stream.close()
+
+		insertRecord(t, server, testutils.GetUUID("table-02"), testutils.GetUUID("column-02"))
+		insertRecord(t, server, testutils.GetUUID("table-01"), testutils.GetUUID("column-01"))
+
+		for _, tableID := range []uuid.UUID{testutils.GetUUID("table-02"), testutils.GetUUID("table-01")} {
+			e := stream.next(10 * time.Second)
+			if e == nil || e.Event != "record.inserted" || e.Data["tableId"] != tableID.String() {
+				t.Fatalf("Unexpected event: %+v", e)
+			}
+		}
+	})
+
+	t.Run("Record events of tables with policies", func(t *testing.T) {
+		server := startPolicyStreamServer(t)
+		defer server.Close()
+
+		stream := openEventStream(t, server, fmt.Sprintf("/folders/%s/events", testutils.GetUUID("folder-01")), bearerHeader("xb_customerxxxxxxxx"))
+		defer stream.close()
+
+		insertCustomerRecord(t, server, "c1")
+		postJSONWithHeader(t, server, "/folders", bearerHeader("xb_ownerxxxxxxxxxxx"), map[string]interface{}{
+			"organizationId": testutils.GetUUID("org1"),
+			"parentFolderId": testutils.GetUUID("folder-01"),
+		})
+
+		e := stream.next(10 * time.Second)
+		if e == nil || e.Event != "folder.created" {
+			t.Fatalf("Unexpected event: %+v", e)
+		}
+	})
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path:       fmt.Sprintf("/folders/%s/events", testutils.GetUUID("folder-03")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+		{
+			Title: "Root folder without organization id",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path:       fmt.Sprintf("/folders/%s/events", uuid.Nil),
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Organization id is required for root folder",
+			},
+		},
+		{
+			Title: "Negative last event id",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: fmt.Sprintf("/folders/%s/events", testutils.GetUUID("folder-01")),
+			Query: url.Values{
+				"lastEventId": []string{"-1"},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request parameter`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
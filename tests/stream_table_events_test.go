@@ -0,0 +1,366 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/auth"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+type streamEvent struct {
+	ID    string
+	Event string
+	Data  map[string]interface{}
+}
+
+type eventStream struct {
+	cancel context.CancelFunc
+	events chan streamEvent
+}
+
+func openEventStream(t *testing.T, server *httptest.Server, path string, header http.Header) *eventStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Failed to open stream: status=%d", res.StatusCode)
+	}
+
+	stream := &eventStream{
+		cancel: cancel,
+		events: make(chan streamEvent, 100),
+	}
+	go func() {
+		defer res.Body.Close()
+		defer close(stream.events)
+		scanner := bufio.NewScanner(res.Body)
+		var e streamEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if e.Event != "" {
+					stream.events <- e
+				}
+				e = streamEvent{}
+			case strings.HasPrefix(line, "id: "):
+				e.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				e.Event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e.Data)
+			}
+		}
+	}()
+	return stream
+}
+
+func (s *eventStream) next(timeout time.Duration) *streamEvent {
+	select {
+	case e, ok := <-s.events:
+		if !ok {
+			return nil
+		}
+		return &e
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func (s *eventStream) close() {
+	s.cancel()
+}
+
+func postJSON(t *testing.T, server *httptest.Server, path string, body map[string]interface{}) {
+	postJSONWithHeader(t, server, path, nil, body)
+}
+
+func postJSONWithHeader(t *testing.T, server *httptest.Server, path string, header http.Header, body map[string]interface{}) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, server.URL+path, bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Request failed: path=%s, status=%d", path, res.StatusCode)
+	}
+}
+
+func insertRecord(t *testing.T, server *httptest.Server, tableID, columnID uuid.UUID) {
+	postJSON(t, server, fmt.Sprintf("/tables/%s/query", tableID), makeJSON(`
+	insert:
+	  columns:
+	    - column: {{ .column }}
+	  values:
+	    - - value: 1
+	`, map[string]interface{}{
+		"column": columnID,
+	}))
+}
+
+func startStreamServer(t *testing.T, fixture string) *httptest.Server {
+	return startStreamServerWithConfig(t, fixture, nil)
+}
+
+func startStreamServerWithConfig(t *testing.T, fixture string, conf *api.Config) *httptest.Server {
+	testutils.RefreshDB()
+	if err := testutils.LoadFixture(fixture); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return httptest.NewServer(api.CreateRouter(testutils.GetDB(), conf))
+}
+
+// policyStreamFixture has table-01 in folder-01 whose records are limited to the customer attribute.
+// The customer key is an editor of customer c1 and the owner key bypasses the policy.
+const policyStreamFixture = `
+organizations:
+  - id: org1
+    tables:
+      - id: folder-01
+        children:
+          - id: table-01
+            columns:
+              - id: column-01
+            policies:
+              - where:
+                  eq:
+                    - {column: %s}
+                    - {attribute: customer}
+    apiKeys:
+      - key: xb_customerxxxxxxxx
+        role: editor
+        properties: {customer: c1}
+      - key: xb_ownerxxxxxxxxxxx
+        role: owner
+`
+
+func startPolicyStreamServer(t *testing.T) *httptest.Server {
+	return startStreamServerWithConfig(t, fmt.Sprintf(policyStreamFixture, testutils.GetUUID("column-01")), &api.Config{
+		Auth: auth.Config{
+			Mode: auth.ModeAPIKey,
+		},
+	})
+}
+
+func insertCustomerRecord(t *testing.T, server *httptest.Server, customer string) {
+	postJSONWithHeader(t, server, fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")), bearerHeader("xb_ownerxxxxxxxxxxx"), makeJSON(`
+	insert:
+	  columns:
+	    - column: {{ .column }}
+	  values:
+	    - - value: {{ .customer }}
+	`, map[string]interface{}{
+		"column":   testutils.GetUUID("column-01"),
+		"customer": customer,
+	}))
+}
+
+func bearerHeader(key string) http.Header {
+	return http.Header{"Authorization": []string{"Bearer " + key}}
+}
+
+func TestStreamTableEvents(t *testing.T) {
+	fixture := `
+	organizations:
+	  - id: org1
+	    tables:
+	      - id: table-01
+	        columns:
+	          - id: column-01
+	      - id: table-02
+	        columns:
+	          - id: column-02
+	`
+
+	t.Run("Record and column events", func(t *testing.T) {
+		server := startStreamServer(t, fixture)
+		defer server.Close()
+
+		stream := openEventStream(t, server, fmt.Sprintf("/tables/%s/events", testutils.GetUUID("table-01")), nil)
+		defer stream.close()
+
+		insertRecord(t, server, testutils.GetUUID("table-02"), testutils.GetUUID("column-02"))
+		insertRecord(t, server, testutils.GetUUID("table-01"), testutils.GetUUID("column-01"))
+		postJSON(t, server, fmt.Sprintf("/tables/%s/columns", testutils.GetUUID("table-01")), map[string]interface{}{})
+
+		e := stream.next(10 * time.Second)
+		if e == nil || e.Event != "record.inserted" || e.Data["tableId"] != testutils.GetUUID("table-01").String() {
+			t.Fatalf("Unexpected event: %+v", e)
+		}
+		if recordIDs := e.Data["data"].(map[string]interface{})["recordIds"].([]interface{}); len(recordIDs) != 1 {
+			t.Errorf("Unexpected record ids: %v", recordIDs)
+		}
+		e = stream.next(10 * time.Second)
+		if e == nil || e.Event != "column.created" {
+			t.Fatalf("Unexpected event: %+v", e)
+		}
+	})
+
+	t.Run("Resume from last event id", func(t *testing.T) {
+		server := startStreamServer(t, fixture)
+		defer server.Close()
+
+		path := fmt.Sprintf("/tables/%s/events", testutils.GetUUID("table-01"))
+		stream := openEventStream(t, server, path, nil)
+
+		insertRecord(t, server, testutils.GetUUID("table-01"), testutils.GetUUID("column-01"))
+		first := stream.next(10 * time.Second)
+		if first == nil {
+			t.Fatal("Event not received")
+		}
+		stream.close()
+
+		// Changes while disconnected
+		postJSON(t, server, fmt.Sprintf("/tables/%s/columns", testutils.GetUUID("table-01")), map[string]interface{}{})
+		insertRecord(t, server, testutils.GetUUID("table-01"), testutils.GetUUID("column-01"))
+
+		stream = openEventStream(t, server, path, http.Header{"Last-Event-ID": []string{first.ID}})
+		defer stream.close()
+
+		for _, expected := range []string{"column.created", "record.inserted"} {
+			e := stream.next(10 * time.Second)
+			if e == nil || e.Event != expected {
+				t.Fatalf("Unexpected event: expected=%s, actual=%+v", expected, e)
+			}
+		}
+	})
+
+	t.Run("Records hidden by table policies", func(t *testing.T) {
+		server := startPolicyStreamServer(t)
+		defer server.Close()
+
+		stream := openEventStream(t, server, fmt.Sprintf("/tables/%s/events", testutils.GetUUID("table-01")), bearerHeader("xb_customerxxxxxxxx"))
+		defer stream.close()
+
+		insertCustomerRecord(t, server, "c2")
+		insertCustomerRecord(t, server, "c1")
+		postJSONWithHeader(t, server, fmt.Sprintf("/tables/%s/columns", testutils.GetUUID("table-01")), bearerHeader("xb_ownerxxxxxxxxxxx"), map[string]interface{}{})
+
+		e := stream.next(10 * time.Second)
+		if e == nil || e.Event != "record.inserted" {
+			t.Fatalf("Unexpected event: %+v", e)
+		}
+		if recordIDs := e.Data["data"].(map[string]interface{})["recordIds"].([]interface{}); len(recordIDs) != 1 {
+			t.Errorf("Unexpected record ids: %v", recordIDs)
+		}
+		e = stream.next(10 * time.Second)
+		if e == nil || e.Event != "column.created" {
+			t.Fatalf("Unexpected event: %+v", e)
+		}
+	})
+
+	t.Run("Records changed out of table policies", func(t *testing.T) {
+		server := startPolicyStreamServer(t)
+		defer server.Close()
+
+		stream := openEventStream(t, server, fmt.Sprintf("/tables/%s/events", testutils.GetUUID("table-01")), bearerHeader("xb_customerxxxxxxxx"))
+		defer stream.close()
+
+		query := func(q string, from, to string) {
+			postJSONWithHeader(t, server, fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")), bearerHeader("xb_ownerxxxxxxxxxxx"), makeJSON(q, map[string]interface{}{
+				"column": testutils.GetUUID("column-01"),
+				"from":   from,
+				"to":     to,
+			}))
+		}
+		update := `
+		update:
+		  set:
+		    - to: {column: {{ .column }} }
+		      value: {value: {{ .to }} }
+		  where: {eq: [{column: {{ .column }} }, {value: {{ .from }} }]}
+		`
+		remove := `
+		delete:
+		  where: {eq: [{column: {{ .column }} }, {value: {{ .from }} }]}
+		`
+
+		insertCustomerRecord(t, server, "c1")
+		query(update, "c1", "c2")
+		query(update, "c2", "c3")
+		insertCustomerRecord(t, server, "c1")
+		query(remove, "c1", "")
+		postJSONWithHeader(t, server, fmt.Sprintf("/tables/%s/columns", testutils.GetUUID("table-01")), bearerHeader("xb_ownerxxxxxxxxxxx"), map[string]interface{}{})
+
+		// The update from c2 to c3 is hidden since the record was not visible before nor after it
+		for _, expected := range []string{"record.inserted", "record.updated", "record.inserted", "record.deleted", "column.created"} {
+			e := stream.next(10 * time.Second)
+			if e == nil || e.Event != expected {
+				t.Fatalf("Unexpected event: expected=%s, actual=%+v", expected, e)
+			}
+			if expected == "column.created" {
+				continue
+			}
+			if recordIDs := e.Data["data"].(map[string]interface{})["recordIds"].([]interface{}); len(recordIDs) != 1 {
+				t.Errorf("Unexpected record ids: %v", recordIDs)
+			}
+		}
+	})
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path:       fmt.Sprintf("/tables/%s/events", testutils.GetUUID("table-03")),
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+		{
+			Title: "Invalid last event id",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(fixture)
+			},
+			Path: fmt.Sprintf("/tables/%s/events", testutils.GetUUID("table-01")),
+			Header: http.Header{
+				"Last-Event-Id": []string{"abc"},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request header`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodGet
+		testutils.RunTestCase(t, tc)
+	}
+}
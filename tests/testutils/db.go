@@ -1,9 +1,11 @@
 package testutils
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -25,7 +27,12 @@ func makeDBConfig() *databases.DBConfig {
 	dbName := os.Getenv("DB_NAME")
 	dbType := os.Getenv("DB_TYPE")
 
-	if !strings.HasSuffix(dbName, "_test") {
+	// Database file of SQLite may have an extension
+	name := dbName
+	if dbType == databases.DBTypeSQLite {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	if !strings.HasSuffix(name, "_test") {
 		log.Fatal("Database name must have suffix '_test' for testing")
 	}
 
@@ -46,6 +53,23 @@ func GetDB() *gorm.DB {
 func RefreshDB() {
 	dbConfig := makeDBConfig()
 
+	switch dbConfig.DBType {
+	case databases.DBTypeSQLite:
+		refreshSQLiteDB()
+	case databases.DBTypePostgres:
+		refreshPostgresDB()
+	default:
+		refreshMySQLDB(dbConfig)
+	}
+
+	// Create tables
+	err := databases.Setup(dbConfig, os.Getenv("MIGRATIONS_DIR"))
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func refreshMySQLDB(dbConfig *databases.DBConfig) {
 	// Get table names
 	rows, err := db.Raw("SELECT table_name FROM information_schema.tables WHERE table_schema = ?", dbConfig.DBName).Rows()
 	if err != nil {
@@ -75,10 +99,53 @@ func RefreshDB() {
 	if err != nil {
 		log.Fatal(err)
 	}
+}
 
-	// Create tables
-	err = databases.Setup(dbConfig, os.Getenv("MIGRATIONS_DIR"))
+func refreshPostgresDB() {
+	// Get table names
+	var tableNames []string
+	err := db.Raw("SELECT tablename FROM pg_tables WHERE schemaname = current_schema()").Scan(&tableNames).Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Drop tables with the constraints referring to them
+	for _, tableName := range tableNames {
+		err := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", tableName)).Error
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func refreshSQLiteDB() {
+	// Get table names
+	var tableNames []string
+	err := db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'").Scan(&tableNames).Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Drop tables, with the foreign key constraints disabled on the connection
+	sqlDB, err := db.DB()
 	if err != nil {
 		log.Fatal(err)
 	}
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(context.Background(), "PRAGMA foreign_keys = OFF"); err != nil {
+		log.Fatal(err)
+	}
+	for _, tableName := range tableNames {
+		_, err := conn.ExecContext(context.Background(), fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if _, err := conn.ExecContext(context.Background(), "PRAGMA foreign_keys = ON"); err != nil {
+		log.Fatal(err)
+	}
 }
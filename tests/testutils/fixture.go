@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"github.com/tsujio/x-base/api/auth"
 	"github.com/tsujio/x-base/api/models"
 )
 
@@ -107,6 +108,45 @@ func createOrganization(organization interface{}, path string) error {
 				return err
 			}
 		}
+
+		// jobs
+		if jobs, exists := org["jobs"]; exists {
+			if js, ok := jobs.([]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".jobs", jobs)
+			} else {
+				for i, j := range js {
+					if err := createJob(j, fmt.Sprintf("%s.jobs[%d]", path, i), o); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// webhooks
+		if webhooks, exists := org["webhooks"]; exists {
+			if ws, ok := webhooks.([]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".webhooks", webhooks)
+			} else {
+				for i, wh := range ws {
+					if err := createWebhook(wh, fmt.Sprintf("%s.webhooks[%d]", path, i), o); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// apiKeys
+		if apiKeys, exists := org["apiKeys"]; exists {
+			if ks, ok := apiKeys.([]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".apiKeys", apiKeys)
+			} else {
+				for i, k := range ks {
+					if err := createAPIKey(k, fmt.Sprintf("%s.apiKeys[%d]", path, i), o); err != nil {
+						return err
+					}
+				}
+			}
+		}
 	}
 	return nil
 }
@@ -247,6 +287,18 @@ func createTable(table interface{}, path string, organizationID, parentFolderID
 					}
 				}
 			}
+
+			if policies, exists := tbl["policies"]; exists {
+				if ps, ok := policies.([]interface{}); !ok {
+					return fmt.Errorf("Invalid type: path=%s, type=%T", path+".policies", policies)
+				} else {
+					for i, p := range ps {
+						if err := createTablePolicy(p, fmt.Sprintf("%s.policies[%d]", path, i), t); err != nil {
+							return err
+						}
+					}
+				}
+			}
 		}
 	}
 	return nil
@@ -297,6 +349,15 @@ func createColumn(column interface{}, path string, table models.Table, index int
 		// Index
 		c.Index = index
 
+		// Type
+		if typ, exists := col["type"]; exists {
+			if t, ok := typ.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".type", typ)
+			} else {
+				c.Type = &t
+			}
+		}
+
 		// Properties
 		if properties, exists := col["properties"]; exists {
 			if props, ok := properties.(map[string]interface{}); !ok {
@@ -383,3 +444,447 @@ func createTableRecord(record interface{}, path string, table models.Table) erro
 	}
 	return nil
 }
+
+func createTablePolicy(policy interface{}, path string, table models.Table) error {
+	if pl, ok := policy.(map[string]interface{}); !ok {
+		return fmt.Errorf("Invalid type: path=%s, type=%T", path, policy)
+	} else {
+		p := &models.TablePolicy{}
+
+		// ID
+		if id, exists := pl["id"]; exists {
+			if idStr, ok := id.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".id", id)
+			} else {
+				p.ID = models.UUID(GetUUID(idStr))
+			}
+		} else {
+			p.ID = models.UUID(uuid.New())
+		}
+
+		// TableID
+		p.TableID = table.ID
+
+		// Where
+		if where, exists := pl["where"]; !exists {
+			return fmt.Errorf(".where required: path=%s", path)
+		} else {
+			j, err := json.Marshal(where)
+			if err != nil {
+				return err
+			}
+			p.Where = j
+		}
+
+		// Properties
+		if properties, exists := pl["properties"]; exists {
+			if props, ok := properties.(map[string]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".properties", properties)
+			} else {
+				p.Properties = props
+			}
+		}
+
+		if err := p.Create(GetDB()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createJob(job interface{}, path string, organization models.Organization) error {
+	if jb, ok := job.(map[string]interface{}); !ok {
+		return fmt.Errorf("Invalid type: path=%s, type=%T", path, job)
+	} else {
+		j := &models.Job{}
+
+		// ID
+		if id, exists := jb["id"]; exists {
+			if idStr, ok := id.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".id", id)
+			} else {
+				j.ID = models.UUID(GetUUID(idStr))
+			}
+		} else {
+			j.ID = models.UUID(uuid.New())
+		}
+
+		// OrganizationID
+		j.OrganizationID = organization.ID
+
+		// Type, Status, Error
+		for key, dest := range map[string]*string{"type": &j.Type, "status": &j.Status} {
+			if val, exists := jb[key]; exists {
+				if v, ok := val.(string); !ok {
+					return fmt.Errorf("Invalid type: path=%s, type=%T", path+"."+key, val)
+				} else {
+					*dest = v
+				}
+			}
+		}
+		if e, exists := jb["error"]; exists {
+			if eStr, ok := e.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".error", e)
+			} else {
+				j.Error = &eStr
+			}
+		}
+
+		// Params
+		params := map[string]interface{}{}
+		if p, exists := jb["params"]; exists {
+			if ps, ok := p.(map[string]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".params", p)
+			} else {
+				params = ps
+			}
+		}
+		b, err := json.Marshal(&params)
+		if err != nil {
+			return err
+		}
+		j.Params = b
+
+		// Result
+		if result, exists := jb["result"]; exists {
+			b, err := json.Marshal(&result)
+			if err != nil {
+				return err
+			}
+			j.Result = b
+		}
+
+		if err := j.Create(GetDB()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createWebhook(webhook interface{}, path string, organization models.Organization) error {
+	if wh, ok := webhook.(map[string]interface{}); !ok {
+		return fmt.Errorf("Invalid type: path=%s, type=%T", path, webhook)
+	} else {
+		w := &models.Webhook{}
+
+		// ID
+		if id, exists := wh["id"]; exists {
+			if idStr, ok := id.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".id", id)
+			} else {
+				w.ID = models.UUID(GetUUID(idStr))
+			}
+		} else {
+			w.ID = models.UUID(uuid.New())
+		}
+
+		// OrganizationID
+		w.OrganizationID = organization.ID
+
+		// TableID
+		if tableID, exists := wh["tableId"]; exists {
+			if idStr, ok := tableID.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".tableId", tableID)
+			} else {
+				id := models.UUID(GetUUID(idStr))
+				w.TableID = &id
+			}
+		}
+
+		// URL, Secret
+		w.URL = "http://localhost/webhook"
+		w.Secret = "secret"
+		for key, dest := range map[string]*string{"url": &w.URL, "secret": &w.Secret} {
+			if val, exists := wh[key]; exists {
+				if v, ok := val.(string); !ok {
+					return fmt.Errorf("Invalid type: path=%s, type=%T", path+"."+key, val)
+				} else {
+					*dest = v
+				}
+			}
+		}
+
+		// Events
+		if evs, exists := wh["events"]; exists {
+			if es, ok := evs.([]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".events", evs)
+			} else {
+				for i, e := range es {
+					if eStr, ok := e.(string); !ok {
+						return fmt.Errorf("Invalid type: path=%s[%d], type=%T", path+".events", i, e)
+					} else {
+						w.Events = append(w.Events, eStr)
+					}
+				}
+			}
+		}
+
+		// Properties
+		if properties, exists := wh["properties"]; exists {
+			if props, ok := properties.(map[string]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".properties", properties)
+			} else {
+				w.Properties = props
+			}
+		}
+
+		// CreatedAt
+		if createdAt, exists := wh["createdAt"]; exists {
+			if createdAtStr, ok := createdAt.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".createdAt", createdAt)
+			} else {
+				if createdAtTime, err := time.Parse(time.RFC3339, createdAtStr); err != nil {
+					return fmt.Errorf("Invalid time format: path=%s", path+".createdAt")
+				} else {
+					w.CreatedAt = createdAtTime
+				}
+			}
+		}
+
+		if err := w.Create(GetDB()); err != nil {
+			return err
+		}
+
+		// deliveries
+		if deliveries, exists := wh["deliveries"]; exists {
+			if ds, ok := deliveries.([]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".deliveries", deliveries)
+			} else {
+				for i, d := range ds {
+					if err := createWebhookDelivery(d, fmt.Sprintf("%s.deliveries[%d]", path, i), w); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func createAPIKey(apiKey interface{}, path string, organization models.Organization) error {
+	if ak, ok := apiKey.(map[string]interface{}); !ok {
+		return fmt.Errorf("Invalid type: path=%s, type=%T", path, apiKey)
+	} else {
+		k := &models.APIKey{}
+
+		// ID
+		if id, exists := ak["id"]; exists {
+			if idStr, ok := id.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".id", id)
+			} else {
+				k.ID = models.UUID(GetUUID(idStr))
+			}
+		} else {
+			k.ID = models.UUID(uuid.New())
+		}
+
+		// OrganizationID
+		k.OrganizationID = organization.ID
+
+		// Key
+		if key, exists := ak["key"]; exists {
+			if keyStr, ok := key.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".key", key)
+			} else {
+				k.Prefix = keyStr
+				if len(k.Prefix) > 12 {
+					k.Prefix = k.Prefix[:12]
+				}
+				k.KeyHash = auth.HashAPIKey(keyStr)
+			}
+		} else {
+			return fmt.Errorf("Key is required: path=%s", path+".key")
+		}
+
+		// Properties
+		if properties, exists := ak["properties"]; exists {
+			if props, ok := properties.(map[string]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".properties", properties)
+			} else {
+				k.Properties = props
+			}
+		}
+
+		// Revoked
+		if revoked, exists := ak["revoked"]; exists {
+			if r, ok := revoked.(bool); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".revoked", revoked)
+			} else if r {
+				now := time.Now().UTC()
+				k.RevokedAt = &now
+			}
+		}
+
+		// CreatedAt
+		if createdAt, exists := ak["createdAt"]; exists {
+			if createdAtStr, ok := createdAt.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".createdAt", createdAt)
+			} else {
+				if createdAtTime, err := time.Parse(time.RFC3339, createdAtStr); err != nil {
+					return fmt.Errorf("Invalid time format: path=%s", path+".createdAt")
+				} else {
+					k.CreatedAt = createdAtTime
+				}
+			}
+		}
+
+		if err := k.Create(GetDB()); err != nil {
+			return err
+		}
+
+		// Role on the organization ("none" grants nothing)
+		role := "owner"
+		if r, exists := ak["role"]; exists {
+			if rStr, ok := r.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".role", r)
+			} else {
+				role = rStr
+			}
+		}
+		if role != "none" {
+			b := &models.RoleBinding{OrganizationID: organization.ID, APIKeyID: k.ID, Role: role}
+			if err := b.Create(GetDB()); err != nil {
+				return err
+			}
+		}
+
+		// roleBindings
+		if roleBindings, exists := ak["roleBindings"]; exists {
+			if bs, ok := roleBindings.([]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".roleBindings", roleBindings)
+			} else {
+				for i, b := range bs {
+					if err := createRoleBinding(b, fmt.Sprintf("%s.roleBindings[%d]", path, i), k); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func createRoleBinding(roleBinding interface{}, path string, apiKey *models.APIKey) error {
+	if rb, ok := roleBinding.(map[string]interface{}); !ok {
+		return fmt.Errorf("Invalid type: path=%s, type=%T", path, roleBinding)
+	} else {
+		b := &models.RoleBinding{}
+
+		// ID
+		if id, exists := rb["id"]; exists {
+			if idStr, ok := id.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".id", id)
+			} else {
+				b.ID = models.UUID(GetUUID(idStr))
+			}
+		} else {
+			b.ID = models.UUID(uuid.New())
+		}
+
+		// OrganizationID, APIKeyID
+		b.OrganizationID = apiKey.OrganizationID
+		b.APIKeyID = apiKey.ID
+
+		// ResourceID
+		if resourceID, exists := rb["resourceId"]; exists {
+			if idStr, ok := resourceID.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".resourceId", resourceID)
+			} else {
+				id := models.UUID(GetUUID(idStr))
+				b.ResourceID = &id
+			}
+		}
+
+		// Role
+		if role, exists := rb["role"]; exists {
+			if roleStr, ok := role.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".role", role)
+			} else {
+				b.Role = roleStr
+			}
+		} else {
+			return fmt.Errorf("Role is required: path=%s", path+".role")
+		}
+
+		if err := b.Create(GetDB()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createWebhookDelivery(delivery interface{}, path string, webhook *models.Webhook) error {
+	if dl, ok := delivery.(map[string]interface{}); !ok {
+		return fmt.Errorf("Invalid type: path=%s, type=%T", path, delivery)
+	} else {
+		d := &models.WebhookDelivery{}
+
+		// ID
+		if id, exists := dl["id"]; exists {
+			if idStr, ok := id.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".id", id)
+			} else {
+				d.ID = models.UUID(GetUUID(idStr))
+			}
+		} else {
+			d.ID = models.UUID(uuid.New())
+		}
+
+		// WebhookID, EventID
+		d.WebhookID = webhook.ID
+		d.EventID = models.UUID(uuid.New())
+
+		// EventType, Status
+		for key, dest := range map[string]*string{"eventType": &d.EventType, "status": &d.Status} {
+			if val, exists := dl[key]; exists {
+				if v, ok := val.(string); !ok {
+					return fmt.Errorf("Invalid type: path=%s, type=%T", path+"."+key, val)
+				} else {
+					*dest = v
+				}
+			}
+		}
+
+		// Attempts
+		if attempts, exists := dl["attempts"]; exists {
+			if a, ok := attempts.(float64); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".attempts", attempts)
+			} else {
+				d.Attempts = int(a)
+			}
+		}
+
+		// Payload
+		payload := map[string]interface{}{}
+		if p, exists := dl["payload"]; exists {
+			if ps, ok := p.(map[string]interface{}); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".payload", p)
+			} else {
+				payload = ps
+			}
+		}
+		b, err := json.Marshal(&payload)
+		if err != nil {
+			return err
+		}
+		d.Payload = b
+
+		// CreatedAt
+		if createdAt, exists := dl["createdAt"]; exists {
+			if createdAtStr, ok := createdAt.(string); !ok {
+				return fmt.Errorf("Invalid type: path=%s, type=%T", path+".createdAt", createdAt)
+			} else {
+				if createdAtTime, err := time.Parse(time.RFC3339, createdAtStr); err != nil {
+					return fmt.Errorf("Invalid time format: path=%s", path+".createdAt")
+				} else {
+					d.CreatedAt = createdAtTime
+				}
+			}
+		}
+
+		if err := d.Create(GetDB()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/databases"
 )
 
 func ServeGet(router http.Handler, path string, query url.Values) map[string]interface{} {
@@ -56,10 +57,15 @@ type APITestCase struct {
 	Query      url.Values
 	Header     http.Header
 	Body       map[string]interface{}
+	RawBody    []byte
 	StatusCode int
 	Output     interface{}
+	OutputXLSX map[string][][]interface{}
 	PostCheck  func(*APITestCase, http.Handler, map[string]interface{})
 	Context    map[string]interface{}
+	Config     *api.Config
+	// Replicas are the read replicas of the db the router uses (see databases.DBConfig)
+	Replicas []databases.DBConfig
 }
 
 func RunTestCase(t *testing.T, tc APITestCase) {
@@ -94,6 +100,8 @@ func RunTestCase(t *testing.T, tc APITestCase) {
 			t.Fatalf("[%s] %+v", tc.Title, err)
 		}
 		body = bytes.NewReader(b)
+	} else if tc.RawBody != nil {
+		body = bytes.NewReader(tc.RawBody)
 	}
 	req, err := http.NewRequest(tc.Method, url.String(), body)
 	if err != nil {
@@ -102,9 +110,20 @@ func RunTestCase(t *testing.T, tc APITestCase) {
 	req.Header = tc.Header
 
 	// Serve request
+	routerDB := GetDB()
+	if len(tc.Replicas) > 0 {
+		dbConfig := makeDBConfig()
+		dbConfig.Replicas = tc.Replicas
+		routerDB, err = databases.Open(dbConfig, db.Logger)
+		if err != nil {
+			t.Fatalf("[%s] %+v", tc.Title, err)
+		}
+		defer databases.Close(routerDB)
+	}
 	r := httptest.NewRecorder()
 	router := api.CreateRouter(
-		GetDB(),
+		routerDB,
+		tc.Config,
 	)
 	router.ServeHTTP(r, req)
 
@@ -115,7 +134,11 @@ func RunTestCase(t *testing.T, tc APITestCase) {
 
 	// Check output
 	var result map[string]interface{}
-	if tc.Output != nil {
+	if tc.OutputXLSX != nil {
+		if diff := CompareJson(tc.OutputXLSX, ReadXLSX(r.Body.Bytes())); diff != "" {
+			t.Errorf("[%s] Response mismatch:\n%s", tc.Title, diff)
+		}
+	} else if tc.Output != nil {
 		err = json.Unmarshal(r.Body.Bytes(), &result)
 		if err != nil {
 			t.Fatalf("[%s] %+v", tc.Title, err)
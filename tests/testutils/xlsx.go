@@ -0,0 +1,67 @@
+package testutils
+
+import (
+	"bytes"
+	"log"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func ReadXLSX(b []byte) map[string][][]interface{} {
+	f, err := excelize.OpenReader(bytes.NewReader(b))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	sheets := map[string][][]interface{}{}
+	for _, name := range f.GetSheetList() {
+		rows, err := f.GetRows(name, excelize.Options{RawCellValue: true})
+		if err != nil {
+			log.Fatal(err)
+		}
+		var records [][]interface{}
+		for _, row := range rows {
+			var record []interface{}
+			for _, cell := range row {
+				record = append(record, cell)
+			}
+			records = append(records, record)
+		}
+		sheets[name] = records
+	}
+	return sheets
+}
+
+// XLSXUntypedCell is a cell value written without the cell type, as numbers are by spreadsheet applications.
+type XLSXUntypedCell string
+
+func MakeXLSX(sheet string, rows [][]interface{}) []byte {
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", sheet)
+	for i, row := range rows {
+		axis, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := f.SetSheetRow(sheet, axis, &row); err != nil {
+			log.Fatal(err)
+		}
+		for j, v := range row {
+			if u, ok := v.(XLSXUntypedCell); ok {
+				axis, err := excelize.CoordinatesToCellName(j+1, i+1)
+				if err != nil {
+					log.Fatal(err)
+				}
+				if err := f.SetCellDefault(sheet, axis, string(u)); err != nil {
+					log.Fatal(err)
+				}
+			}
+		}
+	}
+	b, err := f.WriteToBuffer()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return b.Bytes()
+}
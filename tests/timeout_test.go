@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/api"
+	"github.com/tsujio/x-base/api/timeout"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestTimeout(t *testing.T) {
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		`)
+	}
+	selectQuery := makeJSON(`
+	select:
+	  columns:
+	    - metadata: id
+	`, nil)
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "Route timeout",
+			Prepare: prepare,
+			Config: &api.Config{
+				Timeout: timeout.Config{
+					Routes: map[string]time.Duration{
+						"/tables/{tableID}/query": time.Nanosecond,
+					},
+				},
+			},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Body:       selectQuery,
+			StatusCode: http.StatusGatewayTimeout,
+			Output: map[string]interface{}{
+				"message": "Request timed out",
+			},
+		},
+		{
+			Title:   "Other routes",
+			Prepare: prepare,
+			Config: &api.Config{
+				Timeout: timeout.Config{
+					Routes: map[string]time.Duration{
+						"/tables/{tableID}/query": time.Nanosecond,
+					},
+				},
+			},
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+		},
+		{
+			Title:   "Default timeout",
+			Prepare: prepare,
+			Config: &api.Config{
+				Timeout: timeout.Config{
+					Default: time.Nanosecond,
+				},
+			},
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/tables/%s", testutils.GetUUID("table-01")),
+			StatusCode: http.StatusGatewayTimeout,
+			Output: map[string]interface{}{
+				"message": "Request timed out",
+			},
+		},
+		{
+			Title:   "No timeout",
+			Prepare: prepare,
+			Config: &api.Config{
+				Timeout: timeout.Config{
+					Default: time.Nanosecond,
+					Routes: map[string]time.Duration{
+						"/tables/{tableID}/query": 0,
+					},
+				},
+			},
+			Method:     http.MethodPost,
+			Path:       fmt.Sprintf("/tables/%s/query", testutils.GetUUID("table-01")),
+			Body:       selectQuery,
+			StatusCode: http.StatusOK,
+			Output:     testutils.AnyVal{},
+		},
+	}
+
+	for _, tc := range testCases {
+		testutils.RunTestCase(t, tc)
+	}
+}
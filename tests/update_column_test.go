@@ -52,6 +52,7 @@ func TestUpdateColumn(t *testing.T) {
 			Output: map[string]interface{}{
 				"id":         testutils.GetUUID("column-02"),
 				"tableId":    testutils.GetUUID("table-01"),
+				"type":       nil,
 				"index":      float64(2),
 				"properties": map[string]interface{}{},
 				"createdAt":  testutils.Timestamp{},
@@ -168,6 +169,7 @@ func TestUpdateColumn(t *testing.T) {
 			Output: map[string]interface{}{
 				"id":      testutils.GetUUID("column-01"),
 				"tableId": testutils.GetUUID("table-01"),
+				"type":    nil,
 				"index":   float64(0),
 				"properties": map[string]interface{}{
 					"key1": "new-key",
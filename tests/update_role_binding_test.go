@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestUpdateRoleBinding(t *testing.T) {
+	makePath := func(organizationID, roleBindingID uuid.UUID) string {
+		return fmt.Sprintf("/organizations/%s/role-bindings/%s", organizationID, roleBindingID)
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		    apiKeys:
+		      - id: key-01
+		        key: xb_key1aaaaaaaaaaaa
+		        role: none
+		        roleBindings:
+		          - id: binding-01
+		            resourceId: table-01
+		            role: viewer
+		  - id: org2
+		`)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "General case",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1"), testutils.GetUUID("binding-01")),
+			Body: map[string]interface{}{
+				"role": "editor",
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.GetUUID("binding-01"),
+				"organizationId": testutils.GetUUID("org1"),
+				"apiKeyId":       testutils.GetUUID("key-01"),
+				"resourceId":     testutils.GetUUID("table-01"),
+				"role":           "editor",
+				"createdAt":      testutils.Timestamp{},
+				"updatedAt":      testutils.Timestamp{},
+			},
+		},
+		{
+			Title:   "Another organization",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org2"), testutils.GetUUID("binding-01")),
+			Body: map[string]interface{}{
+				"role": "editor",
+			},
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+		{
+			Title:   "Invalid role",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("org1"), testutils.GetUUID("binding-01")),
+			Body: map[string]interface{}{
+				"role": "admin",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid request body`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPatch
+		testutils.RunTestCase(t, tc)
+	}
+}
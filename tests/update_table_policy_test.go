@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestUpdateTablePolicy(t *testing.T) {
+	makePath := func(tableID, policyID uuid.UUID) string {
+		return fmt.Sprintf("/tables/%s/policies/%s", tableID, policyID)
+	}
+	prepare := func(tc *testutils.APITestCase, db *gorm.DB) error {
+		return testutils.LoadFixture(`
+		organizations:
+		  - id: org1
+		    tables:
+		      - id: table-01
+		        columns:
+		          - id: column-01
+		        policies:
+		          - id: policy-01
+		            where: {value: true}
+		            properties: {name: policy1}
+		      - id: table-02
+		`)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title:   "General case",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("table-01"), testutils.GetUUID("policy-01")),
+			Body: map[string]interface{}{
+				"where": makeJSON(`
+				isNull: {column: {{ .column }} }
+				`, map[string]interface{}{
+					"column": testutils.GetUUID("column-01"),
+				}),
+				"properties": map[string]interface{}{
+					"note": "updated",
+				},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":      testutils.GetUUID("policy-01"),
+				"tableId": testutils.GetUUID("table-01"),
+				"where": map[string]interface{}{
+					"isNull": map[string]interface{}{"column": testutils.GetUUID("column-01").String()},
+				},
+				"properties": map[string]interface{}{
+					"name": "policy1",
+					"note": "updated",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+		},
+		{
+			Title:   "Invalid where",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("table-01"), testutils.GetUUID("policy-01")),
+			Body: map[string]interface{}{
+				"where": map[string]interface{}{"column": uuid.New()},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": testutils.Regexp{Pattern: `^Invalid where`},
+			},
+		},
+		{
+			Title:   "Policy of another table",
+			Prepare: prepare,
+			Path:    makePath(testutils.GetUUID("table-02"), testutils.GetUUID("policy-01")),
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{},
+			},
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Policy not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPatch
+		testutils.RunTestCase(t, tc)
+	}
+}
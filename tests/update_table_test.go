@@ -188,6 +188,7 @@ func TestUpdateTable(t *testing.T) {
 					map[string]interface{}{
 						"id":         testutils.GetUUID("column-01"),
 						"tableId":    testutils.GetUUID("table-01"),
+						"type":       nil,
 						"index":      float64(0),
 						"properties": map[string]interface{}{},
 						"createdAt":  testutils.Timestamp{},
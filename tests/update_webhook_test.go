@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+	"github.com/tsujio/x-base/tests/testutils"
+)
+
+func TestUpdateWebhook(t *testing.T) {
+	makePath := func(id uuid.UUID) string {
+		return fmt.Sprintf("/webhooks/%s", id)
+	}
+
+	testCases := []testutils.APITestCase{
+		{
+			Title: "General case",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				        url: https://example.com/hook
+				        secret: my-secret
+				        events:
+				          - record.inserted
+				        properties:
+				          key1: value1
+				          key2: value2
+				`)
+			},
+			Path: makePath(testutils.GetUUID("webhook-01")),
+			Body: map[string]interface{}{
+				"url":    "https://example.com/hook2",
+				"secret": "new-secret",
+				"events": []string{"table.*"},
+				"properties": map[string]interface{}{
+					"key1": nil,
+					"key3": "value3",
+				},
+			},
+			StatusCode: http.StatusOK,
+			Output: map[string]interface{}{
+				"id":             testutils.GetUUID("webhook-01"),
+				"organizationId": testutils.GetUUID("org1"),
+				"tableId":        nil,
+				"url":            "https://example.com/hook2",
+				"events":         []interface{}{"table.*"},
+				"properties": map[string]interface{}{
+					"key2": "value2",
+					"key3": "value3",
+				},
+				"createdAt": testutils.Timestamp{},
+				"updatedAt": testutils.Timestamp{},
+			},
+			PostCheck: func(tc *testutils.APITestCase, router http.Handler, output map[string]interface{}) {
+				// Reacquire and compare with the previous response
+				res := testutils.ServeGet(router, makePath(testutils.GetUUID("webhook-01")), nil)
+				if diff := testutils.CompareJson(output, res); diff != "" {
+					t.Errorf("[%s] Reacquired response mismatch:\n%s", tc.Title, diff)
+				}
+			},
+		},
+		{
+			Title: "Invalid event type",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				`)
+			},
+			Path: makePath(testutils.GetUUID("webhook-01")),
+			Body: map[string]interface{}{
+				"events": []string{"records.*"},
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Invalid event type: records.*",
+			},
+		},
+		{
+			Title: "Private address",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				`)
+			},
+			Path: makePath(testutils.GetUUID("webhook-01")),
+			Body: map[string]interface{}{
+				"url": "http://192.168.0.1/hook",
+			},
+			StatusCode: http.StatusBadRequest,
+			Output: map[string]interface{}{
+				"message": "Url host is not allowed",
+			},
+		},
+		{
+			Title: "Not found",
+			Prepare: func(tc *testutils.APITestCase, db *gorm.DB) error {
+				return testutils.LoadFixture(`
+				organizations:
+				  - id: org1
+				    webhooks:
+				      - id: webhook-01
+				`)
+			},
+			Path:       makePath(testutils.GetUUID("webhook-02")),
+			Body:       map[string]interface{}{},
+			StatusCode: http.StatusNotFound,
+			Output: map[string]interface{}{
+				"message": "Not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.Method = http.MethodPatch
+		testutils.RunTestCase(t, tc)
+	}
+}